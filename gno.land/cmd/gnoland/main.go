@@ -199,7 +199,10 @@ func makeGenesisDoc(
 	}
 
 	// Load distribution.
-	balances := loadGenesisBalances(genesisBalancesFile)
+	balances, err := gnoland.LoadGenesisBalancesFile(genesisBalancesFile)
+	if err != nil {
+		panic(err)
+	}
 	// debug: for _, balance := range balances { fmt.Println(balance) }
 
 	// Load initial packages from examples.
@@ -287,30 +290,3 @@ func loadGenesisTxs(
 
 	return txs
 }
-
-func loadGenesisBalances(path string) []string {
-	// each balance is in the form: g1xxxxxxxxxxxxxxxx=100000ugnot
-	balances := []string{}
-	content := osm.MustReadFile(path)
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// remove comments.
-		line = strings.Split(line, "#")[0]
-		line = strings.TrimSpace(line)
-
-		// skip empty lines.
-		if line == "" {
-			continue
-		}
-
-		parts := strings.Split(line, "=")
-		if len(parts) != 2 {
-			panic("invalid genesis_balance line: " + line)
-		}
-
-		balances = append(balances, line)
-	}
-	return balances
-}
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -15,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gnolang/gno/gnovm/pkg/doc"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
@@ -315,6 +317,10 @@ func handlerPackageFile(app gotuna.App) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		pkgpath := "gno.land/p/" + vars["filepath"]
+		if strings.HasSuffix(pkgpath, "$doc") {
+			renderPackageDoc(app, w, r, strings.TrimSuffix(strings.TrimSuffix(pkgpath, "$doc"), "/"))
+			return
+		}
 		diruri, filename := std.SplitFilepath(pkgpath)
 		if filename == "" && diruri == pkgpath {
 			// redirect to diruri + "/"
@@ -362,6 +368,36 @@ func renderPackageFile(app gotuna.App, w http.ResponseWriter, r *http.Request, d
 	}
 }
 
+func renderPackageDoc(app gotuna.App, w http.ResponseWriter, r *http.Request, pkgpath string) {
+	mpkg, err := doc.FetchMemPackage(pkgpath, func(path string) (string, error) {
+		res, err := makeRequest(qFileStr, []byte(path))
+		if err != nil {
+			return "", err
+		}
+		return string(res.Data), nil
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	dpkg, err := doc.New(mpkg)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := dpkg.WriteText(&buf, ""); err != nil {
+		writeError(w, err)
+		return
+	}
+	// Render template.
+	tmpl := app.NewTemplatingEngine()
+	tmpl.Set("DirURI", pkgpath)
+	tmpl.Set("DirPath", pathOf(pkgpath))
+	tmpl.Set("Doc", buf.String())
+	tmpl.Render(w, r, "package_doc.html", "funcs.html")
+}
+
 func makeRequest(qpath string, data []byte) (res *abci.ResponseQuery, err error) {
 	opts2 := client.ABCIQueryOptions{
 		// Height: height, XXX
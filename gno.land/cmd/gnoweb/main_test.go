@@ -32,6 +32,7 @@ func TestRoutes(t *testing.T) {
 		{"/r/demo/deep/very/deep?help", ok, "exposed"},
 		{"/r/demo/deep/very/deep/", ok, "render.gno"},
 		{"/r/demo/deep/very/deep/render.gno", ok, "func Render("},
+		{"/p/demo/avl$doc", ok, "func NewTree() *Tree"},
 	}
 	if wd, err := os.Getwd(); err == nil {
 		if strings.HasSuffix(wd, "cmd/gnoweb") {
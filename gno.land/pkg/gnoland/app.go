@@ -20,10 +20,34 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
 )
 
+// AppOptions are the options of NewAppWithOptions.
+type AppOptions struct {
+	DB         dbm.DB // the main DB of the app.
+	StdlibsDir string // the directory of the standard libraries of the VM.
+	Logger     log.Logger
+
+	// SkipFailingGenesisTxs ignores the genesis txs that fail, instead of
+	// panicking.
+	SkipFailingGenesisTxs bool
+	// GenesisTxHandler, if set, is called with the result of each genesis
+	// tx, instead of the results being printed. The genesis txs that fail
+	// are then always ignored.
+	GenesisTxHandler func(tx std.Tx, res sdk.Result)
+}
+
 // NewApp creates the GnoLand application.
 func NewApp(rootDir string, skipFailingGenesisTxs bool, logger log.Logger) (abci.Application, error) {
-	// Get main DB.
-	db := dbm.NewDB("gnolang", dbm.GoLevelDBBackend, filepath.Join(rootDir, "data"))
+	return NewAppWithOptions(AppOptions{
+		DB:                    dbm.NewDB("gnolang", dbm.GoLevelDBBackend, filepath.Join(rootDir, "data")),
+		StdlibsDir:            filepath.Join("..", "gnovm", "stdlibs"),
+		Logger:                logger,
+		SkipFailingGenesisTxs: skipFailingGenesisTxs,
+	})
+}
+
+// NewAppWithOptions creates the GnoLand application with opts.
+func NewAppWithOptions(opts AppOptions) (abci.Application, error) {
+	db, logger := opts.DB, opts.Logger
 
 	// Capabilities keys.
 	mainKey := store.NewStoreKey("main")
@@ -39,12 +63,15 @@ func NewApp(rootDir string, skipFailingGenesisTxs bool, logger log.Logger) (abci
 
 	// Construct keepers.
 	acctKpr := auth.NewAccountKeeper(mainKey, ProtoGnoAccount)
-	bankKpr := bank.NewBankKeeper(acctKpr)
-	stdlibsDir := filepath.Join("..", "gnovm", "stdlibs")
-	vmKpr := vm.NewVMKeeper(baseKey, mainKey, acctKpr, bankKpr, stdlibsDir)
+	bankKpr := bank.NewBankKeeper(mainKey, acctKpr)
+	vmKpr := vm.NewVMKeeper(baseKey, mainKey, acctKpr, bankKpr, opts.StdlibsDir)
 
 	// Set InitChainer
-	baseApp.SetInitChainer(InitChainer(baseApp, acctKpr, bankKpr, skipFailingGenesisTxs))
+	txHandler := opts.GenesisTxHandler
+	if txHandler == nil {
+		txHandler = printGenesisTxResult(opts.SkipFailingGenesisTxs)
+	}
+	baseApp.SetInitChainer(initChainer(baseApp, acctKpr, bankKpr, vmKpr, txHandler))
 
 	// Set AnteHandler
 	authOptions := auth.AnteOptions{
@@ -86,10 +113,20 @@ func NewApp(rootDir string, skipFailingGenesisTxs bool, logger log.Logger) (abci
 }
 
 // InitChainer returns a function that can initialize the chain with genesis.
-func InitChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank.BankKeeperI, skipFailingGenesisTxs bool) func(sdk.Context, abci.RequestInitChain) abci.ResponseInitChain {
+func InitChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank.BankKeeperI, vmKpr *vm.VMKeeper, skipFailingGenesisTxs bool) func(sdk.Context, abci.RequestInitChain) abci.ResponseInitChain {
+	return initChainer(baseApp, acctKpr, bankKpr, vmKpr, printGenesisTxResult(skipFailingGenesisTxs))
+}
+
+func initChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank.BankKeeperI, vmKpr *vm.VMKeeper, txHandler func(std.Tx, sdk.Result)) func(sdk.Context, abci.RequestInitChain) abci.ResponseInitChain {
 	return func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
 		// Get genesis state.
 		genState := req.AppState.(GnoGenesisState)
+		// Set the vm params, read from the state by the vm keeper.
+		vmParams := vm.DefaultParams()
+		if genState.VMParams != nil {
+			vmParams = *genState.VMParams
+		}
+		vmKpr.SetParams(ctx, vmParams)
 		// Parse and set genesis state balances.
 		for _, bal := range genState.Balances {
 			addr, coins := parseBalance(bal)
@@ -101,18 +138,9 @@ func InitChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank
 			}
 		}
 		// Run genesis txs.
-		for i, tx := range genState.Txs {
+		for _, tx := range genState.Txs {
 			res := baseApp.Deliver(tx)
-			if res.IsErr() {
-				fmt.Println("ERROR LOG:", res.Log)
-				fmt.Println("#", i, string(amino.MustMarshalJSON(tx)))
-				// NOTE: comment out to ignore.
-				if !skipFailingGenesisTxs {
-					panic(res.Error)
-				}
-			} else {
-				fmt.Println("SUCCESS:", string(amino.MustMarshalJSON(tx)))
-			}
+			txHandler(tx, res)
 		}
 		// Done!
 		return abci.ResponseInitChain{
@@ -121,6 +149,25 @@ func InitChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank
 	}
 }
 
+// printGenesisTxResult returns the default genesis tx handler, which prints
+// the results, and panics on failures unless skipFailingGenesisTxs.
+func printGenesisTxResult(skipFailingGenesisTxs bool) func(std.Tx, sdk.Result) {
+	i := 0
+	return func(tx std.Tx, res sdk.Result) {
+		if res.IsErr() {
+			fmt.Println("ERROR LOG:", res.Log)
+			fmt.Println("#", i, string(amino.MustMarshalJSON(tx)))
+			// NOTE: comment out to ignore.
+			if !skipFailingGenesisTxs {
+				panic(res.Error)
+			}
+		} else {
+			fmt.Println("SUCCESS:", string(amino.MustMarshalJSON(tx)))
+		}
+		i++
+	}
+}
+
 func parseBalance(bal string) (crypto.Address, std.Coins) {
 	parts := strings.Split(bal, "=")
 	if len(parts) != 2 {
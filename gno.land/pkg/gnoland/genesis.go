@@ -0,0 +1,39 @@
+package gnoland
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadGenesisBalancesFile reads the balances of a genesis balances file,
+// where each line is a balance in the form g1xxxxxxxxxxxxxxxx=100000ugnot,
+// and # starts a comment.
+func LoadGenesisBalancesFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	balances := []string{}
+	lines := strings.Split(string(content), "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		// remove comments.
+		line = strings.Split(line, "#")[0]
+		line = strings.TrimSpace(line)
+
+		// skip empty lines.
+		if line == "" {
+			continue
+		}
+
+		parts := strings.Split(line, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid genesis_balance line: %s", line)
+		}
+
+		balances = append(balances, line)
+	}
+	return balances, nil
+}
@@ -1,6 +1,7 @@
 package gnoland
 
 import (
+	"github.com/gnolang/gno/tm2/pkg/sdk/vm"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
@@ -13,6 +14,7 @@ func ProtoGnoAccount() std.Account {
 }
 
 type GnoGenesisState struct {
-	Balances []string `json:"balances"`
-	Txs      []std.Tx `json:"txs"`
+	Balances []string   `json:"balances"`
+	Txs      []std.Tx   `json:"txs"`
+	VMParams *vm.Params `json:"vm_params,omitempty"` // or the default params.
 }
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/tests"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+type debugCfg struct {
+	rootDir string
+	listen  string
+}
+
+func newDebugCmd(io *commands.IO) *commands.Command {
+	cfg := &debugCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "debug",
+			ShortUsage: "debug [flags] <file> [<file>...]",
+			ShortHelp:  "Runs the specified gno files in a debugger",
+			LongHelp: `Runs the main function of the specified gno files, stopped before its first
+statement, and reads debugger commands from the standard input; type "help"
+for the list of commands.
+
+With -listen, the debugger is instead a Debug Adapter Protocol server, which
+editors attach to, and which serves a single debugging session.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execDebug(cfg, args, io)
+		},
+	)
+}
+
+func (c *debugCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rootDir,
+		"root-dir",
+		"",
+		"clone location of github.com/gnolang/gno (gnodev tries to guess it)",
+	)
+
+	fs.StringVar(
+		&c.listen,
+		"listen",
+		"",
+		"serve the Debug Adapter Protocol at this address (e.g. localhost:2345)",
+	)
+}
+
+// debugQuit is panicked by the debugger to abort the execution.
+type debugQuit struct{}
+
+// A debugProgram is the main package of the debugged files.
+type debugProgram struct {
+	m       *gno.Machine
+	sources map[string]string // of the files, by name.
+}
+
+func newDebugProgram(cfg *debugCfg, args []string, io *commands.IO) (*debugProgram, error) {
+	testStore := tests.TestStore(cfg.rootDir,
+		"", io.In, io.Out, io.Err,
+		tests.ImportModeStdlibsPreferred)
+
+	prog := &debugProgram{sources: make(map[string]string)}
+	files := make([]*gno.FileNode, len(args))
+	for i, fname := range args {
+		bz, err := os.ReadFile(fname)
+		if err != nil {
+			return nil, err
+		}
+		files[i], err = gno.ParseFile(fname, string(bz))
+		if err != nil {
+			return nil, err
+		}
+		prog.sources[fname] = string(bz)
+	}
+
+	prog.m = gno.NewMachineWithOptions(gno.MachineOptions{
+		PkgPath: "main",
+		Output:  io.Out,
+		Store:   testStore,
+	})
+	prog.m.RunFiles(files...)
+	return prog, nil
+}
+
+// run runs the main function with the debugger d, and returns whether it
+// completed, i.e. was not aborted.
+func (prog *debugProgram) run(d *gno.Debugger) (completed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(debugQuit); !ok {
+				panic(r)
+			}
+		}
+	}()
+	prog.m.Debugger = d
+	prog.m.RunStatement(gno.S(gno.Call(gno.X("main"))))
+	return true
+}
+
+// source returns the lines of the file of loc, or nil if not found.
+func (prog *debugProgram) source(loc gno.Location) []string {
+	body, ok := prog.sources[loc.File]
+	if !ok || loc.PkgPath != "main" {
+		mfile := prog.m.Store.GetMemFile(loc.PkgPath, loc.File)
+		if mfile == nil {
+			return nil
+		}
+		body = mfile.Body
+	}
+	return strings.Split(body, "\n")
+}
+
+func execDebug(cfg *debugCfg, args []string, io *commands.IO) error {
+	if len(args) == 0 {
+		return flag.ErrHelp
+	}
+
+	if cfg.rootDir == "" {
+		cfg.rootDir = guessRootDir()
+	}
+
+	if cfg.listen != "" {
+		return serveDAP(cfg, args, io)
+	}
+
+	prog, err := newDebugProgram(cfg, args, io)
+	if err != nil {
+		return err
+	}
+	defer prog.m.Release()
+
+	cli := &debugCLI{
+		io:   io,
+		prog: prog,
+		in:   bufio.NewScanner(io.In),
+	}
+	cli.d = gno.NewDebugger(gno.DebugStep, cli.stop)
+	if prog.run(cli.d) {
+		io.ErrPrintln("Process exited.")
+	}
+	return nil
+}
+
+// debugCLI is the command line interface of gno debug.
+type debugCLI struct {
+	io   *commands.IO
+	prog *debugProgram
+	in   *bufio.Scanner
+	d    *gno.Debugger
+
+	// when stopped.
+	m     *gno.Machine
+	loc   gno.Location
+	stack []gno.DebugFrame
+	frame int // selected frame of the stack.
+}
+
+const debugHelp = `Commands:
+    break, b [<file>:]<line>   set a breakpoint (in the current file by default)
+    clear [<file>:]<line>      clear a breakpoint
+    clearall                   clear all breakpoints
+    breakpoints, bp            list the breakpoints
+    continue, c                run until a breakpoint
+    next, n                    step over to the next line
+    step, s                    step into the next line
+    stepout, so                step out of the current function
+    stack, bt                  print the stack of function calls
+    frame <n>                  select the frame n of the stack
+    locals                     print the local variables of the frame
+    globals                    print the package variables of the frame
+    print, p <expr>            print a variable, field or element, e.g. p.X or xs[0]
+    list, l                    print the source around the current line
+    help, h                    print this help
+    quit, q                    abort the program`
+
+// stop is the Debugger.OnStop of the CLI: it reads and runs commands
+// until one resumes the execution.
+func (cli *debugCLI) stop(m *gno.Machine, loc gno.Location) gno.DebugAction {
+	cli.m, cli.loc = m, loc
+	cli.stack = cli.d.Stack(m, loc)
+	cli.frame = 0
+	cli.printLocation(0)
+	for {
+		fmt.Fprint(cli.io.Err, "(gno) ")
+		if !cli.in.Scan() {
+			panic(debugQuit{})
+		}
+		line := strings.TrimSpace(cli.in.Text())
+		if line == "" {
+			continue
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		arg = strings.TrimSpace(arg)
+		switch cmd {
+		case "continue", "c":
+			return gno.DebugContinue
+		case "next", "n":
+			return gno.DebugNext
+		case "step", "s":
+			return gno.DebugStep
+		case "stepout", "so":
+			return gno.DebugStepOut
+		case "quit", "q", "exit":
+			panic(debugQuit{})
+		}
+		if err := cli.command(cmd, arg); err != nil {
+			cli.io.ErrPrintfln("error: %v", err)
+		}
+	}
+}
+
+// command runs a command that does not resume the execution.
+func (cli *debugCLI) command(cmd string, arg string) error {
+	switch cmd {
+	case "break", "b", "clear":
+		file, line, err := cli.parseLocation(arg)
+		if err != nil {
+			return err
+		}
+		if cmd == "clear" {
+			if !cli.d.ClearBreakpoint(file, line) {
+				return fmt.Errorf("no breakpoint at %s:%d", file, line)
+			}
+			cli.io.ErrPrintfln("Breakpoint cleared at %s:%d", file, line)
+			return nil
+		}
+		cli.d.SetBreakpoint(file, line)
+		cli.io.ErrPrintfln("Breakpoint set at %s:%d", file, line)
+	case "clearall":
+		cli.d.ClearBreakpoints("")
+	case "breakpoints", "bp":
+		for _, bp := range cli.d.Breakpoints() {
+			cli.io.ErrPrintfln("%s:%d", bp.File, bp.Line)
+		}
+	case "stack", "bt":
+		for i, df := range cli.stack {
+			mark := " "
+			if i == cli.frame {
+				mark = ">"
+			}
+			cli.io.ErrPrintfln("%s %d  %s() at %s:%d", mark, i, df.Func, df.Loc.File, df.Loc.Line)
+		}
+	case "frame":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 || n >= len(cli.stack) {
+			return fmt.Errorf("invalid frame %q", arg)
+		}
+		cli.frame = n
+		cli.printLocation(n)
+	case "locals", "globals":
+		if len(cli.stack) == 0 {
+			return errors.New("no frame")
+		}
+		df := cli.stack[cli.frame]
+		vars := df.Locals(cli.m.Store)
+		if cmd == "globals" {
+			vars = df.Globals(cli.m.Store)
+		}
+		for _, v := range vars {
+			cli.io.ErrPrintfln("%s = %s", v.Name, gno.DebugValueString(cli.m.Store, v.Value))
+		}
+	case "print", "p":
+		if len(cli.stack) == 0 {
+			return errors.New("no frame")
+		}
+		tv, err := cli.stack[cli.frame].Inspect(cli.m.Store, arg)
+		if err != nil {
+			return err
+		}
+		cli.io.ErrPrintln(gno.DebugValueString(cli.m.Store, tv))
+	case "list", "l":
+		cli.printSource(cli.frameLoc(), 5)
+	case "help", "h":
+		cli.io.ErrPrintln(debugHelp)
+	default:
+		return fmt.Errorf("unknown command %q, see help", cmd)
+	}
+	return nil
+}
+
+// parseLocation parses "[<file>:]<line>".
+func (cli *debugCLI) parseLocation(arg string) (file string, line int, err error) {
+	file = cli.frameLoc().File
+	sline := arg
+	if i := strings.LastIndex(arg, ":"); i >= 0 {
+		file, sline = arg[:i], arg[i+1:]
+	}
+	line, err = strconv.Atoi(sline)
+	if err != nil || line <= 0 || file == "" {
+		return "", 0, fmt.Errorf("invalid location %q, want [<file>:]<line>", arg)
+	}
+	return file, line, nil
+}
+
+func (cli *debugCLI) frameLoc() gno.Location {
+	if cli.frame < len(cli.stack) {
+		return cli.stack[cli.frame].Loc
+	}
+	return cli.loc
+}
+
+func (cli *debugCLI) printLocation(frame int) {
+	loc := cli.loc
+	fn := "?"
+	if frame < len(cli.stack) {
+		loc, fn = cli.stack[frame].Loc, cli.stack[frame].Func
+	}
+	cli.io.ErrPrintfln("> %s() %s:%d", fn, loc.File, loc.Line)
+	cli.printSource(loc, 0)
+}
+
+// printSource prints the lines of the file of loc around its line.
+func (cli *debugCLI) printSource(loc gno.Location, around int) {
+	lines := cli.prog.source(loc)
+	for ln := loc.Line - around; ln <= loc.Line+around; ln++ {
+		if ln < 1 || ln > len(lines) {
+			continue
+		}
+		mark := "  "
+		if ln == loc.Line {
+			mark = "=>"
+		}
+		cli.io.ErrPrintfln("%s %4d:\t%s", mark, ln, lines[ln-1])
+	}
+}
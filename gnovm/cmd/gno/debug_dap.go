@@ -0,0 +1,562 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+// The Debug Adapter Protocol, or DAP, is the protocol of the debuggers of
+// editors: https://microsoft.github.io/debug-adapter-protocol/. Only the
+// requests and events of a single-threaded program are implemented.
+
+func serveDAP(cfg *debugCfg, args []string, io *commands.IO) error {
+	prog, err := newDebugProgram(cfg, args, io)
+	if err != nil {
+		return err
+	}
+	defer prog.m.Release()
+
+	ln, err := net.Listen("tcp", cfg.listen)
+	if err != nil {
+		return err
+	}
+	io.ErrPrintfln("DAP server listening at: %s", ln.Addr())
+	conn, err := ln.Accept()
+	ln.Close()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return newDAPSession(conn, prog, cfg.rootDir).serve()
+}
+
+type dapRequest struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type dapResponse struct {
+	Seq        int         `json:"seq"`
+	Type       string      `json:"type"`
+	RequestSeq int         `json:"request_seq"`
+	Success    bool        `json:"success"`
+	Command    string      `json:"command"`
+	Message    string      `json:"message,omitempty"`
+	Body       interface{} `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int         `json:"seq"`
+	Type  string      `json:"type"`
+	Event string      `json:"event"`
+	Body  interface{} `json:"body,omitempty"`
+}
+
+type dapSource struct {
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// readDAPMessage reads a message: a Content-Length header and its JSON
+// content.
+func readDAPMessage(r *bufio.Reader, v interface{}) error {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(name, "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return errors.New("missing Content-Length")
+	}
+	bz := make([]byte, length)
+	if _, err := io.ReadFull(r, bz); err != nil {
+		return err
+	}
+	return json.Unmarshal(bz, v)
+}
+
+func writeDAPMessage(w io.Writer, v interface{}) error {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(bz), bz)
+	return err
+}
+
+// dapStop is a stop of the machine, which waits for a dapResume.
+type dapStop struct {
+	m   *gno.Machine
+	loc gno.Location
+}
+
+type dapResume struct {
+	action gno.DebugAction
+	quit   bool
+}
+
+// dapSession serves a DAP client. Requests are handled by serve, which also
+// receives the stops of the machine, running on its own goroutine.
+type dapSession struct {
+	conn    io.ReadWriter
+	prog    *debugProgram
+	rootDir string
+	d       *gno.Debugger
+
+	mu  sync.Mutex // for writing messages.
+	seq int
+
+	stops   chan dapStop
+	resumes chan dapResume
+	done    chan bool // completed
+
+	stopOnEntry bool
+	running     bool
+	lastAction  gno.DebugAction
+	stopped     *dapStop
+	stack       []gno.DebugFrame
+	vars        []func() []gno.DebugVar // by variables reference - 1.
+}
+
+func newDAPSession(conn io.ReadWriter, prog *debugProgram, rootDir string) *dapSession {
+	s := &dapSession{
+		conn:    conn,
+		prog:    prog,
+		rootDir: rootDir,
+		stops:   make(chan dapStop),
+		resumes: make(chan dapResume),
+		done:    make(chan bool, 1),
+	}
+	s.d = gno.NewDebugger(gno.DebugContinue, s.stop)
+	prog.m.Output = dapOutput{s}
+	return s
+}
+
+// stop is the Debugger.OnStop of the session.
+func (s *dapSession) stop(m *gno.Machine, loc gno.Location) gno.DebugAction {
+	s.stops <- dapStop{m: m, loc: loc}
+	r := <-s.resumes
+	if r.quit {
+		panic(debugQuit{})
+	}
+	return r.action
+}
+
+func (s *dapSession) send(v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	switch v := v.(type) {
+	case *dapResponse:
+		v.Seq, v.Type = s.seq, "response"
+	case *dapEvent:
+		v.Seq, v.Type = s.seq, "event"
+	}
+	return writeDAPMessage(s.conn, v)
+}
+
+func (s *dapSession) event(event string, body interface{}) error {
+	return s.send(&dapEvent{Event: event, Body: body})
+}
+
+// dapOutput sends the output of the program as events.
+type dapOutput struct {
+	s *dapSession
+}
+
+func (o dapOutput) Write(p []byte) (int, error) {
+	err := o.s.event("output", map[string]interface{}{
+		"category": "stdout",
+		"output":   string(p),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *dapSession) serve() error {
+	reqs := make(chan dapRequest)
+	readErr := make(chan error, 1)
+	go func() {
+		r := bufio.NewReader(s.conn)
+		for {
+			var req dapRequest
+			if err := readDAPMessage(r, &req); err != nil {
+				readErr <- err
+				return
+			}
+			reqs <- req
+		}
+	}()
+
+	for {
+		select {
+		case req := <-reqs:
+			body, err := s.handle(req)
+			res := &dapResponse{
+				RequestSeq: req.Seq,
+				Success:    err == nil,
+				Command:    req.Command,
+				Body:       body,
+			}
+			if err != nil {
+				res.Message = err.Error()
+			}
+			if err := s.send(res); err != nil {
+				return err
+			}
+			if err := s.afterResponse(req.Command); err != nil {
+				if errors.Is(err, errDAPDisconnect) {
+					return nil
+				}
+				return err
+			}
+		case st := <-s.stops:
+			s.running = false
+			s.stopped = &st
+			s.stack = s.d.Stack(st.m, st.loc)
+			reason := "step"
+			switch {
+			case s.lastAction == gno.DebugContinue && s.stopOnEntry:
+				reason = "entry"
+				s.stopOnEntry = false
+			case s.lastAction == gno.DebugContinue:
+				reason = "breakpoint"
+			}
+			err := s.event("stopped", map[string]interface{}{
+				"reason":            reason,
+				"threadId":          1,
+				"allThreadsStopped": true,
+			})
+			if err != nil {
+				return err
+			}
+		case completed := <-s.done:
+			s.running = false
+			exitCode := 0
+			if !completed {
+				exitCode = 1
+			}
+			if err := s.event("exited", map[string]interface{}{"exitCode": exitCode}); err != nil {
+				return err
+			}
+			if err := s.event("terminated", nil); err != nil {
+				return err
+			}
+		case err := <-readErr:
+			s.quit()
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+var errDAPDisconnect = errors.New("disconnect")
+
+// handle handles req, and returns the body of its response.
+func (s *dapSession) handle(req dapRequest) (interface{}, error) {
+	switch req.Command {
+	case "initialize":
+		return map[string]interface{}{
+			"supportsConfigurationDoneRequest": true,
+			"supportsEvaluateForHovers":        true,
+		}, nil
+	case "launch", "attach":
+		var args struct {
+			StopOnEntry bool `json:"stopOnEntry"`
+		}
+		if len(req.Arguments) > 0 {
+			if err := json.Unmarshal(req.Arguments, &args); err != nil {
+				return nil, err
+			}
+		}
+		s.stopOnEntry = args.StopOnEntry
+		return nil, nil
+	case "setBreakpoints":
+		return s.setBreakpoints(req.Arguments)
+	case "configurationDone", "disconnect", "terminate":
+		return nil, nil
+	case "threads":
+		return map[string]interface{}{
+			"threads": []map[string]interface{}{{"id": 1, "name": "main"}},
+		}, nil
+	}
+
+	// requests of a stopped program.
+	if s.stopped == nil {
+		return nil, fmt.Errorf("%s: program is not stopped", req.Command)
+	}
+	switch req.Command {
+	case "continue":
+		return map[string]interface{}{"allThreadsContinued": true}, nil
+	case "next", "stepIn", "stepOut":
+		return nil, nil
+	case "stackTrace":
+		frames := make([]map[string]interface{}, len(s.stack))
+		for i, df := range s.stack {
+			frames[i] = map[string]interface{}{
+				"id":     i + 1,
+				"name":   df.Func,
+				"source": s.source(df.Loc),
+				"line":   df.Loc.Line,
+				"column": 1,
+			}
+		}
+		return map[string]interface{}{
+			"stackFrames": frames,
+			"totalFrames": len(frames),
+		}, nil
+	case "scopes":
+		df, err := s.frame(req.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		store := s.stopped.m.Store
+		locals := s.reference(func() []gno.DebugVar { return df.Locals(store) })
+		globals := s.reference(func() []gno.DebugVar { return df.Globals(store) })
+		return map[string]interface{}{
+			"scopes": []map[string]interface{}{
+				{"name": "Locals", "variablesReference": locals, "expensive": false},
+				{"name": "Globals", "variablesReference": globals, "expensive": false},
+			},
+		}, nil
+	case "variables":
+		var args struct {
+			VariablesReference int `json:"variablesReference"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		ref := args.VariablesReference
+		if ref < 1 || ref > len(s.vars) {
+			return nil, fmt.Errorf("invalid variables reference %d", ref)
+		}
+		vars := []dapVariable{}
+		for _, v := range s.vars[ref-1]() {
+			vars = append(vars, s.variable(v.Name, v.Value))
+		}
+		return map[string]interface{}{"variables": vars}, nil
+	case "evaluate":
+		var args struct {
+			Expression string `json:"expression"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		df, err := s.frame(req.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		tv, err := df.Inspect(s.stopped.m.Store, args.Expression)
+		if err != nil {
+			return nil, err
+		}
+		v := s.variable(args.Expression, tv)
+		return map[string]interface{}{
+			"result":             v.Value,
+			"type":               v.Type,
+			"variablesReference": v.VariablesReference,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported request %q", req.Command)
+	}
+}
+
+// afterResponse runs or resumes the program after the responses that
+// require it, as events must follow the responses.
+func (s *dapSession) afterResponse(command string) error {
+	switch command {
+	case "initialize":
+		return s.event("initialized", nil)
+	case "configurationDone":
+		if s.running || s.stopped != nil {
+			return nil
+		}
+		if s.stopOnEntry {
+			d := gno.NewDebugger(gno.DebugStep, s.stop)
+			for _, bp := range s.d.Breakpoints() {
+				d.SetBreakpoint(bp.File, bp.Line)
+			}
+			s.d = d
+		}
+		s.running = true
+		go func() {
+			completed := false
+			defer func() {
+				if r := recover(); r != nil {
+					s.event("output", map[string]interface{}{
+						"category": "stderr",
+						"output":   fmt.Sprintf("panic: %v\n", r),
+					})
+				}
+				s.done <- completed
+			}()
+			completed = s.prog.run(s.d)
+		}()
+	case "continue", "next", "stepIn", "stepOut":
+		if s.stopped == nil {
+			return nil
+		}
+		s.lastAction = map[string]gno.DebugAction{
+			"continue": gno.DebugContinue,
+			"next":     gno.DebugNext,
+			"stepIn":   gno.DebugStep,
+			"stepOut":  gno.DebugStepOut,
+		}[command]
+		s.resume(dapResume{action: s.lastAction})
+	case "disconnect", "terminate":
+		s.quit()
+		return errDAPDisconnect
+	}
+	return nil
+}
+
+func (s *dapSession) resume(r dapResume) {
+	s.stopped, s.stack, s.vars = nil, nil, nil
+	s.running = true
+	s.resumes <- r
+}
+
+// quit aborts the program, if stopped.
+func (s *dapSession) quit() {
+	if s.stopped != nil {
+		s.resume(dapResume{quit: true})
+	}
+}
+
+// setBreakpoints replaces the breakpoints of a source file.
+func (s *dapSession) setBreakpoints(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Source      dapSource `json:"source"`
+		Breakpoints []struct {
+			Line int `json:"line"`
+		} `json:"breakpoints"`
+	}
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return nil, err
+	}
+	file := s.breakpointFile(args.Source.Path)
+	s.d.ClearBreakpoints(file)
+	bps := []map[string]interface{}{}
+	for _, bp := range args.Breakpoints {
+		s.d.SetBreakpoint(file, bp.Line)
+		bps = append(bps, map[string]interface{}{"verified": true, "line": bp.Line})
+	}
+	return map[string]interface{}{"breakpoints": bps}, nil
+}
+
+// breakpointFile returns the breakpoint file of a path of the client: the
+// name of a file of the program, or else the path of its package.
+func (s *dapSession) breakpointFile(path string) string {
+	abs, _ := filepath.Abs(path)
+	for fname := range s.prog.sources {
+		if fabs, _ := filepath.Abs(fname); fabs == abs {
+			return fname
+		}
+	}
+	for _, dir := range []string{"examples", filepath.Join("gnovm", "stdlibs")} {
+		dir = filepath.Join(s.rootDir, dir) + string(filepath.Separator)
+		if strings.HasPrefix(abs, dir) {
+			return filepath.ToSlash(strings.TrimPrefix(abs, dir))
+		}
+	}
+	return filepath.Base(path)
+}
+
+// source returns the source file of loc, reversing breakpointFile.
+func (s *dapSession) source(loc gno.Location) dapSource {
+	src := dapSource{Name: filepath.Base(loc.File)}
+	if _, ok := s.prog.sources[loc.File]; ok && loc.PkgPath == "main" {
+		src.Path, _ = filepath.Abs(loc.File)
+		return src
+	}
+	for _, dir := range []string{"examples", filepath.Join("gnovm", "stdlibs")} {
+		path := filepath.Join(s.rootDir, dir, filepath.FromSlash(loc.PkgPath), loc.File)
+		if _, err := os.Stat(path); err == nil {
+			src.Path = path
+			break
+		}
+	}
+	return src
+}
+
+// frame returns the stack frame of the frameId of arguments, or the
+// innermost one.
+func (s *dapSession) frame(arguments json.RawMessage) (gno.DebugFrame, error) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return gno.DebugFrame{}, err
+		}
+	}
+	if args.FrameID == 0 && len(s.stack) > 0 {
+		return s.stack[0], nil
+	}
+	if args.FrameID < 1 || args.FrameID > len(s.stack) {
+		return gno.DebugFrame{}, fmt.Errorf("invalid frame %d", args.FrameID)
+	}
+	return s.stack[args.FrameID-1], nil
+}
+
+// reference returns a variables reference for the variables of vars.
+func (s *dapSession) reference(vars func() []gno.DebugVar) int {
+	s.vars = append(s.vars, vars)
+	return len(s.vars)
+}
+
+func (s *dapSession) variable(name string, tv gno.TypedValue) dapVariable {
+	store := s.stopped.m.Store
+	v := dapVariable{
+		Name:  name,
+		Value: gno.DebugValueString(store, tv),
+	}
+	if tv.T != nil {
+		v.Type = tv.T.String()
+	}
+	if len(gno.DebugChildren(store, tv)) > 0 {
+		v.VariablesReference = s.reference(func() []gno.DebugVar {
+			return gno.DebugChildren(store, tv)
+		})
+	}
+	return v
+}
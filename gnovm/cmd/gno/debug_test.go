@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebug(t *testing.T) {
+	tc := []testMainCase{
+		{
+			args:        []string{"debug"},
+			errShouldBe: "flag: help requested",
+		},
+		{
+			args:                []string{"debug", "../../tests/integ/debug/main.gno"},
+			stdin:               "c\n",
+			stdoutShouldBe:      "2\n",
+			stderrShouldContain: "=>   18:\t\tlist := &node{Key: \"a\", Next: &node{Key: \"b\"}}\n(gno) Process exited.\n",
+		},
+		{
+			args:                []string{"debug", "../../tests/integ/debug/main.gno"},
+			stdin:               "b main.gno:12\nc\nbt\np n\nq\n",
+			stderrShouldContain: "> 0  main.length() at ../../tests/integ/debug/main.gno:12\n  1  main.main() at ../../tests/integ/debug/main.gno:19\n(gno) &node{Key: \"a\", Next: &node{Key: \"b\", Next: nil}}\n",
+		},
+		{
+			args:                []string{"debug", "../../tests/integ/debug/main.gno"},
+			stdin:               "n\ns\nlocals\nso\np list.Next.Key\np nope\n",
+			stderrShouldContain: "(gno) n = &node{Key: \"a\", Next: &node{Key: \"b\", Next: nil}}\nl = nil\n(gno) > main.main() ../../tests/integ/debug/main.gno:20\n=>   20:\t\tprintln(l)\n(gno) \"b\"\n(gno) error: name nope not declared\n",
+		},
+	}
+	testMainCaseRun(t, tc)
+}
+
+func TestDebugDAP(t *testing.T) {
+	io := commands.NewTestIO()
+	prog, err := newDebugProgram(&debugCfg{rootDir: guessRootDir()}, []string{"../../tests/integ/debug/main.gno"}, io)
+	require.NoError(t, err)
+	defer prog.m.Release()
+
+	server, client := net.Pipe()
+	defer client.Close()
+	go newDAPSession(server, prog, "").serve()
+
+	seq := 0
+	r := bufio.NewReader(client)
+	// request sends a request, and returns the messages received up to its
+	// response, which is last.
+	request := func(command string, args interface{}) []map[string]interface{} {
+		t.Helper()
+		seq++
+		bz, err := json.Marshal(args)
+		require.NoError(t, err)
+		go writeDAPMessage(client, dapRequest{Seq: seq, Type: "request", Command: command, Arguments: bz})
+		var msgs []map[string]interface{}
+		for {
+			var msg map[string]interface{}
+			require.NoError(t, readDAPMessage(r, &msg))
+			msgs = append(msgs, msg)
+			if msg["type"] == "response" {
+				require.Equal(t, command, msg["command"])
+				require.Equal(t, true, msg["success"], msg["message"])
+				return msgs
+			}
+		}
+	}
+	// event waits for an event, and returns its body.
+	event := func(name string) map[string]interface{} {
+		t.Helper()
+		for {
+			var msg map[string]interface{}
+			require.NoError(t, readDAPMessage(r, &msg))
+			if msg["event"] == name {
+				body, _ := msg["body"].(map[string]interface{})
+				return body
+			}
+		}
+	}
+	body := func(msgs []map[string]interface{}) map[string]interface{} {
+		return msgs[len(msgs)-1]["body"].(map[string]interface{})
+	}
+
+	request("initialize", map[string]interface{}{"adapterID": "gno"})
+	event("initialized")
+	request("launch", map[string]interface{}{})
+	bps := request("setBreakpoints", map[string]interface{}{
+		"source":      map[string]interface{}{"path": "../../tests/integ/debug/main.gno"},
+		"breakpoints": []map[string]interface{}{{"line": 12}},
+	})
+	require.Len(t, body(bps)["breakpoints"], 1)
+	request("configurationDone", nil)
+	require.Equal(t, "breakpoint", event("stopped")["reason"])
+
+	frames := body(request("stackTrace", map[string]interface{}{"threadId": 1}))["stackFrames"].([]interface{})
+	require.Len(t, frames, 2)
+	top := frames[0].(map[string]interface{})
+	require.Equal(t, "main.length", top["name"])
+	require.Equal(t, float64(12), top["line"])
+
+	scopes := body(request("scopes", map[string]interface{}{"frameId": top["id"]}))["scopes"].([]interface{})
+	locals := scopes[0].(map[string]interface{})
+	require.Equal(t, "Locals", locals["name"])
+	vars := body(request("variables", map[string]interface{}{"variablesReference": locals["variablesReference"]}))["variables"].([]interface{})
+	require.Len(t, vars, 2)
+	n := vars[0].(map[string]interface{})
+	require.Equal(t, "n", n["name"])
+	require.Equal(t, "*main.node", n["type"])
+	require.NotZero(t, n["variablesReference"])
+
+	res := body(request("evaluate", map[string]interface{}{"expression": "n.Next.Key", "frameId": top["id"]}))
+	require.Equal(t, `"b"`, res["result"])
+
+	request("setBreakpoints", map[string]interface{}{
+		"source":      map[string]interface{}{"path": "../../tests/integ/debug/main.gno"},
+		"breakpoints": []map[string]interface{}{},
+	})
+	request("continue", map[string]interface{}{"threadId": 1})
+	require.Equal(t, "2\n", event("output")["output"])
+	require.Equal(t, float64(0), event("exited")["exitCode"])
+	request("disconnect", nil)
+}
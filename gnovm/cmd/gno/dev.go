@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/node"
+	"github.com/gnolang/gno/tm2/pkg/bft/privval"
+	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	vmm "github.com/gnolang/gno/tm2/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type devCfg struct {
+	rootDir             string
+	listen              string
+	chainID             string
+	genesisBalancesFile string
+	blockTime           time.Duration
+	pollInterval        time.Duration
+}
+
+func newDevCmd(io *commands.IO) *commands.Command {
+	cfg := &devCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "dev",
+			ShortUsage: "dev [flags] <dir> [<dir>...]",
+			ShortHelp:  "Runs a local in-memory chain with the specified packages",
+			LongHelp: `Runs a single-validator chain in memory, whose genesis adds the gno packages of
+the specified directories, recursively, and the packages of the examples they
+import. The path of a package is the module of its gno.mod, or its path from a
+"gno.land" directory.
+
+The packages are added by test1 (g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5).
+The RPC is served at -listen, e.g. for gnokey and gnoweb. When a file of the
+packages changes, the chain is restarted from a new genesis.`,
+		},
+		cfg,
+		func(ctx context.Context, args []string) error {
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+			defer stop()
+			return execDev(ctx, cfg, args, io)
+		},
+	)
+}
+
+func (c *devCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rootDir,
+		"root-dir",
+		"",
+		"clone location of github.com/gnolang/gno (gnodev tries to guess it)",
+	)
+
+	fs.StringVar(
+		&c.listen,
+		"listen",
+		"tcp://127.0.0.1:26657",
+		"listen address of the RPC",
+	)
+
+	fs.StringVar(
+		&c.chainID,
+		"chainid",
+		"dev",
+		"the ID of the chain",
+	)
+
+	fs.StringVar(
+		&c.genesisBalancesFile,
+		"genesis-balances-file",
+		"",
+		"initial distribution file (default <root-dir>/gno.land/genesis/genesis_balances.txt)",
+	)
+
+	fs.DurationVar(
+		&c.blockTime,
+		"block-time",
+		time.Second,
+		"time between blocks",
+	)
+
+	fs.DurationVar(
+		&c.pollInterval,
+		"poll-interval",
+		time.Second,
+		"interval between checks for changes of the files",
+	)
+}
+
+// devCreator adds the packages in the genesis: test1.
+var devCreator = crypto.MustAddressFromString("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+
+func execDev(ctx context.Context, cfg *devCfg, args []string, io *commands.IO) error {
+	if len(args) == 0 {
+		return flag.ErrHelp
+	}
+
+	if cfg.rootDir == "" {
+		cfg.rootDir = guessRootDir()
+	}
+	if cfg.genesisBalancesFile == "" {
+		cfg.genesisBalancesFile = filepath.Join(cfg.rootDir, "gno.land", "genesis", "genesis_balances.txt")
+	}
+	balances, err := gnoland.LoadGenesisBalancesFile(cfg.genesisBalancesFile)
+	if err != nil {
+		return err
+	}
+
+	pkgs, err := devPackages(cfg.rootDir, args)
+	if err != nil {
+		return err
+	}
+	n, err := startDevNode(cfg, balances, pkgs, io)
+	if err != nil {
+		return err
+	}
+	io.ErrPrintfln("Listening on %s", cfg.listen)
+
+	stamp := devStamp(pkgs)
+	ticker := time.NewTicker(cfg.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			n.stop()
+			return nil
+		case <-ticker.C:
+		}
+		s := devStamp(pkgs)
+		if s == stamp {
+			continue
+		}
+		stamp = s
+
+		io.ErrPrintln("Files changed, reloading.")
+		newPkgs, err := devPackages(cfg.rootDir, args)
+		if err != nil {
+			io.ErrPrintfln("error: %v", err)
+			continue
+		}
+		pkgs = newPkgs
+		stamp = devStamp(pkgs)
+		n.stop()
+		if n, err = startDevNode(cfg, balances, pkgs, io); err != nil {
+			return err
+		}
+	}
+}
+
+// A devPackage is a package of gno dev, with its directory.
+type devPackage struct {
+	dir string
+	*std.MemPackage
+}
+
+// devPackages reads the packages of the directories args, and of the
+// examples they import, sorted such that the imports of a package precede
+// it.
+func devPackages(rootDir string, args []string) (pkgs []devPackage, err error) {
+	defer func() {
+		// ReadMemPackage panics on invalid files.
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	dirs, err := devDirs(args)
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]devPackage)
+	imports := make(map[string][]string)
+	var paths []string
+	read := func(dir string, pkgPath string) error {
+		pkg := devPackage{dir: dir, MemPackage: gno.ReadMemPackage(dir, pkgPath)}
+		imps, err := devImports(pkg.MemPackage)
+		if err != nil {
+			return err
+		}
+		byPath[pkgPath] = pkg
+		imports[pkgPath] = imps
+		paths = append(paths, pkgPath)
+		return nil
+	}
+	for _, dir := range dirs {
+		pkgPath := lintPkgPath(dir)
+		if pkgPath == "" {
+			return nil, fmt.Errorf("cannot determine the package path of %s: add a gno.mod", dir)
+		}
+		if _, ok := byPath[pkgPath]; ok {
+			return nil, fmt.Errorf("package %s is in several directories", pkgPath)
+		}
+		if err := read(dir, pkgPath); err != nil {
+			return nil, err
+		}
+	}
+	// also read the examples imported, not found in args.
+	for i := 0; i < len(paths); i++ {
+		for _, imp := range imports[paths[i]] {
+			exDir := filepath.Join(rootDir, "examples", filepath.FromSlash(imp))
+			if _, ok := byPath[imp]; ok || !strings.HasPrefix(imp, "gno.land/") || !isFileExist(exDir) {
+				continue
+			}
+			if err := read(exDir, imp); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// sort topologically, in the order read otherwise.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var visit func(path string) error
+	visit = func(path string) error {
+		switch state[path] {
+		case visiting:
+			return fmt.Errorf("import cycle through %s", path)
+		case visited:
+			return nil
+		}
+		state[path] = visiting
+		for _, imp := range imports[path] {
+			if _, ok := byPath[imp]; ok {
+				if err := visit(imp); err != nil {
+					return err
+				}
+			}
+		}
+		state[path] = visited
+		pkgs = append(pkgs, byPath[path])
+		return nil
+	}
+	for _, path := range paths {
+		if err := visit(path); err != nil {
+			return nil, err
+		}
+	}
+	return pkgs, nil
+}
+
+// devDirs returns the directories containing gno files in the directories
+// args, recursively.
+func devDirs(args []string) ([]string, error) {
+	var dirs []string
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", arg)
+		}
+		err := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if isGnoFile(entry) {
+					dirs = append(dirs, path)
+					break
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+// devImports returns the imports of the non-test files of mpkg.
+func devImports(mpkg *std.MemPackage) ([]string, error) {
+	fset := token.NewFileSet()
+	var imports []string
+	for _, mfile := range mpkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, mfile.Name, mfile.Body, parser.ImportsOnly)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return nil, err
+			}
+			imports = append(imports, path)
+		}
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// devStamp returns a summary of the names, sizes and modification times of
+// the files of the directories of pkgs, which changes when they change.
+func devStamp(pkgs []devPackage) string {
+	var sb strings.Builder
+	for _, pkg := range pkgs {
+		entries, err := os.ReadDir(pkg.dir)
+		if err != nil {
+			fmt.Fprintf(&sb, "%s: %v\n", pkg.dir, err)
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || info.IsDir() {
+				continue
+			}
+			fmt.Fprintf(&sb, "%s %d %d\n", filepath.Join(pkg.dir, entry.Name()), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return sb.String()
+}
+
+// A devNode is a running node of gno dev.
+type devNode struct {
+	*node.Node
+	home string
+}
+
+// startDevNode starts a node whose genesis adds pkgs.
+func startDevNode(cfg *devCfg, balances []string, pkgs []devPackage, io *commands.IO) (*devNode, error) {
+	// the databases are in memory, but the consensus has files, e.g. its
+	// write-ahead log.
+	home, err := os.MkdirTemp("", "gnodev")
+	if err != nil {
+		return nil, err
+	}
+	bftCfg := config.TestConfig().SetRootDir(home)
+	bftCfg.EnsureDirs()
+	bftCfg.RPC.ListenAddress = cfg.listen
+	bftCfg.P2P.ListenAddress = "tcp://127.0.0.1:0"
+	bftCfg.Consensus.CreateEmptyBlocks = true
+	bftCfg.Consensus.CreateEmptyBlocksInterval = 0
+	bftCfg.Consensus.SkipTimeoutCommit = false
+	bftCfg.Consensus.TimeoutCommit = cfg.blockTime
+
+	// the logs of a new chain, e.g. of its consensus, are mostly noise.
+	logger := log.NewNopLogger()
+
+	pv := privval.GenFilePV(bftCfg.PrivValidatorKeyFile(), bftCfg.PrivValidatorStateFile())
+	genesis := &bft.GenesisDoc{
+		GenesisTime: time.Now(),
+		ChainID:     cfg.chainID,
+		ConsensusParams: abci.ConsensusParams{
+			Block: &abci.BlockParams{
+				MaxTxBytes:   1000000,  // 1MB,
+				MaxDataBytes: 2000000,  // 2MB,
+				MaxGas:       10000000, // 10M gas
+				TimeIotaMS:   100,      // 100ms
+			},
+		},
+		Validators: []bft.GenesisValidator{
+			{
+				Address: pv.GetPubKey().Address(),
+				PubKey:  pv.GetPubKey(),
+				Power:   10,
+				Name:    "devvalidator",
+			},
+		},
+	}
+	txs := make([]std.Tx, len(pkgs))
+	for i, pkg := range pkgs {
+		txs[i].Msgs = []std.Msg{
+			vmm.MsgAddPackage{
+				Creator: devCreator,
+				Package: pkg.MemPackage,
+			},
+		}
+		txs[i].Fee = std.NewFee(50000, std.MustParseCoin("1000000ugnot"))
+		txs[i].Signatures = make([]std.Signature, len(txs[i].GetSigners()))
+	}
+	genesis.AppState = gnoland.GnoGenesisState{
+		Balances: balances,
+		Txs:      txs,
+	}
+
+	app, err := gnoland.NewAppWithOptions(gnoland.AppOptions{
+		DB:         dbm.NewMemDB(),
+		StdlibsDir: filepath.Join(cfg.rootDir, "gnovm", "stdlibs"),
+		Logger:     logger,
+		GenesisTxHandler: func(tx std.Tx, res sdk.Result) {
+			pkg := tx.Msgs[0].(vmm.MsgAddPackage).Package
+			if res.IsErr() {
+				io.ErrPrintfln("Failed to add package %s: %s", pkg.Path, res.Log)
+			} else {
+				io.ErrPrintfln("Added package %s", pkg.Path)
+			}
+		},
+	})
+	if err != nil {
+		os.RemoveAll(home)
+		return nil, fmt.Errorf("error in creating new app: %w", err)
+	}
+
+	nodeKey := &p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}
+	n, err := node.NewNode(bftCfg, pv, nodeKey,
+		proxy.NewLocalClientCreator(app),
+		func() (*bft.GenesisDoc, error) { return genesis, nil },
+		node.DefaultDBProvider,
+		logger)
+	if err != nil {
+		os.RemoveAll(home)
+		return nil, fmt.Errorf("error in creating node: %w", err)
+	}
+	if err := n.Start(); err != nil {
+		os.RemoveAll(home)
+		return nil, fmt.Errorf("error in start node: %w", err)
+	}
+	return &devNode{Node: n, home: home}, nil
+}
+
+func (n *devNode) stop() {
+	_ = n.Stop()
+	n.Wait()
+	os.RemoveAll(n.home)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
+)
+
+// writeDevPackage writes a package in dir with gno.mod and the file
+// render.gno.
+func writeDevPackage(t *testing.T, dir string, pkgPath string, body string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gno.mod"), []byte("module "+pkgPath+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "render.gno"), []byte(body), 0o644))
+}
+
+func TestDevPackages(t *testing.T) {
+	dir := t.TempDir()
+	writeDevPackage(t, filepath.Join(dir, "hello"), "gno.land/r/demo/hello", `package hello
+
+import "gno.land/p/demo/greet"
+
+func Render(path string) string { return greet.Hello(path) }
+`)
+	writeDevPackage(t, filepath.Join(dir, "greet"), "gno.land/p/demo/greet", `package greet
+
+import "gno.land/p/demo/ufmt"
+
+func Hello(name string) string { return ufmt.Sprintf("hello %s", name) }
+`)
+
+	pkgs, err := devPackages("../../..", []string{dir})
+	require.NoError(t, err)
+	var paths []string
+	for _, pkg := range pkgs {
+		paths = append(paths, pkg.Path)
+	}
+	assert.Equal(t, []string{"gno.land/p/demo/ufmt", "gno.land/p/demo/greet", "gno.land/r/demo/hello"}, paths)
+
+	_, err = devPackages("../../..", []string{"../../tests/integ/valid1"})
+	assert.Error(t, err)
+}
+
+func TestDev(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts a node")
+	}
+
+	dir := t.TempDir()
+	writeDevPackage(t, dir, "gno.land/r/demo/hello", `package hello
+
+func Render(path string) string { return "hello " + path }
+`)
+
+	// pick a free port for the RPC.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	l.Close()
+
+	io := commands.NewTestIO()
+	var stderr bytes.Buffer
+	io.SetErr(commands.WriteNopCloser(&stderr))
+	cfg := &devCfg{
+		rootDir:             "../../..",
+		listen:              "tcp://" + addr,
+		chainID:             "dev",
+		genesisBalancesFile: "../../../gno.land/genesis/genesis_balances.txt",
+		blockTime:           100 * time.Millisecond,
+		pollInterval:        100 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- execDev(ctx, cfg, []string{dir}, io) }()
+
+	cli := client.NewHTTP(addr, "/websocket")
+	render := func(want string) {
+		t.Helper()
+		var got string
+		for i := 0; i < 100; i++ {
+			res, err := cli.ABCIQuery("vm/qrender", []byte("gno.land/r/demo/hello\nbob"))
+			if err == nil && res.Response.Error == nil {
+				if got = string(res.Response.Data); got == want {
+					return
+				}
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("render: got %q, want %q", got, want)
+	}
+	render("hello bob")
+
+	// hot reload.
+	writeDevPackage(t, dir, "gno.land/r/demo/hello", `package hello
+
+func Render(path string) string { return "hi " + path }
+`)
+	render("hi bob")
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.True(t, strings.Contains(stderr.String(), "Added package gno.land/r/demo/hello\n"))
+	assert.True(t, strings.Contains(stderr.String(), "Files changed, reloading.\n"))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/pkg/doc"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type docCfg struct {
+	rootDir string
+	remote  string
+}
+
+func newDocCmd(io *commands.IO) *commands.Command {
+	cfg := &docCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "doc",
+			ShortUsage: "doc [flags] <pkgpath>[.<symbol>]",
+			ShortHelp:  "Shows the documentation of a package or symbol",
+			LongHelp: `Shows the documentation of a package, or of one of its symbols: a constant,
+variable, function or type, or a method as <type>.<method>.
+
+The package is a directory, or the path of a standard library or of a package
+of the examples, e.g. "strings" or "gno.land/p/demo/avl". With -remote, other
+packages are fetched from the node at this address.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execDoc(cfg, args, io)
+		},
+	)
+}
+
+func (c *docCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rootDir,
+		"root-dir",
+		"",
+		"clone location of github.com/gnolang/gno (gnodev tries to guess it)",
+	)
+
+	fs.StringVar(
+		&c.remote,
+		"remote",
+		"",
+		"address of the node to fetch packages from (e.g. test3.gno.land:36657)",
+	)
+}
+
+func execDoc(cfg *docCfg, args []string, io *commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	pkgArg, symbol := splitDocArg(args[0])
+	mpkg, err := docMemPackage(cfg, pkgArg)
+	if err != nil {
+		return err
+	}
+	dpkg, err := doc.New(mpkg)
+	if err != nil {
+		return err
+	}
+	return dpkg.WriteText(io.Out, symbol)
+}
+
+// splitDocArg splits "<pkgpath>[.<symbol>]". As package paths have dots,
+// the symbol starts at the first dot of the last element of the path.
+func splitDocArg(arg string) (pkg string, symbol string) {
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		return arg, ""
+	}
+	dir, last := "", arg
+	if i := strings.LastIndex(arg, "/"); i >= 0 {
+		dir, last = arg[:i+1], arg[i+1:]
+	}
+	if last == "." || last == ".." {
+		return arg, ""
+	}
+	if i := strings.Index(last, "."); i >= 0 {
+		return dir + last[:i], last[i+1:]
+	}
+	return arg, ""
+}
+
+// docMemPackage reads the package pkg, from a directory, the standard
+// libraries or the examples, or fetches it from the remote.
+func docMemPackage(cfg *docCfg, pkg string) (*std.MemPackage, error) {
+	if info, err := os.Stat(pkg); err == nil && info.IsDir() {
+		pkgPath := lintPkgPath(pkg)
+		if pkgPath == "" {
+			abs, err := filepath.Abs(pkg)
+			if err != nil {
+				return nil, err
+			}
+			pkgPath = filepath.Base(abs)
+		}
+		return gno.ReadMemPackage(pkg, pkgPath), nil
+	}
+
+	if cfg.rootDir == "" {
+		cfg.rootDir = guessRootDir()
+	}
+	for _, dir := range []string{
+		filepath.Join(cfg.rootDir, "gnovm", "stdlibs", pkg),
+		filepath.Join(cfg.rootDir, "examples", pkg),
+	} {
+		if isFileExist(dir) {
+			return gno.ReadMemPackage(dir, pkg), nil
+		}
+	}
+
+	if cfg.remote == "" {
+		return nil, fmt.Errorf("package %s not found, use -remote to fetch it from a node", pkg)
+	}
+	return doc.FetchMemPackage(pkg, func(path string) (string, error) {
+		return queryFile(cfg.remote, path)
+	})
+}
+
+// queryFile queries the vm/qfile of the remote.
+func queryFile(remote string, path string) (string, error) {
+	cli := client.NewHTTP(remote, "/websocket")
+	qres, err := cli.ABCIQueryWithOptions("vm/qfile", []byte(path), client.ABCIQueryOptions{})
+	if err != nil {
+		return "", fmt.Errorf("query %s: %w", path, err)
+	}
+	if qres.Response.Error != nil {
+		return "", fmt.Errorf("query %s: %w", path, qres.Response.Error)
+	}
+	return string(qres.Response.Data), nil
+}
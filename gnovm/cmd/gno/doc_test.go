@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestDoc(t *testing.T) {
+	tc := []testMainCase{
+		{
+			args:        []string{"doc"},
+			errShouldBe: "flag: help requested",
+		},
+		{
+			args:                []string{"doc", "../../../examples/gno.land/p/demo/avl"},
+			stdoutShouldContain: "type Tree struct{ ... }\n    func NewTree() *Tree\n",
+		},
+		{
+			args:           []string{"doc", "-root-dir", "../../..", "gno.land/p/demo/avl.Tree.Size"},
+			stdoutShouldBe: "func (tree *Tree) Size() int\n",
+		},
+		{
+			args:                []string{"doc", "-root-dir", "../../..", "strings.Contains"},
+			stdoutShouldContain: "func Contains(s, substr string) bool\n",
+		},
+		{
+			args:        []string{"doc", "-root-dir", "../../..", "strings.Nope"},
+			errShouldBe: "no symbol Nope in package strings",
+		},
+		{
+			args:        []string{"doc", "-root-dir", "../../..", "gno.land/p/demo/nope"},
+			errShouldBe: "package gno.land/p/demo/nope not found, use -remote to fetch it from a node",
+		},
+	}
+	testMainCaseRun(t, tc)
+}
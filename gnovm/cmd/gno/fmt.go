@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+type fmtCfg struct {
+	list  bool
+	write bool
+	diff  bool
+}
+
+func newFmtCmd(io *commands.IO) *commands.Command {
+	cfg := &fmtCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "fmt",
+			ShortUsage: "fmt [flags] <file or dir> [<file or dir>...]",
+			ShortHelp:  "Formats the specified gno files",
+			LongHelp: `Formats the specified gno files, and the gno files of the specified
+directories recursively, as gofmt formats go files. By default, the formatted
+files are printed.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execFmt(cfg, args, io)
+		},
+	)
+}
+
+func (c *fmtCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.BoolVar(
+		&c.list,
+		"l",
+		false,
+		"list the files whose formatting differs",
+	)
+
+	fs.BoolVar(
+		&c.write,
+		"w",
+		false,
+		"write the formatted files instead of printing them",
+	)
+
+	fs.BoolVar(
+		&c.diff,
+		"d",
+		false,
+		"print the diffs of the formatting instead of the formatted files",
+	)
+}
+
+func execFmt(cfg *fmtCfg, args []string, io *commands.IO) error {
+	if len(args) < 1 {
+		return flag.ErrHelp
+	}
+
+	paths, err := gnoFilesFromArgs(args)
+	if err != nil {
+		return fmt.Errorf("list files: %w", err)
+	}
+
+	errCount := 0
+	for _, path := range paths {
+		err := fmtFile(path, cfg, io)
+		if err != nil {
+			io.ErrPrintln(err)
+			errCount++
+		}
+	}
+
+	if errCount > 0 {
+		return fmt.Errorf("%d files could not be formatted", errCount)
+	}
+	return nil
+}
+
+func fmtFile(path string, cfg *fmtCfg, io *commands.IO) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	// unlike format.Source, require a whole file, as gofmt does. Errors
+	// of the parser and of os include the path.
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	res := buf.Bytes()
+	changed := !bytes.Equal(src, res)
+
+	if cfg.list && changed {
+		io.Println(path)
+	}
+	if cfg.write && changed {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, res, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if cfg.diff && changed {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(src)),
+			B:        difflib.SplitLines(string(res)),
+			FromFile: path + ".orig",
+			ToFile:   path,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		io.Printf("%s", diff)
+	}
+	if !cfg.list && !cfg.write && !cfg.diff {
+		io.Printf("%s", res)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestFmt(t *testing.T) {
+	tc := []testMainCase{
+		{
+			args:        []string{"fmt"},
+			errShouldBe: "flag: help requested",
+		},
+		{
+			args:           []string{"fmt", "-l", "../../tests/integ/unformatted", "../../tests/integ/valid1"},
+			stdoutShouldBe: "../../tests/integ/unformatted/main.gno\n",
+		},
+		{
+			args:           []string{"fmt", "../../tests/integ/unformatted/main.gno"},
+			stdoutShouldBe: "package main\n\nfunc main() {\n\tx := 1\n\tprintln(x)\n}\n",
+		},
+		{
+			args:                []string{"fmt", "-d", "../../tests/integ/unformatted"},
+			stdoutShouldContain: "-    x:=1\n-\tprintln( x )\n+func main() {\n+\tx := 1\n+\tprintln(x)\n",
+		},
+		{
+			args:           []string{"fmt", "../../tests/integ/empty-gno1"},
+			errShouldBe:    "1 files could not be formatted",
+			stderrShouldBe: "../../tests/integ/empty-gno1/empty.gno:1:1: expected 'package', found 'EOF'\n",
+		},
+	}
+	testMainCaseRun(t, tc)
+}
@@ -0,0 +1,853 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// Fuzz tests are Fuzz* functions taking a *testing.F, which add seed
+// inputs and set a fuzz target (see stdlibs/testing.F). On normal runs,
+// the fuzz target is called with the seed inputs and those of the
+// testdata/fuzz/<FuzzName> directory of the package, as regression tests.
+// With -fuzz, inputs are also mutated from those, keeping the ones that
+// reach new code, until the target fails; the failing input is then
+// written to testdata/fuzz/<FuzzName>.
+//
+// Inputs are written in the corpus file format of Go, so that corpora can
+// be shared with Go fuzz tests.
+
+const fuzzCorpusHeader = "go test fuzz v1"
+
+// A fuzzInput is a list of arguments of a fuzz target, after the
+// *testing.T. Depending on the kind of the parameter, values are string,
+// []byte, bool, int64 (for signed integers), uint64 (for unsigned
+// integers) or float64.
+type fuzzInput []interface{}
+
+// A fuzzTarget is the fuzz target set by a fuzz test, on the machine of
+// its package.
+type fuzzTarget struct {
+	name   string
+	m      *gno.Machine
+	fn     gno.TypedValue
+	params []gno.Type // after the *testing.T.
+	seeds  []fuzzInput
+}
+
+// newFuzzTarget runs the fuzz test name on m, and returns the fuzz target
+// and seed inputs it set. The target is nil if the fuzz test failed or was
+// skipped, as reported.
+func newFuzzTarget(m *gno.Machine, name string) (*fuzzTarget, report, error) {
+	var rep report
+	res := m.Eval(gno.Call("runfuzz", fmt.Sprintf("%q", name)))
+	if err := json.Unmarshal([]byte(res[0].GetString()), &rep); err != nil {
+		return nil, rep, err
+	}
+	if rep.Failed || rep.Skipped {
+		return nil, rep, nil
+	}
+
+	ft := &fuzzTarget{name: name, m: m, fn: res[1]}
+	ftype, ok := ft.fn.T.(*gno.FuncType)
+	if !ok || len(ftype.Params) == 0 || len(ftype.Results) != 0 ||
+		ftype.Params[0].Type.String() != "*testing.T" {
+		return nil, rep, errors.New("fuzz target must be a function of the form func(*testing.T, ...), got %s", ft.fn.T.String())
+	}
+	for _, p := range ftype.Params[1:] {
+		if fuzzTypeName(p.Type) == "" {
+			return nil, rep, errors.New("fuzz target parameters must be of type string, []byte, bool, or a numeric type other than complex, got %s", p.Type.String())
+		}
+		ft.params = append(ft.params, p.Type)
+	}
+
+	// seed inputs, as added, of type [][]interface{}.
+	for i, seed := range fuzzSliceList(m.Store, res[2]) {
+		args := fuzzSliceList(m.Store, seed)
+		if len(args) != len(ft.params) {
+			return nil, rep, errors.New("wrong number of values in seed input #%d: want %d, got %d", i, len(ft.params), len(args))
+		}
+		in := make(fuzzInput, len(args))
+		for j, arg := range args {
+			if arg.T.TypeID() != ft.params[j].TypeID() {
+				return nil, rep, errors.New("mismatched types in seed input #%d: want %s, got %s", i, ft.params[j].String(), arg.T.String())
+			}
+			in[j] = fuzzArgOf(m.Store, arg)
+		}
+		ft.seeds = append(ft.seeds, in)
+	}
+	return ft, rep, nil
+}
+
+// run calls the fuzz target with in, with a *testing.T named name. It
+// returns an error if the machine panicked, in which case it cannot be
+// used anymore.
+func (ft *fuzzTarget) run(name string, in fuzzInput) (rep report, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	m := ft.m
+	t := m.Eval(gno.Call("newfuzzt", fmt.Sprintf("%q", name)))[0]
+	args := []gno.Expr{&gno.ConstExpr{TypedValue: t}}
+	for i, arg := range in {
+		args = append(args, &gno.ConstExpr{TypedValue: fuzzArgValue(m.Alloc, ft.params[i], arg)})
+	}
+	input := gno.Fn(nil, nil, gno.Ss(gno.S(&gno.CallExpr{
+		Func: &gno.ConstExpr{TypedValue: ft.fn},
+		Args: args,
+	})))
+	res := m.Eval(gno.Call("runfuzzinput", &gno.ConstExpr{TypedValue: t}, input))
+	err = json.Unmarshal([]byte(res[0].GetString()), &rep)
+	return rep, err
+}
+
+// corpus returns the seed inputs and those of the testdata/fuzz directory
+// of the package, with their names.
+func (ft *fuzzTarget) corpus(pkgPath string) ([]string, []fuzzInput, error) {
+	var names []string
+	var inputs []fuzzInput
+	for i, seed := range ft.seeds {
+		names = append(names, fmt.Sprintf("seed#%d", i))
+		inputs = append(inputs, seed)
+	}
+
+	dir := filepath.Join(pkgPath, "testdata", "fuzz", ft.name)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+		in, err := unmarshalFuzzInput(ft.params, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", filepath.Join(dir, entry.Name()), err)
+		}
+		names = append(names, entry.Name())
+		inputs = append(inputs, in)
+	}
+	return names, inputs, nil
+}
+
+// runFuzzTest runs the fuzz test name, and calls its fuzz target with the
+// inputs of its corpus, as a test. It returns its fuzz target, if set, and
+// whether m can still be used: it cannot after a panic of the machine.
+func runFuzzTest(m *gno.Machine, pkgPath string, name string, cfg *testCfg, io *commands.IO) (*fuzzTarget, bool, error) {
+	verbose := cfg.verbose
+	filter := splitRegexp(cfg.run)
+
+	if verbose {
+		io.ErrPrintfln("=== RUN   %s", name)
+	}
+	startedAt := time.Now()
+	fail := func(err error) error {
+		io.ErrPrintfln("--- FAIL: %s (%s)", name, fmtDuration(time.Since(startedAt)))
+		return err
+	}
+
+	ft, rep, err := newFuzzTarget(m, name)
+	if err != nil {
+		return nil, true, fail(err)
+	}
+	if rep.Output != "" && (verbose || rep.Failed) {
+		defer io.ErrPrintfln("output: %s", rep.Output)
+	}
+	switch {
+	case rep.Failed:
+		return nil, true, fail(errors.New("failed: %q", name))
+	case rep.Skipped:
+		if verbose {
+			io.ErrPrintfln("--- SKIP: %s", name)
+		}
+		return nil, true, nil
+	}
+
+	names, inputs, err := ft.corpus(pkgPath)
+	if err != nil {
+		return nil, true, fail(err)
+	}
+	var errs error
+	for i, in := range inputs {
+		testName := name + "/" + names[i]
+		if !shouldRun(filter, testName) {
+			continue
+		}
+		rep, err := ft.run(testName, in)
+		if err != nil {
+			io.ErrPrintfln("    --- FAIL: %s", testName)
+			io.ErrPrintfln("output: panic: %v", err)
+			return ft, false, fail(errors.New("failed: %q", testName))
+		}
+		if rep.Failed {
+			io.ErrPrintfln("    --- FAIL: %s", testName)
+			if rep.Output != "" {
+				io.ErrPrintfln("output: %s", rep.Output)
+			}
+			errs = errors.New("failed: %q", testName)
+		}
+	}
+	if errs != nil {
+		return ft, true, fail(errs)
+	}
+	if verbose {
+		io.ErrPrintfln("--- PASS: %s (%s)", name, fmtDuration(time.Since(startedAt)))
+	}
+	return ft, true, nil
+}
+
+// fuzz calls the fuzz target with inputs mutated from its corpus until it
+// fails, or until the fuzz time of cfg is over. Inputs that execute new
+// lines of the package are added to the corpus. The failing input is
+// written to the testdata/fuzz directory of the package.
+func (ft *fuzzTarget) fuzz(pkgPath string, cfg *testCfg, io *commands.IO) error {
+	limit, maxExecs, err := parseFuzzTime(cfg.fuzzTime)
+	if err != nil {
+		return err
+	}
+	_, corpus, err := ft.corpus(pkgPath)
+	if err != nil {
+		return err
+	}
+	if len(corpus) == 0 {
+		corpus = append(corpus, zeroFuzzInput(ft.params))
+	}
+
+	m := ft.m
+	cov := m.Coverage
+	if cov == nil {
+		cov, err = newPkgCoverage(pkgPath)
+		if err != nil {
+			return err
+		}
+		m.Coverage = cov
+		defer func() { m.Coverage = nil }()
+	}
+
+	mu := fuzzMutator{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	startedAt := time.Now()
+	lastLog := startedAt
+	numSeeds := len(corpus)
+	covered := 0
+	logStatus := func(execs int) {
+		elapsed := time.Since(startedAt)
+		io.ErrPrintfln("fuzz: elapsed: %s, execs: %d (%.0f/sec), new interesting: %d (total: %d)",
+			elapsed.Round(time.Second), execs, float64(execs)/elapsed.Seconds(),
+			len(corpus)-numSeeds, len(corpus))
+	}
+
+	io.ErrPrintfln("fuzz: elapsed: 0s, gathering baseline coverage: 0/%d completed", numSeeds)
+	execs := 0
+	for {
+		var in fuzzInput
+		if execs < numSeeds {
+			// gather the coverage of the corpus first.
+			in = corpus[execs]
+		} else {
+			if (limit > 0 && time.Since(startedAt) >= limit) ||
+				(maxExecs > 0 && execs-numSeeds >= maxExecs) {
+				break
+			}
+			in = mu.mutate(ft.params, corpus[mu.rand.Intn(len(corpus))])
+		}
+		execs++
+
+		rep, err := ft.run(ft.name, in)
+		if err != nil || rep.Failed {
+			if err != nil {
+				rep.Output = fmt.Sprintf("panic: %v", err)
+			}
+			return ft.crash(pkgPath, in, rep, time.Since(startedAt), io)
+		}
+
+		if n := cov.NumCovered(); n > covered {
+			covered = n
+			if execs > numSeeds {
+				corpus = append(corpus, in)
+			}
+		}
+		if execs == numSeeds {
+			io.ErrPrintfln("fuzz: elapsed: %s, gathering baseline coverage: %d/%d completed, now fuzzing",
+				time.Since(startedAt).Round(time.Second), numSeeds, numSeeds)
+		}
+		if time.Since(lastLog) >= 3*time.Second {
+			lastLog = time.Now()
+			logStatus(execs)
+		}
+	}
+	logStatus(execs)
+	return nil
+}
+
+// crash writes in, a failing input, to the corpus of the package, and
+// reports the failure.
+func (ft *fuzzTarget) crash(pkgPath string, in fuzzInput, rep report, d time.Duration, io *commands.IO) error {
+	data := marshalFuzzInput(ft.params, in)
+	hash := fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+	dir := filepath.Join(pkgPath, "testdata", "fuzz", ft.name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, hash)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+
+	io.ErrPrintfln("--- FAIL: %s (%s)", ft.name, fmtDuration(d))
+	if rep.Output != "" {
+		io.ErrPrintfln("output: %s", rep.Output)
+	}
+	io.ErrPrintfln("")
+	io.ErrPrintfln("Failing input written to %s", path)
+	io.ErrPrintfln("To re-run:")
+	io.ErrPrintfln("gno test -run %s/%s %s", ft.name, hash, pkgPath)
+	return errors.New("failed: %q", ft.name)
+}
+
+// parseFuzzTime parses the -fuzztime flag, a duration or a number of
+// executions as in "1000x". Zero values are unlimited.
+func parseFuzzTime(s string) (time.Duration, int, error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	if strings.HasSuffix(s, "x") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "x"))
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("invalid -fuzztime %q", s)
+		}
+		return 0, n, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, 0, fmt.Errorf("invalid -fuzztime %q", s)
+	}
+	return d, 0, nil
+}
+
+//----------------------------------------
+// values
+
+// fuzzTypeName returns the name of t in corpus files, or "" if t cannot be
+// the type of a fuzz target parameter.
+func fuzzTypeName(t gno.Type) string {
+	switch t.Kind() {
+	case gno.StringKind, gno.BoolKind,
+		gno.IntKind, gno.Int8Kind, gno.Int16Kind, gno.Int32Kind, gno.Int64Kind,
+		gno.UintKind, gno.Uint8Kind, gno.Uint16Kind, gno.Uint32Kind, gno.Uint64Kind,
+		gno.Float32Kind, gno.Float64Kind:
+		if _, ok := t.(gno.PrimitiveType); !ok {
+			return "" // named
+		}
+		return t.String()
+	case gno.SliceKind:
+		if st, ok := t.(*gno.SliceType); ok && st.Elt.Kind() == gno.Uint8Kind {
+			if _, ok := st.Elt.(gno.PrimitiveType); ok {
+				return "[]byte"
+			}
+		}
+	}
+	return ""
+}
+
+// fuzzBits returns the size in bits of the numeric type t.
+func fuzzBits(t gno.Type) int {
+	switch t.Kind() {
+	case gno.Int8Kind, gno.Uint8Kind:
+		return 8
+	case gno.Int16Kind, gno.Uint16Kind:
+		return 16
+	case gno.Int32Kind, gno.Uint32Kind, gno.Float32Kind:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// fuzzSliceList returns the elements of tv, a slice.
+func fuzzSliceList(store gno.Store, tv gno.TypedValue) []gno.TypedValue {
+	sv, ok := tv.V.(*gno.SliceValue)
+	if !ok {
+		return nil
+	}
+	av := sv.GetBase(store)
+	return av.List[sv.Offset : sv.Offset+sv.Length]
+}
+
+// fuzzArgOf returns the value of tv, of a fuzz target parameter type.
+func fuzzArgOf(store gno.Store, tv gno.TypedValue) interface{} {
+	switch tv.T.Kind() {
+	case gno.StringKind:
+		return tv.GetString()
+	case gno.BoolKind:
+		return tv.GetBool()
+	case gno.IntKind:
+		return int64(tv.GetInt())
+	case gno.Int8Kind:
+		return int64(tv.GetInt8())
+	case gno.Int16Kind:
+		return int64(tv.GetInt16())
+	case gno.Int32Kind:
+		return int64(tv.GetInt32())
+	case gno.Int64Kind:
+		return tv.GetInt64()
+	case gno.UintKind:
+		return uint64(tv.GetUint())
+	case gno.Uint8Kind:
+		return uint64(tv.GetUint8())
+	case gno.Uint16Kind:
+		return uint64(tv.GetUint16())
+	case gno.Uint32Kind:
+		return uint64(tv.GetUint32())
+	case gno.Uint64Kind:
+		return tv.GetUint64()
+	case gno.Float32Kind:
+		return float64(tv.GetFloat32())
+	case gno.Float64Kind:
+		return tv.GetFloat64()
+	case gno.SliceKind:
+		if tv.V == nil {
+			return []byte(nil)
+		}
+		sv := tv.V.(*gno.SliceValue)
+		data := sv.GetBase(store).GetReadonlyBytes()
+		return append([]byte(nil), data[sv.Offset:sv.Offset+sv.Length]...)
+	default:
+		panic("should not happen")
+	}
+}
+
+// fuzzArgValue returns the value v as a value of type t.
+func fuzzArgValue(alloc *gno.Allocator, t gno.Type, v interface{}) gno.TypedValue {
+	tv := gno.TypedValue{T: t}
+	switch t.Kind() {
+	case gno.StringKind:
+		tv.V = alloc.NewString(v.(string))
+	case gno.BoolKind:
+		tv.SetBool(v.(bool))
+	case gno.IntKind:
+		tv.SetInt(int(v.(int64)))
+	case gno.Int8Kind:
+		tv.SetInt8(int8(v.(int64)))
+	case gno.Int16Kind:
+		tv.SetInt16(int16(v.(int64)))
+	case gno.Int32Kind:
+		tv.SetInt32(int32(v.(int64)))
+	case gno.Int64Kind:
+		tv.SetInt64(v.(int64))
+	case gno.UintKind:
+		tv.SetUint(uint(v.(uint64)))
+	case gno.Uint8Kind:
+		tv.SetUint8(uint8(v.(uint64)))
+	case gno.Uint16Kind:
+		tv.SetUint16(uint16(v.(uint64)))
+	case gno.Uint32Kind:
+		tv.SetUint32(uint32(v.(uint64)))
+	case gno.Uint64Kind:
+		tv.SetUint64(v.(uint64))
+	case gno.Float32Kind:
+		tv.SetFloat32(float32(v.(float64)))
+	case gno.Float64Kind:
+		tv.SetFloat64(v.(float64))
+	case gno.SliceKind:
+		if data := v.([]byte); data != nil {
+			tv.V = alloc.NewSliceFromData(append([]byte(nil), data...))
+		}
+	default:
+		panic("should not happen")
+	}
+	return tv
+}
+
+// zeroFuzzInput returns the input of zero values of types.
+func zeroFuzzInput(types []gno.Type) fuzzInput {
+	in := make(fuzzInput, len(types))
+	for i, t := range types {
+		switch t.Kind() {
+		case gno.StringKind:
+			in[i] = ""
+		case gno.BoolKind:
+			in[i] = false
+		case gno.Float32Kind, gno.Float64Kind:
+			in[i] = float64(0)
+		case gno.SliceKind:
+			in[i] = []byte(nil)
+		case gno.UintKind, gno.Uint8Kind, gno.Uint16Kind, gno.Uint32Kind, gno.Uint64Kind:
+			in[i] = uint64(0)
+		default:
+			in[i] = int64(0)
+		}
+	}
+	return in
+}
+
+//----------------------------------------
+// corpus files
+
+// marshalFuzzInput encodes in, an input of a fuzz target with parameters
+// of types, in the corpus file format.
+func marshalFuzzInput(types []gno.Type, in fuzzInput) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(fuzzCorpusHeader + "\n")
+	for i, v := range in {
+		name := fuzzTypeName(types[i])
+		switch v := v.(type) {
+		case string, []byte:
+			fmt.Fprintf(&buf, "%s(%q)\n", name, v)
+		case float64:
+			bits := fuzzBits(types[i])
+			switch {
+			case math.IsNaN(v) && bits == 32:
+				fmt.Fprintf(&buf, "math.Float32frombits(0x%x)\n", math.Float32bits(float32(v)))
+			case math.IsNaN(v):
+				fmt.Fprintf(&buf, "math.Float64frombits(0x%x)\n", math.Float64bits(v))
+			case math.IsInf(v, 0):
+				fmt.Fprintf(&buf, "%s(%v)\n", name, v) // +Inf or -Inf
+			default:
+				fmt.Fprintf(&buf, "%s(%s)\n", name, strconv.FormatFloat(v, 'g', -1, bits))
+			}
+		default:
+			fmt.Fprintf(&buf, "%s(%v)\n", name, v)
+		}
+	}
+	return buf.Bytes()
+}
+
+// unmarshalFuzzInput decodes data, in the corpus file format, as an input
+// of a fuzz target with parameters of types.
+func unmarshalFuzzInput(types []gno.Type, data []byte) (fuzzInput, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != fuzzCorpusHeader {
+		return nil, fmt.Errorf("missing %q header", fuzzCorpusHeader)
+	}
+	lines = lines[1:]
+	if len(lines) != len(types) {
+		return nil, fmt.Errorf("wrong number of values in corpus entry: want %d, got %d", len(types), len(lines))
+	}
+	in := make(fuzzInput, len(types))
+	for i, line := range lines {
+		v, err := parseFuzzValue(types[i], strings.TrimSpace(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+		in[i] = v
+	}
+	return in, nil
+}
+
+// parseFuzzValue parses line, as written in corpus files, as a value of
+// type t. Lines are Go expressions, such as string("a"), int(-1),
+// rune('a'), or math.Float64frombits(0x7ff8000000000001).
+func parseFuzzValue(t gno.Type, line string) (interface{}, error) {
+	x, err := parser.ParseExpr(line)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := x.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, fmt.Errorf("malformed value %q", line)
+	}
+	want := fuzzTypeName(t)
+	mismatch := func(name string) error {
+		return fmt.Errorf("mismatched types: want %s, got %s", want, name)
+	}
+
+	// math.FloatXXfrombits(0x...)
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		name := fmt.Sprintf("%s.%s", sel.X, sel.Sel.Name)
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, fmt.Errorf("malformed value %q", line)
+		}
+		u, err := strconv.ParseUint(lit.Value, 0, 64)
+		switch {
+		case err != nil:
+			return nil, err
+		case name == "math.Float64frombits" && want == "float64":
+			return math.Float64frombits(u), nil
+		case name == "math.Float32frombits" && want == "float32":
+			return float64(math.Float32frombits(uint32(u))), nil
+		default:
+			return nil, mismatch(name)
+		}
+	}
+
+	var name string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.ArrayType:
+		if elt, ok := fun.Elt.(*ast.Ident); ok && fun.Len == nil &&
+			(elt.Name == "byte" || elt.Name == "uint8") {
+			name = "[]byte"
+		}
+	}
+	switch name {
+	case "rune":
+		name = "int32"
+	case "byte":
+		name = "uint8"
+	}
+	if name != want {
+		return nil, mismatch(name)
+	}
+
+	// the literal, with its sign.
+	arg := call.Args[0]
+	neg := false
+	if ux, ok := arg.(*ast.UnaryExpr); ok && (ux.Op == token.SUB || ux.Op == token.ADD) {
+		neg = ux.Op == token.SUB
+		arg = ux.X
+	}
+	var lit string
+	var kind token.Token
+	switch arg := arg.(type) {
+	case *ast.BasicLit:
+		lit, kind = arg.Value, arg.Kind
+	case *ast.Ident:
+		lit, kind = arg.Name, token.IDENT
+	default:
+		return nil, fmt.Errorf("malformed value %q", line)
+	}
+
+	switch t.Kind() {
+	case gno.StringKind, gno.SliceKind:
+		if kind != token.STRING || neg {
+			return nil, fmt.Errorf("malformed value %q", line)
+		}
+		s, err := strconv.Unquote(lit)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind() == gno.SliceKind {
+			return []byte(s), nil
+		}
+		return s, nil
+	case gno.BoolKind:
+		if kind != token.IDENT || neg || (lit != "true" && lit != "false") {
+			return nil, fmt.Errorf("malformed value %q", line)
+		}
+		return lit == "true", nil
+	case gno.Float32Kind, gno.Float64Kind:
+		var f float64
+		switch {
+		case kind == token.IDENT && lit == "Inf":
+			f = math.Inf(1)
+		case kind == token.IDENT && lit == "NaN":
+			f = math.NaN()
+		case kind == token.INT || kind == token.FLOAT:
+			f, err = strconv.ParseFloat(lit, fuzzBits(t))
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("malformed value %q", line)
+		}
+		if neg {
+			f = -f
+		}
+		return f, nil
+	case gno.UintKind, gno.Uint8Kind, gno.Uint16Kind, gno.Uint32Kind, gno.Uint64Kind:
+		if neg {
+			return nil, fmt.Errorf("malformed value %q", line)
+		}
+		if kind == token.CHAR {
+			r, _, _, err := strconv.UnquoteChar(lit[1:len(lit)-1], '\'')
+			if err != nil {
+				return nil, err
+			}
+			lit = strconv.Itoa(int(r))
+		} else if kind != token.INT {
+			return nil, fmt.Errorf("malformed value %q", line)
+		}
+		return strconv.ParseUint(lit, 0, fuzzBits(t))
+	default: // signed integers
+		if kind == token.CHAR {
+			r, _, _, err := strconv.UnquoteChar(lit[1:len(lit)-1], '\'')
+			if err != nil {
+				return nil, err
+			}
+			lit = strconv.Itoa(int(r))
+		} else if kind != token.INT {
+			return nil, fmt.Errorf("malformed value %q", line)
+		}
+		if neg {
+			lit = "-" + lit
+		}
+		return strconv.ParseInt(lit, 0, fuzzBits(t))
+	}
+}
+
+//----------------------------------------
+// mutations
+
+// fuzzMutator mutates inputs at random.
+type fuzzMutator struct {
+	rand *rand.Rand
+}
+
+var (
+	interestingBytes   = []byte{0, 1, 0x7f, 0x80, 0xff, ' ', '\n', '"', '\'', '\\', '%', '<', '>', '{', '}', '[', ']', '/', '*', '-', '0', '9', 'a'}
+	interestingStrings = []string{"", "\x00", "\xff", "%s", "%d", "%%", "\\", "\"", "<a>", "</", "{}", "[]", "-1", "0", "1e308", "é", "\U0001F600"}
+	interestingInts    = []int64{0, 1, -1, 2, 7, 8, 16, 32, 64, 100, 127, 128, 255, 256, 1000, 1024, 4096, 65535, 65536, math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64}
+	interestingFloats  = []float64{0, -0.5, 0.5, 1, -1, 1e-308, 1e308, math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1), math.NaN()}
+)
+
+// mutate returns a copy of in, of parameters of types, with one to four
+// mutations of one of its values.
+func (mu *fuzzMutator) mutate(types []gno.Type, in fuzzInput) fuzzInput {
+	out := make(fuzzInput, len(in))
+	copy(out, in)
+	if len(out) == 0 {
+		return out
+	}
+	i := mu.rand.Intn(len(out))
+	n := 1 + mu.rand.Intn(4)
+	for j := 0; j < n; j++ {
+		switch v := out[i].(type) {
+		case string:
+			out[i] = string(mu.mutateBytes([]byte(v), true))
+		case []byte:
+			out[i] = mu.mutateBytes(append([]byte(nil), v...), false)
+		case bool:
+			out[i] = !v
+		case int64:
+			out[i] = mu.mutateInt(v, fuzzBits(types[i]))
+		case uint64:
+			out[i] = mu.mutateUint(v, fuzzBits(types[i]))
+		case float64:
+			out[i] = mu.mutateFloat(v, fuzzBits(types[i]))
+		}
+	}
+	return out
+}
+
+func (mu *fuzzMutator) mutateBytes(b []byte, isString bool) []byte {
+	r := mu.rand
+	switch op := r.Intn(9); {
+	case op == 0 || len(b) == 0: // insert a byte
+		pos := r.Intn(len(b) + 1)
+		c := byte(r.Intn(256))
+		if r.Intn(2) == 0 {
+			c = interestingBytes[r.Intn(len(interestingBytes))]
+		}
+		return append(b[:pos], append([]byte{c}, b[pos:]...)...)
+	case op == 1: // delete a range
+		pos := r.Intn(len(b))
+		end := pos + 1 + r.Intn(len(b)-pos)
+		return append(b[:pos], b[end:]...)
+	case op == 2: // flip a bit
+		b[r.Intn(len(b))] ^= 1 << uint(r.Intn(8))
+	case op == 3: // set an interesting byte
+		b[r.Intn(len(b))] = interestingBytes[r.Intn(len(interestingBytes))]
+	case op == 4: // duplicate a range
+		pos := r.Intn(len(b))
+		end := pos + 1 + r.Intn(len(b)-pos)
+		dst := r.Intn(len(b) + 1)
+		chunk := append([]byte(nil), b[pos:end]...)
+		return append(b[:dst], append(chunk, b[dst:]...)...)
+	case op == 5: // swap two bytes
+		i, j := r.Intn(len(b)), r.Intn(len(b))
+		b[i], b[j] = b[j], b[i]
+	case op == 6: // truncate
+		return b[:r.Intn(len(b))]
+	case op == 7: // insert an interesting string
+		pos := r.Intn(len(b) + 1)
+		s := interestingStrings[r.Intn(len(interestingStrings))]
+		return append(b[:pos], append([]byte(s), b[pos:]...)...)
+	default: // add to a byte
+		b[r.Intn(len(b))] += byte(r.Intn(33) - 16)
+	}
+	return b
+}
+
+func (mu *fuzzMutator) mutateInt(v int64, bits int) int64 {
+	r := mu.rand
+	switch r.Intn(5) {
+	case 0:
+		v += int64(1 + r.Intn(16))
+	case 1:
+		v -= int64(1 + r.Intn(16))
+	case 2:
+		v ^= 1 << uint(r.Intn(bits))
+	case 3:
+		v = interestingInts[r.Intn(len(interestingInts))]
+	default:
+		v = -v
+	}
+	// wrap to the size of the type.
+	shift := uint(64 - bits)
+	return v << shift >> shift
+}
+
+func (mu *fuzzMutator) mutateUint(v uint64, bits int) uint64 {
+	r := mu.rand
+	switch r.Intn(4) {
+	case 0:
+		v += uint64(1 + r.Intn(16))
+	case 1:
+		v -= uint64(1 + r.Intn(16))
+	case 2:
+		v ^= 1 << uint(r.Intn(bits))
+	default:
+		v = uint64(interestingInts[r.Intn(len(interestingInts))])
+	}
+	shift := uint(64 - bits)
+	return v << shift >> shift
+}
+
+func (mu *fuzzMutator) mutateFloat(v float64, bits int) float64 {
+	r := mu.rand
+	switch r.Intn(5) {
+	case 0:
+		v += float64(r.Intn(33) - 16)
+	case 1:
+		v *= r.NormFloat64()
+	case 2:
+		v = interestingFloats[r.Intn(len(interestingFloats))]
+	case 3:
+		v = -v
+	default:
+		v = float64(int64(v))
+	}
+	if bits == 32 {
+		v = float64(float32(v))
+	}
+	return v
+}
+
+// matchFuzzTargets returns the names of the fuzz tests matching the -fuzz
+// pattern.
+func matchFuzzTargets(pattern string, fuzzes []testFunc) []string {
+	filter := splitRegexp(pattern)
+	var names []string
+	for _, fuzz := range fuzzes {
+		if shouldRun(filter, fuzz.Name) {
+			names = append(names, fuzz.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzCorpusEncoding(t *testing.T) {
+	types := []gno.Type{
+		gno.StringType,
+		&gno.SliceType{Elt: gno.Uint8Type},
+		gno.BoolType,
+		gno.IntType,
+		gno.Int8Type,
+		gno.Uint64Type,
+		gno.Float32Type,
+		gno.Float64Type,
+	}
+	in := fuzzInput{
+		"a\x00\"é",
+		[]byte{0xff, '\n'},
+		true,
+		int64(-42),
+		int64(math.MinInt8),
+		uint64(math.MaxUint64),
+		float64(float32(1.5)),
+		math.Inf(-1),
+	}
+
+	data := marshalFuzzInput(types, in)
+	require.Equal(t, `go test fuzz v1
+string("a\x00\"é")
+[]byte("\xff\n")
+bool(true)
+int(-42)
+int8(-128)
+uint64(18446744073709551615)
+float32(1.5)
+float64(-Inf)
+`, string(data))
+
+	out, err := unmarshalFuzzInput(types, data)
+	require.NoError(t, err)
+	require.Equal(t, in, out)
+
+	// values as written by Go.
+	out, err = unmarshalFuzzInput(
+		[]gno.Type{gno.Int32Type, gno.Uint8Type, gno.Float64Type},
+		[]byte("go test fuzz v1\nrune('é')\nbyte('\\x01')\nmath.Float64frombits(0x7ff8000000000001)\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64('é'), out[0])
+	require.Equal(t, uint64(1), out[1])
+	require.True(t, math.IsNaN(out[2].(float64)))
+
+	_, err = unmarshalFuzzInput([]gno.Type{gno.StringType}, []byte("go test fuzz v1\nint(1)\n"))
+	require.EqualError(t, err, "line 2: mismatched types: want string, got int")
+	_, err = unmarshalFuzzInput([]gno.Type{gno.StringType}, []byte("string(\"a\")\n"))
+	require.Error(t, err)
+}
+
+func TestFuzzMutate(t *testing.T) {
+	types := []gno.Type{gno.StringType, gno.Int8Type, gno.Uint16Type}
+	in := fuzzInput{"hello", int64(1), uint64(2)}
+	mu := fuzzMutator{rand: rand.New(rand.NewSource(1))}
+	for i := 0; i < 1000; i++ {
+		orig := append(fuzzInput(nil), in...)
+		out := mu.mutate(types, in)
+		require.Equal(t, orig, in, "input must not be modified")
+		i8 := out[1].(int64)
+		require.True(t, math.MinInt8 <= i8 && i8 <= math.MaxInt8, "int8 out of range: %d", i8)
+		require.LessOrEqual(t, out[2].(uint64), uint64(math.MaxUint16))
+		in = out
+	}
+}
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnomod"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+type lintCfg struct {
+	rootDir string
+}
+
+func newLintCmd(io *commands.IO) *commands.Command {
+	cfg := &lintCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "lint",
+			ShortUsage: "lint [flags] <package> [<package>...]",
+			ShortHelp:  "Reports likely mistakes in the specified gno packages",
+			LongHelp: `Reports likely mistakes in the specified gno packages, whose test files are
+not linted:
+
+    unsupported-import   imports of packages that are neither gno.land packages
+                         nor standard libraries of the GnoVM
+    realm-call-param     exported realm functions that MsgCall cannot call, as
+                         a parameter is not of a primitive type or []byte
+    package-state        functions of p/ packages that modify package-level
+                         variables, as only realms have mutable state
+    orig-caller          uses of std.GetOrigCaller in p/ packages, or to
+                         authorize the caller of a realm function
+
+The path of a package is the module of its gno.mod, or else guessed from its
+directory.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execLint(cfg, args, io)
+		},
+	)
+}
+
+func (c *lintCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rootDir,
+		"root-dir",
+		"",
+		"clone location of github.com/gnolang/gno (gnodev tries to guess it)",
+	)
+}
+
+func execLint(cfg *lintCfg, args []string, io *commands.IO) error {
+	if len(args) < 1 {
+		return flag.ErrHelp
+	}
+
+	if cfg.rootDir == "" {
+		cfg.rootDir = guessRootDir()
+	}
+
+	stdlibs, err := lintStdlibs(cfg.rootDir)
+	if err != nil {
+		return err
+	}
+
+	paths, err := gnoPackagesFromArgs(args)
+	if err != nil {
+		return fmt.Errorf("list packages: %w", err)
+	}
+
+	issueCount := 0
+	for _, pkgDir := range paths {
+		issues, err := lintPackage(pkgDir, stdlibs)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pkgDir, err)
+		}
+		for _, issue := range issues {
+			io.ErrPrintfln("%s", issue)
+		}
+		issueCount += len(issues)
+	}
+
+	if issueCount > 0 {
+		return fmt.Errorf("%d lint issues", issueCount)
+	}
+	return nil
+}
+
+// lintStdlibs returns the import paths of the standard libraries, except
+// internal ones.
+func lintStdlibs(rootDir string) (map[string]bool, error) {
+	stdlibsDir := filepath.Join(rootDir, "gnovm", "stdlibs")
+	stdlibs := make(map[string]bool)
+	err := filepath.WalkDir(stdlibsDir, func(path string, f fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() && f.Name() == "internal" {
+			return filepath.SkipDir
+		}
+		if isGnoFile(f) {
+			dir, _ := filepath.Rel(stdlibsDir, filepath.Dir(path))
+			stdlibs[filepath.ToSlash(dir)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list standard libraries: %w", err)
+	}
+	return stdlibs, nil
+}
+
+type lintIssue struct {
+	pos  token.Position
+	rule string
+	msg  string
+}
+
+func (issue lintIssue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", issue.pos, issue.msg, issue.rule)
+}
+
+// lintPkg is a package being linted.
+type lintPkg struct {
+	path   string // e.g. gno.land/r/demo/foo.
+	fset   *token.FileSet
+	files  []*ast.File             // without tests.
+	types  map[string]ast.Expr     // declared types.
+	vars   map[string]bool         // package-level variables.
+	specs  map[*ast.ValueSpec]bool // of package-level variables.
+	issues []lintIssue
+}
+
+func lintPackage(pkgDir string, stdlibs map[string]bool) ([]lintIssue, error) {
+	// a file argument is linted with the files of its package.
+	if info, err := os.Stat(pkgDir); err == nil && !info.IsDir() {
+		pkgDir = filepath.Dir(pkgDir)
+	}
+	pkg := &lintPkg{
+		path:  lintPkgPath(pkgDir),
+		fset:  token.NewFileSet(),
+		types: make(map[string]ast.Expr),
+		vars:  make(map[string]bool),
+		specs: make(map[*ast.ValueSpec]bool),
+	}
+
+	entries, err := os.ReadDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !isGnoFile(entry) || strings.HasSuffix(name, "_test.gno") || strings.HasSuffix(name, "_filetest.gno") {
+			continue
+		}
+		f, err := parser.ParseFile(pkg.fset, filepath.Join(pkgDir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		pkg.files = append(pkg.files, f)
+	}
+
+	for _, f := range pkg.files {
+		pkg.collectDecls(f)
+	}
+	// tests are not deployed, and may import more packages.
+	for _, f := range pkg.files {
+		pkg.lintImports(f, stdlibs)
+		if strings.HasPrefix(pkg.path, "gno.land/r/") {
+			pkg.lintRealmCallParams(f)
+		}
+		if strings.HasPrefix(pkg.path, "gno.land/p/") {
+			pkg.lintPackageState(f)
+		}
+		pkg.lintOrigCaller(f)
+	}
+
+	sort.SliceStable(pkg.issues, func(i, j int) bool {
+		pi, pj := pkg.issues[i].pos, pkg.issues[j].pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+	return pkg.issues, nil
+}
+
+// lintPkgPath returns the module of the gno.mod of pkgDir, or else the
+// path of pkgDir from its gno.land directory.
+func lintPkgPath(pkgDir string) string {
+	if bz, err := os.ReadFile(filepath.Join(pkgDir, "gno.mod")); err == nil {
+		if path := gnomod.ModulePath(bz); path != "" {
+			return path
+		}
+	}
+	abs, err := filepath.Abs(pkgDir)
+	if err != nil {
+		return ""
+	}
+	abs = filepath.ToSlash(abs)
+	if i := strings.LastIndex(abs, "/gno.land/"); i >= 0 {
+		return abs[i+1:]
+	}
+	return ""
+}
+
+func (pkg *lintPkg) report(pos token.Pos, rule string, format string, args ...interface{}) {
+	pkg.issues = append(pkg.issues, lintIssue{
+		pos:  pkg.fset.Position(pos),
+		rule: rule,
+		msg:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (pkg *lintPkg) collectDecls(f *ast.File) {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				pkg.types[spec.Name.Name] = spec.Type
+			case *ast.ValueSpec:
+				if gd.Tok != token.VAR {
+					continue
+				}
+				pkg.specs[spec] = true
+				for _, name := range spec.Names {
+					if name.Name != "_" {
+						pkg.vars[name.Name] = true
+					}
+				}
+			}
+		}
+	}
+}
+
+func (pkg *lintPkg) lintImports(f *ast.File, stdlibs map[string]bool) {
+	for _, imp := range f.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || strings.HasPrefix(path, "gno.land/") || stdlibs[path] {
+			continue
+		}
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			pkg.report(imp.Pos(), "unsupported-import",
+				"import %q is not a gno.land package", path)
+		} else {
+			pkg.report(imp.Pos(), "unsupported-import",
+				"import %q is not a standard library of the GnoVM", path)
+		}
+	}
+}
+
+func (pkg *lintPkg) lintRealmCallParams(f *ast.File) {
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || !fd.Name.IsExported() {
+			continue
+		}
+		for _, field := range fd.Type.Params.List {
+			if pkg.isCallParamType(field.Type, make(map[string]bool)) {
+				continue
+			}
+			name := "_"
+			if len(field.Names) > 0 {
+				name = field.Names[0].Name
+			}
+			pkg.report(field.Pos(), "realm-call-param",
+				"exported function %s cannot be called with MsgCall: parameter %s has type %s",
+				fd.Name.Name, name, types.ExprString(field.Type))
+		}
+	}
+}
+
+// The types of the arguments of MsgCall, see vm.convertArgToGno.
+var lintCallParamTypes = map[string]bool{
+	"bool": true, "string": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true, "rune": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "byte": true,
+}
+
+// isCallParamType returns whether x is a type that MsgCall arguments can
+// be converted to, or a type that cannot be resolved here.
+func (pkg *lintPkg) isCallParamType(x ast.Expr, visiting map[string]bool) bool {
+	switch x := x.(type) {
+	case *ast.ParenExpr:
+		return pkg.isCallParamType(x.X, visiting)
+	case *ast.Ident:
+		if lintCallParamTypes[x.Name] {
+			return true
+		}
+		t, ok := pkg.types[x.Name]
+		if !ok || visiting[x.Name] {
+			// unknown, e.g. a float or a type parameter.
+			return !strings.HasPrefix(x.Name, "float") && !strings.HasPrefix(x.Name, "complex")
+		}
+		visiting[x.Name] = true
+		return pkg.isCallParamType(t, visiting)
+	case *ast.ArrayType:
+		elt, ok := x.Elt.(*ast.Ident)
+		return ok && (elt.Name == "byte" || elt.Name == "uint8")
+	case *ast.SelectorExpr:
+		// only the types of std are known.
+		if pkgName, ok := x.X.(*ast.Ident); ok && pkgName.Name == "std" {
+			return x.Sel.Name == "Address"
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func (pkg *lintPkg) lintPackageState(f *ast.File) {
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil || (fd.Recv == nil && fd.Name.Name == "init") {
+			continue
+		}
+		check := func(x ast.Expr) {
+			if id := pkg.packageVar(x); id != nil {
+				pkg.report(x.Pos(), "package-state",
+					"%s modifies package-level variable %s, but only realms can have mutable state",
+					fd.Name.Name, id.Name)
+			}
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if n.Tok == token.DEFINE {
+					return true
+				}
+				for _, lhs := range n.Lhs {
+					check(lhs)
+				}
+			case *ast.IncDecStmt:
+				check(n.X)
+			}
+			return true
+		})
+	}
+}
+
+// packageVar returns the package-level variable that x is part of, or nil.
+func (pkg *lintPkg) packageVar(x ast.Expr) *ast.Ident {
+	for {
+		switch xx := x.(type) {
+		case *ast.ParenExpr:
+			x = xx.X
+		case *ast.SelectorExpr:
+			x = xx.X
+		case *ast.IndexExpr:
+			x = xx.X
+		case *ast.StarExpr:
+			x = xx.X
+		case *ast.Ident:
+			if xx.Obj == nil {
+				// declared in another file.
+				if pkg.vars[xx.Name] {
+					return xx
+				}
+				return nil
+			}
+			if spec, ok := xx.Obj.Decl.(*ast.ValueSpec); ok && pkg.specs[spec] && xx.Name != "_" {
+				return xx
+			}
+			return nil
+		default:
+			return nil
+		}
+	}
+}
+
+func (pkg *lintPkg) lintOrigCaller(f *ast.File) {
+	isPackage := strings.HasPrefix(pkg.path, "gno.land/p/")
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		// a check that the caller signed the transaction makes the
+		// original caller the caller.
+		assertsOrigin := false
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			if isStdCall(n, "AssertOriginCall") {
+				assertsOrigin = true
+			}
+			return !assertsOrigin
+		})
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				if isPackage && isStdCall(n, "GetOrigCaller") {
+					pkg.report(n.Pos(), "orig-caller",
+						"std.GetOrigCaller in a package returns the signer of the transaction, not the caller of %s",
+						fd.Name.Name)
+				}
+			case *ast.BinaryExpr:
+				if isPackage || assertsOrigin || (n.Op != token.EQL && n.Op != token.NEQ) {
+					return true
+				}
+				if isStdCall(n.X, "GetOrigCaller") || isStdCall(n.Y, "GetOrigCaller") {
+					pkg.report(n.Pos(), "orig-caller",
+						"std.GetOrigCaller authorizes the signer of the transaction even if %s is called by another realm; "+
+							"call std.AssertOriginCall first, or check std.GetCallerAt(2)",
+						fd.Name.Name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// isStdCall returns whether n is a call of the function name of std.
+func isStdCall(n ast.Node, name string) bool {
+	cx, ok := n.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sx, ok := cx.Fun.(*ast.SelectorExpr)
+	if !ok || sx.Sel.Name != name {
+		return false
+	}
+	id, ok := sx.X.(*ast.Ident)
+	return ok && id.Name == "std"
+}
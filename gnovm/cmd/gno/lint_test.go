@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestLint(t *testing.T) {
+	tc := []testMainCase{
+		{
+			args:        []string{"lint"},
+			errShouldBe: "flag: help requested",
+		},
+		{
+			args: []string{"lint", "../../tests/integ/valid1"},
+		},
+		{
+			args:        []string{"lint", "../../tests/integ/lint-realm"},
+			errShouldBe: "4 lint issues",
+			stderrShouldBe: `../../tests/integ/lint-realm/lintrealm.gno:4:2: import "os" is not a standard library of the GnoVM (unsupported-import)
+../../tests/integ/lint-realm/lintrealm.gno:20:5: std.GetOrigCaller authorizes the signer of the transaction even if Add is called by another realm; call std.AssertOriginCall first, or check std.GetCallerAt(2) (orig-caller)
+../../tests/integ/lint-realm/lintrealm.gno:34:19: exported function Scale cannot be called with MsgCall: parameter factor has type float64 (realm-call-param)
+../../tests/integ/lint-realm/lintrealm.gno:34:35: exported function Scale cannot be called with MsgCall: parameter opts has type *Options (realm-call-param)
+`,
+		},
+		{
+			args:        []string{"lint", "../../tests/integ/lint-pkg"},
+			errShouldBe: "3 lint issues",
+			stderrShouldBe: `../../tests/integ/lint-pkg/lintpkg.gno:15:2: Next modifies package-level variable counter, but only realms can have mutable state (package-state)
+../../tests/integ/lint-pkg/lintpkg.gno:20:2: Register modifies package-level variable registry, but only realms can have mutable state (package-state)
+../../tests/integ/lint-pkg/lintpkg.gno:20:19: std.GetOrigCaller in a package returns the signer of the transaction, not the caller of Register (orig-caller)
+`,
+		},
+	}
+	testMainCaseRun(t, tc)
+}
@@ -34,17 +34,19 @@ func newGnodevCmd(io *commands.IO) *commands.Command {
 		newPrecompileCmd(io),
 		newTestCmd(io),
 		newModCmd(io),
-		newReplCmd(),
-		// fmt -- gofmt
+		newDebugCmd(io),
+		newFmtCmd(io),
+		newLintCmd(io),
+		newDocCmd(io),
+		newDevCmd(io),
+		newReplCmd(io),
+		newRenderCmd(io),
 		// clean
 		// graph
 		// vendor -- download deps from the chain in vendor/
 		// list -- list packages
-		// render -- call render()?
 		// publish/release
 		// generate
-		// doc -- godoc
-		// "vm" -- starts an in-memory chain that can be interacted with?
 		// bug -- start a bug report
 		// version -- show gnodev, golang versions
 	)
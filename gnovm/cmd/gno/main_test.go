@@ -23,6 +23,7 @@ func TestMain_Gnodev(t *testing.T) {
 
 type testMainCase struct {
 	args                 []string
+	stdin                string
 	testDir              string
 	simulateExternalRepo bool
 
@@ -119,6 +120,9 @@ func testMainCaseRun(t *testing.T, tc []testMainCase) {
 			}
 
 			io := commands.NewTestIO()
+			if test.stdin != "" {
+				io.SetIn(strings.NewReader(test.stdin))
+			}
 			io.SetOut(commands.WriteNopCloser(mockOut))
 			io.SetErr(commands.WriteNopCloser(mockErr))
 
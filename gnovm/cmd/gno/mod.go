@@ -16,6 +16,14 @@ type modDownloadCfg struct {
 	remote string
 }
 
+type modGraphCfg struct {
+	remote string
+}
+
+type modVendorCfg struct {
+	remote string
+}
+
 func newModCmd(io *commands.IO) *commands.Command {
 	cmd := commands.NewCommand(
 		commands.Metadata{
@@ -29,6 +37,9 @@ func newModCmd(io *commands.IO) *commands.Command {
 
 	cmd.AddSubCommands(
 		newModDownloadCmd(io),
+		newModTidyCmd(io),
+		newModGraphCmd(io),
+		newModVendorCmd(io),
 	)
 
 	return cmd
@@ -68,28 +79,9 @@ func execModDownload(cfg *modDownloadCfg, args []string, io *commands.IO) error
 	if err != nil {
 		return err
 	}
-	modPath := filepath.Join(path, "gno.mod")
-	if !isFileExist(modPath) {
-		return errors.New("gno.mod not found")
-	}
-
-	// read gno.mod
-	data, err := os.ReadFile(modPath)
+	gnoMod, err := readGnoMod(path)
 	if err != nil {
-		return fmt.Errorf("readfile %q: %w", modPath, err)
-	}
-
-	// parse gno.mod
-	gnoMod, err := gnomod.Parse(modPath, data)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-	// sanitize gno.mod
-	gnoMod.Sanitize()
-
-	// validate gno.mod
-	if err := gnoMod.Validate(); err != nil {
-		return fmt.Errorf("validate: %w", err)
+		return err
 	}
 
 	// fetch dependencies
@@ -110,3 +102,182 @@ func execModDownload(cfg *modDownloadCfg, args []string, io *commands.IO) error
 
 	return nil
 }
+
+func newModTidyCmd(io *commands.IO) *commands.Command {
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "tidy",
+			ShortUsage: "tidy",
+			ShortHelp:  "Set the requirements of gno.mod to the imports of the package",
+			LongHelp: `Sets the requirements of gno.mod to the gno.land packages imported by the
+gno files of the package, including its tests, keeping the versions of the
+packages already required.`,
+		},
+		commands.NewEmptyConfig(),
+		func(_ context.Context, args []string) error {
+			return execModTidy(args, io)
+		},
+	)
+}
+
+func execModTidy(args []string, io *commands.IO) error {
+	if len(args) > 0 {
+		return flag.ErrHelp
+	}
+
+	path, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	gnoMod, err := readGnoMod(path)
+	if err != nil {
+		return err
+	}
+
+	imports, err := gnomod.ReadImports(path)
+	if err != nil {
+		return fmt.Errorf("read imports: %w", err)
+	}
+	gnoMod.Tidy(imports)
+
+	modPath := filepath.Join(path, "gno.mod")
+	if err := os.WriteFile(modPath, gnoMod.Format(), 0o644); err != nil {
+		return fmt.Errorf("writefile %q: %w", modPath, err)
+	}
+	return nil
+}
+
+func newModGraphCmd(io *commands.IO) *commands.Command {
+	cfg := &modGraphCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "graph",
+			ShortUsage: "graph [flags]",
+			ShortHelp:  "Print the graph of the requirements",
+			LongHelp: `Prints the graph of the requirements, one edge per line, as the path of a
+package followed by the path of a package it requires: the requirements of
+gno.mod, and the imports of the packages they require, recursively.
+
+The packages are read from the directories replacing them in gno.mod, from
+the vendor directory, or else fetched from the remote.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execModGraph(cfg, args, io)
+		},
+	)
+}
+
+func (c *modGraphCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.remote,
+		"remote",
+		"test3.gno.land:36657",
+		"remote for fetching gno modules",
+	)
+}
+
+func execModGraph(cfg *modGraphCfg, args []string, io *commands.IO) error {
+	if len(args) > 0 {
+		return flag.ErrHelp
+	}
+
+	path, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	gnoMod, err := readGnoMod(path)
+	if err != nil {
+		return err
+	}
+
+	edges, err := gnoMod.Graph(path, cfg.remote)
+	if err != nil {
+		return fmt.Errorf("graph: %w", err)
+	}
+	for _, edge := range edges {
+		io.Println(edge[0], edge[1])
+	}
+	return nil
+}
+
+func newModVendorCmd(io *commands.IO) *commands.Command {
+	cfg := &modVendorCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "vendor",
+			ShortUsage: "vendor [flags]",
+			ShortHelp:  "Copy the required packages in the vendor directory",
+			LongHelp: `Copies the packages required by gno.mod, and the packages they import,
+recursively, in the vendor directory, e.g. in vendor/gno.land/p/demo/avl,
+replacing its content, for offline use.
+
+The packages are read from the directories replacing them in gno.mod, or else
+fetched from the remote.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execModVendor(cfg, args, io)
+		},
+	)
+}
+
+func (c *modVendorCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.remote,
+		"remote",
+		"test3.gno.land:36657",
+		"remote for fetching gno modules",
+	)
+}
+
+func execModVendor(cfg *modVendorCfg, args []string, io *commands.IO) error {
+	if len(args) > 0 {
+		return flag.ErrHelp
+	}
+
+	path, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	gnoMod, err := readGnoMod(path)
+	if err != nil {
+		return err
+	}
+
+	if err := gnoMod.Vendor(path, cfg.remote); err != nil {
+		return fmt.Errorf("vendor: %w", err)
+	}
+	return nil
+}
+
+// readGnoMod reads, sanitizes and validates the gno.mod of the directory
+// path.
+func readGnoMod(path string) (*gnomod.File, error) {
+	modPath := filepath.Join(path, "gno.mod")
+	if !isFileExist(modPath) {
+		return nil, errors.New("gno.mod not found")
+	}
+
+	// read gno.mod
+	data, err := os.ReadFile(modPath)
+	if err != nil {
+		return nil, fmt.Errorf("readfile %q: %w", modPath, err)
+	}
+
+	// parse gno.mod
+	gnoMod, err := gnomod.Parse(modPath, data)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	// sanitize gno.mod
+	gnoMod.Sanitize()
+
+	// validate gno.mod
+	if err := gnoMod.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+	return gnoMod, nil
+}
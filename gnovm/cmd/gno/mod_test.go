@@ -72,6 +72,39 @@ func TestModApp(t *testing.T) {
 			simulateExternalRepo: true,
 			errShouldContain:     "fetch: writepackage: querychain:",
 		},
+
+		// test gno mod tidy, graph and vendor
+		{
+			args:                 []string{"mod", "tidy"},
+			testDir:              "../../tests/integ/empty-dir",
+			simulateExternalRepo: true,
+			errShouldBe:          "gno.mod not found",
+		},
+		{
+			args:                 []string{"mod", "tidy"},
+			testDir:              "../../tests/integ/mod-deps",
+			simulateExternalRepo: true,
+		},
+		{
+			args:                 []string{"mod", "graph"},
+			testDir:              "../../tests/integ/mod-deps",
+			simulateExternalRepo: true,
+			stdoutShouldBe: `gno.land/r/demo/moddeps gno.land/p/demo/dep1
+gno.land/r/demo/moddeps gno.land/p/demo/unused
+gno.land/p/demo/dep1 gno.land/p/demo/dep2
+`,
+		},
+		{
+			args:                 []string{"mod", "graph", "-remote", ""},
+			testDir:              "../../tests/integ/require-remote-module",
+			simulateExternalRepo: true,
+			errShouldBe:          "graph: gno.land/p/demo/avl: package gno.land/p/demo/avl not found in vendor, and no remote",
+		},
+		{
+			args:                 []string{"mod", "vendor"},
+			testDir:              "../../tests/integ/mod-deps",
+			simulateExternalRepo: true,
+		},
 	}
 	testMainCaseRun(t, tc)
 }
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/cmd/gnoweb/static"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/tests"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gotuna/gotuna"
+)
+
+type renderCfg struct {
+	rootDir  string
+	web      string
+	viewsDir string
+}
+
+func newRenderCmd(io *commands.IO) *commands.Command {
+	cfg := &renderCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "render",
+			ShortUsage: "render [flags] <pkgdir> [<path>]",
+			ShortHelp:  "Renders a realm from a local package",
+			LongHelp: `Runs the realm of the directory pkgdir in memory, with its imports from the
+standard libraries and the examples, and prints the markdown of its
+Render(path).
+
+With -web, it serves the rendering of the realm with the templates of
+gnoweb instead, e.g. http://127.0.0.1:8888/r/demo/boards:path, and runs the
+realm again on each request to show the changes of its files.
+
+The package path of the realm is the module of its gno.mod.`,
+		},
+		cfg,
+		func(ctx context.Context, args []string) error {
+			return execRender(ctx, cfg, args, io)
+		},
+	)
+}
+
+func (c *renderCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rootDir,
+		"root-dir",
+		"",
+		"clone location of github.com/gnolang/gno (gnodev tries to guess it)",
+	)
+
+	fs.StringVar(
+		&c.web,
+		"web",
+		"",
+		"address to serve the rendering on with the templates of gnoweb (e.g. 127.0.0.1:8888)",
+	)
+
+	fs.StringVar(
+		&c.viewsDir,
+		"views-dir",
+		"",
+		"views directory of gnoweb (default <root-dir>/gno.land/cmd/gnoweb/views)",
+	)
+}
+
+func execRender(ctx context.Context, cfg *renderCfg, args []string, io *commands.IO) error {
+	if len(args) < 1 || len(args) > 2 {
+		return flag.ErrHelp
+	}
+
+	if cfg.rootDir == "" {
+		cfg.rootDir = guessRootDir()
+	}
+	if cfg.viewsDir == "" {
+		cfg.viewsDir = filepath.Join(cfg.rootDir, "gno.land", "cmd", "gnoweb", "views")
+	}
+
+	dir := args[0]
+	if cfg.web != "" {
+		if len(args) > 1 {
+			return flag.ErrHelp
+		}
+		return serveRender(ctx, cfg, dir, io)
+	}
+
+	path := ""
+	if len(args) > 1 {
+		path = args[1]
+	}
+	res, err := renderRealm(cfg.rootDir, dir, path, io.Err)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(res, "\n") {
+		res += "\n"
+	}
+	_, err = io.Out.Write([]byte(res))
+	return err
+}
+
+// renderRealm runs the realm in dir in a new store, and returns its
+// Render(path). The prints of the realm are written to output.
+func renderRealm(rootDir string, dir string, path string, output io.Writer) (res string, err error) {
+	pkgPath := lintPkgPath(dir)
+	if pkgPath == "" {
+		return "", fmt.Errorf("%s: no package path, add a gno.mod with its module", dir)
+	}
+	if !gno.IsRealmPath(pkgPath) {
+		return "", fmt.Errorf("%s: %s is not a realm", dir, pkgPath)
+	}
+	xx, err := gno.ParseExpr(fmt.Sprintf("Render(%q)", path))
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: %v", pkgPath, r)
+		}
+	}()
+	mpkg := gno.ReadMemPackage(dir, pkgPath)
+	store := tests.TestStore(rootDir, "", nil, output, output, tests.ImportModeStdlibsOnly)
+	m := tests.TestMachine(store, output, pkgPath)
+	defer m.Release()
+	m.RunMemPackage(mpkg, true)
+
+	rtvs := m.Eval(xx)
+	if len(rtvs) != 1 || rtvs[0].T == nil || rtvs[0].T.Kind() != gno.StringKind {
+		return "", fmt.Errorf("%s: Render must return a string", pkgPath)
+	}
+	return rtvs[0].GetString(), nil
+}
+
+// serveRender serves the rendering of the realm in dir on cfg.web, until
+// ctx is done.
+func serveRender(ctx context.Context, cfg *renderCfg, dir string, io *commands.IO) error {
+	handler, err := renderHandler(cfg, dir, io.Err)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:              cfg.web,
+		ReadHeaderTimeout: 60 * time.Second,
+		Handler:           handler,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	io.ErrPrintfln("Running on http://%s", cfg.web)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// renderHandler returns the handler of the rendering of the realm in dir,
+// at the paths of gnoweb: /r/<name> and /r/<name>:<path>.
+func renderHandler(cfg *renderCfg, dir string, output io.Writer) (http.Handler, error) {
+	pkgPath := lintPkgPath(dir)
+	if !gno.IsRealmPath(pkgPath) {
+		return nil, fmt.Errorf("%s: %q is not a realm", dir, pkgPath)
+	}
+	rlmname := strings.TrimPrefix(pkgPath, "gno.land/r/")
+	app := gotuna.App{
+		ViewFiles: os.DirFS(cfg.viewsDir),
+		Static:    static.EmbeddedStatic,
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/static/", http.StripPrefix("/static", http.FileServer(http.FS(app.Static))))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		query := strings.TrimPrefix(r.URL.Path, "/r/"+rlmname)
+		if query == r.URL.Path || (query != "" && !strings.HasPrefix(query, ":")) || query == ":" {
+			http.Redirect(w, r, "/r/"+rlmname, http.StatusFound)
+			return
+		}
+		query = strings.TrimPrefix(query, ":")
+
+		res, err := renderRealm(cfg.rootDir, dir, query, output)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "%s\n", err)
+			return
+		}
+		// linkify query, as gnoweb does.
+		var pathLinks []pathLink
+		if query != "" {
+			parts := strings.Split(query, "/")
+			for i, part := range parts {
+				pathLinks = append(pathLinks, pathLink{
+					URL:  "/r/" + rlmname + ":" + strings.Join(parts[:i+1], "/"),
+					Text: part,
+				})
+			}
+		}
+		tmpl := app.NewTemplatingEngine()
+		tmpl.Set("RealmName", rlmname)
+		tmpl.Set("RealmPath", pkgPath)
+		tmpl.Set("Query", query)
+		tmpl.Set("PathLinks", pathLinks)
+		tmpl.Set("Contents", res)
+		tmpl.Render(w, r, "realm_render.html", "funcs.html")
+	})
+	return mux, nil
+}
+
+type pathLink struct {
+	URL  string
+	Text string
+}
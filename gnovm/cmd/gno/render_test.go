@@ -0,0 +1,76 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
+)
+
+func TestRenderApp(t *testing.T) {
+	tc := []testMainCase{
+		{
+			args:        []string{"render"},
+			errShouldBe: "flag: help requested",
+		},
+		{
+			args:           []string{"render", "-root-dir", "../../..", "../../tests/integ/render"},
+			stdoutShouldBe: "# Home of gno.land/r/demo/render\n\nchain: dev\n",
+		},
+		{
+			args:           []string{"render", "-root-dir", "../../..", "../../tests/integ/render", "a/b"},
+			stdoutShouldBe: "# a/b\n\n<b>2</b> parts\n",
+		},
+		{
+			args:        []string{"render", "-root-dir", "../../..", "../../tests/integ/valid1"},
+			errShouldBe: "../../tests/integ/valid1: no package path, add a gno.mod with its module",
+		},
+		{
+			args:        []string{"render", "-root-dir", "../../..", "../../tests/integ/lint-pkg"},
+			errShouldBe: "../../tests/integ/lint-pkg: gno.land/p/demo/lintpkg is not a realm",
+		},
+	}
+	testMainCaseRun(t, tc)
+}
+
+func TestRenderHandler(t *testing.T) {
+	cfg := &renderCfg{
+		rootDir:  "../../..",
+		viewsDir: "../../../gno.land/cmd/gnoweb/views",
+	}
+	handler, err := renderHandler(cfg, "../../tests/integ/render", io.Discard)
+	require.NoError(t, err)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	get := func(path string) (*http.Response, string) {
+		t.Helper()
+		res, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, string(body)
+	}
+
+	res, _ := get("/")
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	assert.Equal(t, "/r/demo/render", res.Header.Get("Location"))
+
+	res, body := get("/r/demo/render:a/b")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, body, "# a/b\n\n&lt;b&gt;2&lt;/b&gt; parts")
+	assert.Contains(t, body, `<a href="/r/demo/render:a">a</a>`)
+
+	res, _ = get("/static/js/marked.min.js")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	_, err = renderHandler(cfg, "../../tests/integ/lint-pkg", io.Discard)
+	assert.Error(t, err)
+}
@@ -1,25 +1,35 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	goerrors "errors"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	gotoken "go/token"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/gnolang/gno/gnovm/pkg/doc"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/tests"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/std"
 	"golang.org/x/term"
 )
 
 type replCfg struct {
 	verbose bool
 	rootDir string
+	remote  string
 }
 
-func newReplCmd() *commands.Command {
+func newReplCmd(io *commands.IO) *commands.Command {
 	cfg := &replCfg{}
 
 	return commands.NewCommand(
@@ -27,10 +37,19 @@ func newReplCmd() *commands.Command {
 			Name:       "repl",
 			ShortUsage: "repl [flags]",
 			ShortHelp:  "Starts a GnoVM REPL",
+			LongHelp: `Starts a GnoVM REPL session.
+
+The declarations and imports of the inputs are kept in the session. An input
+can span several lines, until its brackets are balanced. The results of an
+expression are printed.
+
+Type :help in the session for its commands. With -remote, the imported
+packages which are not in the examples are fetched from a node, and run in
+the session: they do not have their state on the chain.`,
 		},
 		cfg,
 		func(_ context.Context, args []string) error {
-			return execRepl(cfg, args)
+			return execRepl(cfg, args, io)
 		},
 	)
 }
@@ -49,9 +68,16 @@ func (c *replCfg) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"clone location of github.com/gnolang/gno (gnodev tries to guess it)",
 	)
+
+	fs.StringVar(
+		&c.remote,
+		"remote",
+		"",
+		"address of the node to fetch imported packages from (e.g. test3.gno.land:36657)",
+	)
 }
 
-func execRepl(cfg *replCfg, args []string) error {
+func execRepl(cfg *replCfg, args []string, io *commands.IO) error {
 	if len(args) > 0 {
 		return flag.ErrHelp
 	}
@@ -60,59 +86,496 @@ func execRepl(cfg *replCfg, args []string) error {
 		cfg.rootDir = guessRootDir()
 	}
 
-	return runRepl(cfg.rootDir, cfg.verbose)
+	r := newRepl(cfg, io)
+	// the machine of r changes on :reset.
+	defer func() { r.m.Release() }()
+
+	if f, ok := io.In.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return r.runTerm(f, io.Err)
+	}
+	return r.run(io.In)
 }
 
-func runRepl(rootDir string, verbose bool) error {
-	stdin := os.Stdin
-	stdout := os.Stdout
-	stderr := os.Stderr
+const replHelp = `Commands:
+  :help              shows this help
+  :reset             resets the session
+  :load <file.gno>   loads the imports and declarations of a file
+  :type <expr>       shows the type of an expression
+`
+
+// A repl is a session of gno repl. The declarations of its inputs are run
+// as the files of the package of its machine, with the imports of the
+// session.
+type repl struct {
+	cfg *replCfg
+	in  io.Reader
+	out io.Writer
+	err io.Writer
 
-	// init store and machine
-	testStore := tests.TestStore(rootDir, "", stdin, stdout, stderr, tests.ImportModeStdlibsOnly)
-	if verbose {
-		testStore.SetLogStoreOps(true)
+	store    gno.Store
+	m        *gno.Machine
+	pv       *gno.PackageValue // the package of the session.
+	imports  []string          // the import specs, e.g. `"strings"`.
+	declared map[string]bool   // the names declared in the session.
+	nfiles   int               // the number of files run.
+	lastFile gno.Name          // the last file with all the imports.
+}
+
+func newRepl(cfg *replCfg, io *commands.IO) *repl {
+	r := &repl{cfg: cfg, in: io.In, out: io.Out, err: io.Err}
+	r.reset()
+	return r
+}
+
+// reset starts a new session.
+func (r *repl) reset() {
+	if r.m != nil {
+		r.m.Release()
 	}
-	m := gno.NewMachineWithOptions(gno.MachineOptions{
-		PkgPath: "test",
-		Output:  stdout,
-		Store:   testStore,
+	r.store = tests.TestStore(r.cfg.rootDir, "", r.in, r.out, r.err, tests.ImportModeStdlibsOnly)
+	if r.cfg.verbose {
+		r.store.SetLogStoreOps(true)
+	}
+	r.m = gno.NewMachineWithOptions(gno.MachineOptions{
+		PkgPath: "main",
+		Output:  r.out,
+		Store:   r.store,
 	})
+	r.pv = r.m.Package
+	r.imports = nil
+	r.declared = make(map[string]bool)
+	r.nfiles = 0
+	// a first file, so that expressions can be evaluated.
+	if err := r.runFile(""); err != nil {
+		panic(err)
+	}
+}
 
-	defer m.Release()
+// run reads the inputs from in until EOF.
+func (r *repl) run(in io.Reader) error {
+	br := bufio.NewReader(in)
+	var input string
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			input += line
+			if !incomplete(input) {
+				r.handle(input)
+				input = ""
+			}
+		}
+		if goerrors.Is(err, io.EOF) {
+			if strings.TrimSpace(input) != "" {
+				r.handle(input)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
 
-	// init termui
+// runTerm reads the inputs from the terminal f, writing the prompts to w.
+func (r *repl) runTerm(f *os.File, w io.Writer) error {
 	rw := struct {
 		io.Reader
 		io.Writer
-	}{os.Stdin, os.Stderr}
+	}{f, w}
 	t := term.NewTerminal(rw, "")
 
-	// main loop
-	for i := 1; ; i++ {
-		// parse line and execute
-		t.SetPrompt(fmt.Sprintf("gno:%d> ", i))
-		oldState, err := term.MakeRaw(0)
-		input, err := t.ReadLine()
+	var input string
+	for i := 1; ; {
+		if input == "" {
+			t.SetPrompt(fmt.Sprintf("gno:%d> ", i))
+		} else {
+			t.SetPrompt("...   ")
+		}
+		oldState, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return fmt.Errorf("term error: %w", err)
+		}
+		line, err := t.ReadLine()
+		term.Restore(int(f.Fd()), oldState)
 		if err != nil {
-			term.Restore(0, oldState)
 			if goerrors.Is(err, io.EOF) {
 				return nil
 			}
 			return fmt.Errorf("term error: %w", err)
 		}
-		term.Restore(0, oldState)
 
-		funcName := fmt.Sprintf("repl_%d", i)
-		src := "package test\nfunc " + funcName + "() {\n" + input + "\n}"
-		// FIXME: support ";" as line separator?
-		// FIXME: gofmt as linter + formatter
-		// FIXME: support multiline when unclosed parenthesis, etc
+		input += line + "\n"
+		if incomplete(input) {
+			continue
+		}
+		r.handle(input)
+		input = ""
+		i++
+	}
+}
 
-		n := gno.MustParseFile(funcName+".gno", src)
-		m.RunFiles(n)
-		m.RunStatement(gno.S(gno.Call(gno.X(funcName))))
+// incomplete returns whether the input has unbalanced brackets, or an
+// unterminated raw string or comment, and so continues on the next line.
+func incomplete(input string) bool {
+	if strings.HasPrefix(strings.TrimSpace(input), ":") {
+		return false
+	}
+	fset := gotoken.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(input))
+	var s scanner.Scanner
+	unterminated := false
+	s.Init(file, []byte(input), func(_ gotoken.Position, msg string) {
+		if strings.HasSuffix(msg, "not terminated") {
+			unterminated = true
+		}
+	}, 0)
+	depth := 0
+	for {
+		_, tok, _ := s.Scan()
+		switch tok {
+		case gotoken.LPAREN, gotoken.LBRACE, gotoken.LBRACK:
+			depth++
+		case gotoken.RPAREN, gotoken.RBRACE, gotoken.RBRACK:
+			depth--
+		case gotoken.EOF:
+			return depth > 0 || unterminated
+		}
 	}
+}
 
+// handle runs an input of the session, and prints its errors.
+func (r *repl) handle(input string) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return
+	}
+	var err error
+	if strings.HasPrefix(input, ":") {
+		err = r.command(input)
+	} else {
+		err = r.eval(input)
+	}
+	if err != nil {
+		fmt.Fprintf(r.err, "error: %v\n", err)
+	}
+}
+
+// command runs a command of the session, e.g. ":reset".
+func (r *repl) command(input string) error {
+	name, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case ":help":
+		fmt.Fprint(r.out, replHelp)
+	case ":reset":
+		r.reset()
+	case ":load":
+		if arg == "" {
+			return fmt.Errorf("usage: :load <file.gno>")
+		}
+		bz, err := os.ReadFile(arg)
+		if err != nil {
+			return err
+		}
+		return r.declare(string(bz))
+	case ":type":
+		if arg == "" {
+			return fmt.Errorf("usage: :type <expr>")
+		}
+		return r.printType(arg)
+	default:
+		return fmt.Errorf("unknown command %s, see :help", name)
+	}
 	return nil
 }
+
+// eval runs an input which is declarations, an expression, or statements.
+func (r *repl) eval(input string) error {
+	if _, err := parser.ParseFile(gotoken.NewFileSet(), "", "package main\n"+input, 0); err == nil {
+		return r.declare("package main\n" + input)
+	}
+	if _, err := parser.ParseExpr(input); err == nil {
+		return r.printExpr(input)
+	}
+
+	src := "package main\nfunc _() {\n" + input + "\n}"
+	fset := gotoken.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return err
+	}
+	stmts := f.Decls[0].(*ast.FuncDecl).Body.List
+	// a short variable declaration declares variables of the session.
+	if as, ok := stmts[0].(*ast.AssignStmt); ok && len(stmts) == 1 && as.Tok == gotoken.DEFINE {
+		var names []string
+		isNew := false
+		for _, lhs := range as.Lhs {
+			id, ok := lhs.(*ast.Ident)
+			if !ok {
+				return fmt.Errorf("non-name %s on left side of :=", src[fset.Position(lhs.Pos()).Offset:fset.Position(lhs.End()).Offset])
+			}
+			names = append(names, id.Name)
+			isNew = isNew || (id.Name != "_" && !r.declared[id.Name])
+		}
+		rhs := src[fset.Position(as.Rhs[0].Pos()).Offset:fset.Position(as.Rhs[len(as.Rhs)-1].End()).Offset]
+		if isNew {
+			return r.declare("package main\nvar " + strings.Join(names, ", ") + " = " + rhs)
+		}
+		input = strings.Join(names, ", ") + " = " + rhs
+	}
+	return r.exec(input)
+}
+
+// declare runs the imports and declarations of the file src.
+func (r *repl) declare(src string) error {
+	fset := gotoken.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return err
+	}
+	offset := func(pos gotoken.Pos) int { return fset.Position(pos).Offset }
+
+	var imports []string
+	for _, spec := range f.Imports {
+		imp := src[offset(spec.Pos()):offset(spec.End())]
+		if !contains(r.imports, imp) && !contains(imports, imp) {
+			imports = append(imports, imp)
+		}
+	}
+	var names []string
+	var body strings.Builder
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok == gotoken.IMPORT {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, id := range spec.Names {
+						names = append(names, id.Name)
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil && decl.Name.Name != "init" {
+				names = append(names, decl.Name.Name)
+			}
+		}
+		body.WriteString(src[offset(decl.Pos()):offset(decl.End())])
+		body.WriteString("\n")
+	}
+	for _, name := range names {
+		if name != "_" && r.declared[name] {
+			return fmt.Errorf("%s redeclared in this session", name)
+		}
+	}
+
+	if len(imports) > 0 {
+		for _, imp := range imports {
+			if err := r.loadRemote(importSpecPath(imp)); err != nil {
+				return err
+			}
+		}
+		// check the imports, and keep a file with all of them.
+		if err := r.runFile("", imports...); err != nil {
+			return err
+		}
+		r.imports = append(r.imports, imports...)
+	}
+	if body.Len() == 0 {
+		return nil
+	}
+	if err := r.runFile(body.String()); err != nil {
+		return err
+	}
+	for _, name := range names {
+		r.declared[name] = true
+	}
+	return nil
+}
+
+// exec runs statements in a function of a new file of the session.
+func (r *repl) exec(stmts string) error {
+	fname := fmt.Sprintf("repl_%d", r.nfiles+1)
+	if err := r.runFile("func " + fname + "() {\n" + stmts + "\n}"); err != nil {
+		return err
+	}
+	return r.catch(func() {
+		r.m.RunStatement(gno.S(gno.Call(gno.X(fname))))
+	})
+}
+
+// printExpr evaluates the expression x and prints its results.
+func (r *repl) printExpr(x string) error {
+	expr, err := gno.ParseExpr(x)
+	if err != nil {
+		return err
+	}
+	var res []gno.TypedValue
+	err = r.catch(func() {
+		r.m.PushBlock(r.pv.GetFileBlock(r.store, r.lastFile))
+		res = r.m.Eval(expr)
+		r.m.PopBlock()
+	})
+	if err != nil {
+		return err
+	}
+	for _, tv := range res {
+		fmt.Fprintln(r.out, tv.String())
+	}
+	return nil
+}
+
+// printType prints the type of the expression x.
+func (r *repl) printType(x string) error {
+	expr, err := gno.ParseExpr(x)
+	if err != nil {
+		return err
+	}
+	return r.catch(func() {
+		fn := r.pv.GetFileBlock(r.store, r.lastFile).GetSource(r.store)
+		expr = gno.Preprocess(r.store, fn, expr).(gno.Expr)
+		t := r.m.EvalStaticTypeOf(fn, expr)
+		if t == nil {
+			fmt.Fprintln(r.out, "nil")
+		} else {
+			fmt.Fprintln(r.out, t.String())
+		}
+	})
+}
+
+// runFile runs a new file of the session with the declarations body, and
+// the imports of the session and imports. A file which fails is removed
+// from the package.
+func (r *repl) runFile(body string, imports ...string) error {
+	r.nfiles++
+	name := fmt.Sprintf("repl_%d.gno", r.nfiles)
+	var src strings.Builder
+	src.WriteString("package main\n")
+	for _, imp := range append(r.imports[:len(r.imports):len(r.imports)], imports...) {
+		fmt.Fprintf(&src, "import %s\n", imp)
+	}
+	src.WriteString(body)
+
+	fn, err := gno.ParseFile(name, src.String())
+	if err != nil {
+		return err
+	}
+	pn := r.pv.GetBlock(r.store).GetSource(r.store).(*gno.PackageNode)
+	nfiles := 0
+	if pn.FileSet != nil {
+		nfiles = len(pn.FileSet.Files)
+	}
+	err = r.catch(func() {
+		r.m.RunFiles(fn)
+	})
+	if err != nil {
+		if pn.FileSet != nil && len(pn.FileSet.Files) > nfiles {
+			pn.FileSet.Files = pn.FileSet.Files[:nfiles]
+		}
+		return err
+	}
+	if len(imports) > 0 || body == "" {
+		r.lastFile = gno.Name(name)
+	}
+	return nil
+}
+
+// catch runs fn, and returns the panic of fn as an error. The machine is
+// then emptied, to run the next inputs.
+func (r *repl) catch(fn func()) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			m := r.m
+			m.NumOps, m.NumValues, m.NumResults = 0, 0, 0
+			m.Exprs, m.Stmts, m.Frames, m.Blocks = nil, nil, nil, nil
+			m.Exception = nil
+			m.SetActivePackage(r.pv)
+			if e, ok := rec.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v", rec)
+			}
+		}
+	}()
+	fn()
+	return nil
+}
+
+// loadRemote fetches the package pkgPath from the remote, if it is not in
+// the store, with the packages it imports, and runs it in the store.
+func (r *repl) loadRemote(pkgPath string) error {
+	if r.cfg.remote == "" || !strings.HasPrefix(pkgPath, "gno.land/") {
+		return nil
+	}
+	var pv *gno.PackageValue
+	if err := r.catch(func() { pv = r.store.GetPackage(pkgPath, false) }); err != nil {
+		return err
+	}
+	if pv != nil {
+		return nil
+	}
+
+	mpkg, err := doc.FetchMemPackage(pkgPath, func(path string) (string, error) {
+		return queryFile(r.cfg.remote, path)
+	})
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", pkgPath, err)
+	}
+	var files []*std.MemFile
+	fset := gotoken.NewFileSet()
+	for _, mfile := range mpkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") || strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, mfile.Name, mfile.Body, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		mpkg.Name = f.Name.Name
+		for _, spec := range f.Imports {
+			if err := r.loadRemote(importSpecPath(spec.Path.Value)); err != nil {
+				return err
+			}
+		}
+		files = append(files, mfile)
+	}
+	mpkg.Files = files
+
+	return r.catch(func() {
+		m2 := gno.NewMachineWithOptions(gno.MachineOptions{
+			PkgPath: "main",
+			Output:  r.out,
+			Store:   r.store,
+		})
+		defer m2.Release()
+		m2.RunMemPackage(mpkg, true)
+	})
+}
+
+// importSpecPath returns the path of the import spec imp, e.g. `str "strings"`.
+func importSpecPath(imp string) string {
+	i := strings.IndexByte(imp, '"')
+	if i < 0 {
+		i = strings.IndexByte(imp, '`')
+	}
+	path, err := strconv.Unquote(imp[i:])
+	if err != nil {
+		return imp
+	}
+	return path
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
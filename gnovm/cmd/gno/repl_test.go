@@ -1,13 +1,73 @@
 package main
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/jaekwon/testify/assert"
+)
 
 func TestReplApp(t *testing.T) {
 	tc := []testMainCase{
 		{args: []string{"repl", "invalid-arg"}, errShouldBe: "flag: help requested"},
 
-		// args
-		// {args: []string{"repl", "..."}, stdoutShouldContain: "..."},
+		// session
+		{
+			args:           []string{"repl"},
+			stdin:          "1+2\nx := 3\nx\nx := 4\nx\n",
+			stdoutShouldBe: "(3 int)\n(3 int)\n(4 int)\n",
+		},
+		{
+			args:           []string{"repl"},
+			stdin:          "import \"strings\"\nstrings.Repeat(\"ab\", 2)\ns := strings.ToUpper(\"a\")\ns\n",
+			stdoutShouldBe: "(\"abab\" string)\n(\"A\" string)\n",
+		},
+		{
+			args: []string{"repl"},
+			stdin: `func add(a, b int) int {
+	return a + b
+}
+type T struct{ A int }
+t := T{A: add(1, 2)}
+for i := 0; i < 2; i++ {
+	t.A += i
+}
+t.A
+:type t
+`,
+			stdoutShouldBe: "(4 int)\nmain.T\n",
+		},
+		{
+			args:                []string{"repl"},
+			stdin:               "x := 1\nx = \"s\"\nfunc x() {}\npanic(\"boom\")\nx\n",
+			stdoutShouldBe:      "(1 int)\n",
+			stderrShouldContain: "error: x redeclared in this session\nerror: boom\n",
+		},
+		{
+			args:           []string{"repl"},
+			stdin:          "x := 1\n:reset\nx := \"s\"\nx\n:load ../../tests/integ/valid1/valid.gno\n",
+			stdoutShouldBe: "(\"s\" string)\n",
+		},
+		{
+			args:                []string{"repl"},
+			stdin:               ":load\n:unknown\n",
+			stderrShouldContain: "error: usage: :load <file.gno>\nerror: unknown command :unknown, see :help\n",
+		},
 	}
 	testMainCaseRun(t, tc)
 }
+
+func TestReplIncomplete(t *testing.T) {
+	for input, want := range map[string]bool{
+		"1 + 2\n":               false,
+		"func f() {\n":          true,
+		"func f() {\n}\n":       false,
+		"f(1,\n":                true,
+		"s := `a\n":             true,
+		"s := `a\n`\n":          false,
+		"/* comment\n":          true,
+		":load {\n":             false,
+		"x := []int{1, 2}[0]\n": false,
+	} {
+		assert.Equal(t, want, incomplete(input), input)
+	}
+}
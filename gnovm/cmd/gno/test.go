@@ -7,14 +7,18 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"text/template"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/gnomod"
 	"github.com/gnolang/gno/gnovm/tests"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 	"github.com/gnolang/gno/tm2/pkg/errors"
@@ -27,9 +31,15 @@ type testCfg struct {
 	verbose           bool
 	rootDir           string
 	run               string
+	bench             string
+	benchmem          bool
+	fuzz              string
+	fuzzTime          string
 	timeout           time.Duration
 	precompile        bool // TODO: precompile should be the default, but it needs to automatically precompile dependencies in memory.
 	updateGoldenTests bool
+	cover             bool
+	coverProfile      string
 }
 
 func newTestCmd(io *commands.IO) *commands.Command {
@@ -84,12 +94,54 @@ func (c *testCfg) RegisterFlags(fs *flag.FlagSet) {
 		"test name filtering pattern",
 	)
 
+	fs.StringVar(
+		&c.bench,
+		"bench",
+		"",
+		"run only benchmarks matching the pattern, after the tests (\".\" for all)",
+	)
+
+	fs.BoolVar(
+		&c.benchmem,
+		"benchmem",
+		false,
+		"print memory allocations for benchmarks",
+	)
+
+	fs.StringVar(
+		&c.fuzz,
+		"fuzz",
+		"",
+		"run the fuzz test matching the pattern, after the tests, until it fails or for -fuzztime",
+	)
+
+	fs.StringVar(
+		&c.fuzzTime,
+		"fuzztime",
+		"",
+		"time to spend fuzzing, as a duration or as Nx to run N inputs (default: unlimited)",
+	)
+
 	fs.DurationVar(
 		&c.timeout,
 		"timeout",
 		0,
 		"max execution time",
 	)
+
+	fs.BoolVar(
+		&c.cover,
+		"cover",
+		false,
+		"enable coverage analysis",
+	)
+
+	fs.StringVar(
+		&c.coverProfile,
+		"coverprofile",
+		"",
+		"write a coverage profile to the file after all tests have passed (implies -cover)",
+	)
 }
 
 func execTest(cfg *testCfg, args []string, io *commands.IO) error {
@@ -98,6 +150,9 @@ func execTest(cfg *testCfg, args []string, io *commands.IO) error {
 	}
 
 	verbose := cfg.verbose
+	if cfg.coverProfile != "" {
+		cfg.cover = true
+	}
 
 	tempdirRoot, err := os.MkdirTemp("", "gno-precompile")
 	if err != nil {
@@ -121,6 +176,14 @@ func execTest(cfg *testCfg, args []string, io *commands.IO) error {
 	if err != nil {
 		return fmt.Errorf("list packages from args: %w", err)
 	}
+	if cfg.fuzz != "" {
+		if len(pkgPaths) > 1 {
+			return errors.New("cannot use -fuzz flag with multiple packages")
+		}
+		if _, _, err := parseFuzzTime(cfg.fuzzTime); err != nil {
+			return err
+		}
+	}
 
 	if cfg.timeout > 0 {
 		go func() {
@@ -131,6 +194,7 @@ func execTest(cfg *testCfg, args []string, io *commands.IO) error {
 
 	buildErrCount := 0
 	testErrCount := 0
+	var profile bytes.Buffer
 	for _, pkgPath := range pkgPaths {
 		if cfg.precompile {
 			if verbose {
@@ -185,10 +249,24 @@ func execTest(cfg *testCfg, args []string, io *commands.IO) error {
 		sort.Strings(unittestFiles)
 		sort.Strings(filetestFiles)
 
+		var cov *gno.Coverage
+		if cfg.cover {
+			cov, err = newPkgCoverage(pkgPath)
+			if err != nil {
+				return fmt.Errorf("%s: cover: %w", pkgPath, err)
+			}
+		}
+
 		startedAt := time.Now()
-		err = gnoTestPkg(pkgPath, unittestFiles, filetestFiles, cfg, io)
+		err = gnoTestPkg(pkgPath, unittestFiles, filetestFiles, cov, cfg, io)
 		duration := time.Since(startedAt)
 		dstr := fmtDuration(duration)
+		if cov != nil {
+			dstr += fmt.Sprintf("\tcoverage: %.1f%% of statements", cov.Percent())
+			if err := cov.WriteProfile(&profile); err != nil {
+				return err
+			}
+		}
 
 		if err != nil {
 			io.ErrPrintfln("%s: test pkg: %v", pkgPath, err)
@@ -205,13 +283,73 @@ func execTest(cfg *testCfg, args []string, io *commands.IO) error {
 		return fmt.Errorf("FAIL: %d build errors, %d test errors", buildErrCount, testErrCount)
 	}
 
+	if cfg.coverProfile != "" {
+		data := append([]byte("mode: count\n"), profile.Bytes()...)
+		if err := os.WriteFile(cfg.coverProfile, data, 0o644); err != nil {
+			return fmt.Errorf("write cover profile: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// newPkgCoverage returns a coverage of the non-test files of the package in
+// the directory pkgPath, as run from unit tests (by directory) and from
+// filetests (by import path, if known). Files are reported by their
+// absolute path, which `go tool cover` uses as is.
+func newPkgCoverage(pkgPath string) (*gno.Coverage, error) {
+	cov := gno.NewCoverage()
+	memPkg := gno.ReadMemPackage(pkgPath, pkgPath)
+	importPath := guessImportPath(pkgPath)
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		path, err := filepath.Abs(filepath.Join(pkgPath, mfile.Name))
+		if err != nil {
+			return nil, err
+		}
+		if err := cov.AddFile(memPkg.Path, mfile.Name, path, mfile.Body); err != nil {
+			return nil, err
+		}
+		if importPath != "" && importPath != memPkg.Path {
+			if err := cov.AddFile(importPath, mfile.Name, path, mfile.Body); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cov, nil
+}
+
+// guessImportPath returns the import path of the package in the directory
+// pkgPath, from its gno.mod file, or else from its gno.land/ path element.
+// It returns "" if unknown.
+func guessImportPath(pkgPath string) string {
+	modPath := filepath.Join(pkgPath, "gno.mod")
+	if data, err := os.ReadFile(modPath); err == nil {
+		gnoMod, err := gnomod.Parse(modPath, data)
+		if err == nil && gnoMod.Module != nil {
+			return gnoMod.Module.Mod.Path
+		}
+	}
+	absPath, err := filepath.Abs(pkgPath)
+	if err != nil {
+		return ""
+	}
+	absPath = filepath.ToSlash(absPath)
+	if i := strings.Index(absPath, "/gno.land/"); i >= 0 {
+		return absPath[i+1:]
+	}
+	return ""
+}
+
 func gnoTestPkg(
 	pkgPath string,
 	unittestFiles,
 	filetestFiles []string,
+	cov *gno.Coverage,
 	cfg *testCfg,
 	io *commands.IO,
 ) error {
@@ -251,8 +389,9 @@ func gnoTestPkg(
 		// run test files in pkg
 		{
 			m := tests.TestMachine(testStore, stdout, "main")
+			m.Coverage = cov
 			m.RunMemPackage(memPkg, true)
-			err := runTestFiles(m, tfiles, memPkg.Name, verbose, runFlag, io)
+			err := runTestFiles(m, pkgPath, tfiles, memPkg.Name, cfg, io)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -263,8 +402,9 @@ func gnoTestPkg(
 			testPkgName := getPkgNameFromFileset(ifiles)
 			if testPkgName != "" {
 				m := tests.TestMachine(testStore, stdout, testPkgName)
+				m.Coverage = cov
 				m.RunMemPackage(memPkg, true)
-				err := runTestFiles(m, ifiles, testPkgName, verbose, runFlag, io)
+				err := runTestFiles(m, pkgPath, ifiles, testPkgName, cfg, io)
 				if err != nil {
 					errs = multierr.Append(errs, err)
 				}
@@ -292,7 +432,9 @@ func gnoTestPkg(
 			}
 
 			testFilePath := filepath.Join(pkgPath, testFileName)
-			err := tests.RunFileTest(rootDir, testFilePath, tests.WithSyncWanted(cfg.updateGoldenTests))
+			err := tests.RunFileTest(rootDir, testFilePath,
+				tests.WithSyncWanted(cfg.updateGoldenTests),
+				tests.WithCoverage(cov))
 			duration := time.Since(startedAt)
 			dstr := fmtDuration(duration)
 
@@ -320,18 +462,21 @@ func gnoTestPkg(
 
 func runTestFiles(
 	m *gno.Machine,
+	pkgPath string,
 	files *gno.FileSet,
 	pkgName string,
-	verbose bool,
-	runFlag string,
+	cfg *testCfg,
 	io *commands.IO,
 ) error {
 	var errs error
+	verbose := cfg.verbose
 
 	testFuncs := &testFuncs{
 		PackageName: pkgName,
 		Verbose:     verbose,
-		RunFlag:     runFlag,
+		RunFlag:     cfg.run,
+		BenchFlag:   cfg.bench,
+		BenchMem:    cfg.benchmem,
 	}
 	loadTestFuncs(pkgName, testFuncs, files)
 
@@ -396,9 +541,149 @@ func runTestFiles(
 		}
 	}
 
+	// fuzz tests run their corpus as tests.
+	filter := splitRegexp(cfg.run)
+	fuzzTargets := make(map[string]*fuzzTarget)
+	for _, fuzz := range testFuncs.FuzzTargets {
+		if !shouldRun(filter, fuzz.Name) {
+			continue
+		}
+		ft, ok, err := runFuzzTest(m, pkgPath, fuzz.Name, cfg, io)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+		}
+		if !ok {
+			// the machine panicked.
+			return errs
+		}
+		fuzzTargets[fuzz.Name] = ft
+	}
+
+	// like go test, benchmarks and fuzzing are only run if all tests
+	// passed.
+	if errs != nil {
+		return errs
+	}
+	if cfg.bench != "" {
+		if err := runBenchmarks(m, testFuncs, cfg, io); err != nil {
+			return err
+		}
+	}
+	if cfg.fuzz != "" {
+		return fuzzTestFiles(m, pkgPath, testFuncs, fuzzTargets, cfg, io)
+	}
+	return nil
+}
+
+// runBenchmarks runs the benchmarks matching the -bench pattern.
+func runBenchmarks(
+	m *gno.Machine,
+	benchmarks *testFuncs,
+	cfg *testCfg,
+	io *commands.IO,
+) error {
+	var errs error
+	verbose := cfg.verbose
+
+	if m.Alloc == nil {
+		// track allocations, without limit.
+		m.Alloc = gno.NewAllocator(math.MaxInt64)
+	}
+	for _, bench := range benchmarks.Benchmarks {
+		benchFuncStr := fmt.Sprintf("%q", bench.Name)
+
+		eval := m.Eval(gno.Call("runbench", benchFuncStr))
+
+		ret := eval[0].GetString()
+		if ret == "" {
+			err := errors.New("failed to execute benchmark: %q", bench.Name)
+			errs = multierr.Append(errs, err)
+			io.ErrPrintfln("--- FAIL: %s", bench.Name)
+			continue
+		}
+
+		var rep benchReport
+		err := json.Unmarshal([]byte(ret), &rep)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			io.ErrPrintfln("--- FAIL: %s", bench.Name)
+			continue
+		}
+
+		switch {
+		case rep.Filtered:
+			// noop
+		case rep.Failed:
+			err := errors.New("failed: %q", bench.Name)
+			errs = multierr.Append(errs, err)
+			io.ErrPrintfln("--- FAIL: %s", bench.Name)
+		case rep.Skipped:
+			if verbose {
+				io.ErrPrintfln("--- SKIP: %s", bench.Name)
+			}
+		}
+
+		for _, res := range rep.Results {
+			line := fmt.Sprintf("%s\t%8d\t%10d cycles/op",
+				res.Name, res.N, perOp(res.Cycles, res.N))
+			if res.ShowAllocs {
+				line += fmt.Sprintf("\t%10d B/op", perOp(res.AllocBytes, res.N))
+			}
+			io.ErrPrintln(line)
+		}
+
+		if rep.Output != "" && (verbose || rep.Failed) {
+			io.ErrPrintfln("output: %s", rep.Output)
+		}
+	}
+
 	return errs
 }
 
+// perOp returns total divided by the n iterations, or 0 if none ran.
+func perOp(total int64, n int) int64 {
+	if n <= 0 {
+		return 0
+	}
+	return total / int64(n)
+}
+
+// fuzzTestFiles fuzzes the fuzz test matching the -fuzz pattern, among
+// fuzzes, of which fuzzTargets were run.
+func fuzzTestFiles(
+	m *gno.Machine,
+	pkgPath string,
+	fuzzes *testFuncs,
+	fuzzTargets map[string]*fuzzTarget,
+	cfg *testCfg,
+	io *commands.IO,
+) error {
+	names := matchFuzzTargets(cfg.fuzz, fuzzes.FuzzTargets)
+	switch len(names) {
+	case 0:
+		io.ErrPrintfln("testing: warning: no fuzz tests to fuzz")
+		return nil
+	case 1:
+	default:
+		return errors.New("will not fuzz, -fuzz matches more than one fuzz test: %v", names)
+	}
+
+	ft, ok := fuzzTargets[names[0]]
+	if !ok {
+		// filtered out by -run.
+		var err error
+		ft, ok, err = runFuzzTest(m, pkgPath, names[0], cfg, io)
+		if err != nil || !ok {
+			return err
+		}
+	}
+	if ft == nil {
+		// skipped.
+		return nil
+	}
+	return ft.fuzz(pkgPath, cfg, io)
+}
+
 // mirror of stdlibs/testing.Report
 type report struct {
 	Name     string
@@ -409,6 +694,26 @@ type report struct {
 	Output   string
 }
 
+// mirror of stdlibs/testing.BenchmarkReport
+type benchReport struct {
+	Name     string
+	Verbose  bool
+	Failed   bool
+	Skipped  bool
+	Filtered bool
+	Output   string
+	Results  []benchResult
+}
+
+// mirror of stdlibs/testing.BenchmarkResult
+type benchResult struct {
+	Name       string
+	N          int
+	Cycles     int64
+	AllocBytes int64
+	ShowAllocs bool
+}
+
 var testmainTmpl = template.Must(template.New("testmain").Parse(`
 package {{ .PackageName }}
 
@@ -416,14 +721,14 @@ import (
 	"testing"
 )
 
-var tests = []testing.InternalTest{
+var testmainTests = []testing.InternalTest{
 {{range .Tests}}
     {"{{.Name}}", {{.Name}}},
 {{end}}
 }
 
 func runtest(name string) (report string) {
-	for _, test := range tests {
+	for _, test := range testmainTests {
 		if test.Name == name {
 			return testing.RunTest({{printf "%q" .RunFlag}}, {{.Verbose}}, test)
 		}
@@ -431,13 +736,57 @@ func runtest(name string) (report string) {
 	panic("no such test: " + name)
 	return ""
 }
+
+var testmainBenchmarks = []testing.InternalBenchmark{
+{{range .Benchmarks}}
+    {"{{.Name}}", {{.Name}}},
+{{end}}
+}
+
+func runbench(name string) (report string) {
+	for _, bench := range testmainBenchmarks {
+		if bench.Name == name {
+			return testing.RunBenchmark({{printf "%q" .BenchFlag}}, {{.BenchMem}}, {{.Verbose}}, bench)
+		}
+	}
+	panic("no such benchmark: " + name)
+	return ""
+}
+
+var testmainFuzzTargets = []testing.InternalFuzzTarget{
+{{range .FuzzTargets}}
+    {"{{.Name}}", {{.Name}}},
+{{end}}
+}
+
+func runfuzz(name string) (report string, fn interface{}, corpus [][]interface{}) {
+	for _, target := range testmainFuzzTargets {
+		if target.Name == name {
+			return testing.RunFuzzTarget({{.Verbose}}, target)
+		}
+	}
+	panic("no such fuzz test: " + name)
+	return "", nil, nil
+}
+
+func newfuzzt(name string) *testing.T {
+	return testing.NewT(name)
+}
+
+func runfuzzinput(t *testing.T, input func()) (report string) {
+	return testing.RunFuzzInput(t, input)
+}
 `))
 
 type testFuncs struct {
 	Tests       []testFunc
+	Benchmarks  []testFunc
+	FuzzTargets []testFunc
 	PackageName string
 	Verbose     bool
 	RunFlag     string
+	BenchFlag   string
+	BenchMem    bool
 }
 
 type testFunc struct {
@@ -460,6 +809,20 @@ func formatTestmain(t *testFuncs) (string, error) {
 	return buf.String(), nil
 }
 
+// isTestFunc reports whether name is a test function name with prefix, as
+// go test does: the prefix must not be followed by a lowercase letter, so
+// that e.g. "Benchmarker" is not a benchmark.
+func isTestFunc(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
 func loadTestFuncs(pkgName string, t *testFuncs, tfiles *gno.FileSet) *testFuncs {
 	for _, tf := range tfiles.Files {
 		for _, d := range tf.Decls {
@@ -471,6 +834,18 @@ func loadTestFuncs(pkgName string, t *testFuncs, tfiles *gno.FileSet) *testFuncs
 						Name:    fname,
 					}
 					t.Tests = append(t.Tests, tf)
+				} else if isTestFunc(fname, "Benchmark") {
+					tf := testFunc{
+						Package: pkgName,
+						Name:    fname,
+					}
+					t.Benchmarks = append(t.Benchmarks, tf)
+				} else if strings.HasPrefix(fname, "Fuzz") {
+					tf := testFunc{
+						Package: pkgName,
+						Name:    fname,
+					}
+					t.FuzzTargets = append(t.FuzzTargets, tf)
 				}
 			}
 		}
@@ -93,6 +93,34 @@ func TestTest(t *testing.T) {
 			stderrShouldBe:  "=== PREC  ./../../tests/integ/failing2\n=== BUILD ./../../tests/integ/failing2\n=== RUN   file/failing_filetest.gno\n",
 			recoverShouldBe: "fail on ../../tests/integ/failing2/failing_filetest.gno: got unexpected error: beep boop",
 		},
+		{
+			args:                []string{"test", "--cover", "../../tests/integ/cover"},
+			stderrShouldContain: "coverage: 66.7% of statements",
+		},
+		{
+			args:                []string{"test", "--bench", "Sizes", "../../tests/integ/bench"},
+			stderrShouldContain: "cycles/op\nBenchmarkSizes/100\t",
+		},
+		{
+			args:                []string{"test", "--bench", "SumAlloc", "../../tests/integ/bench"},
+			stderrShouldContain: " B/op\n",
+		},
+		{
+			args:                []string{"test", "--bench", ".", "../../tests/integ/bench"},
+			stderrShouldContain: "BenchmarkSum\t",
+		},
+		{
+			args:                []string{"test", "--verbose", "--run", "FuzzReverse/582528ddfad69eb5", "../../tests/integ/fuzz"},
+			stderrShouldContain: "--- PASS: FuzzReverse",
+		},
+		{
+			args:                []string{"test", "--fuzz", "Reverse", "--fuzztime", "50x", "../../tests/integ/fuzz"},
+			stderrShouldContain: "3/3 completed, now fuzzing",
+		},
+		{
+			args:        []string{"test", "--fuzz", "Reverse", "--fuzztime", "1y", "../../tests/integ/fuzz"},
+			errShouldBe: `invalid -fuzztime "1y"`,
+		},
 		{
 			args:                []string{"test", "../../../examples/gno.land/p/demo/ufmt"},
 			stdoutShouldContain: "RUN   TestSprintf",
@@ -0,0 +1,284 @@
+// Package doc renders the documentation of gno packages, as go doc does for
+// go packages. It is used by gno doc and by gnoweb.
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	godoc "go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// A Package is the documentation of a package.
+type Package struct {
+	fset *token.FileSet
+	doc  *godoc.Package
+}
+
+// New parses the non-test gno files of mpkg, and returns their
+// documentation. Only exported declarations are documented.
+func New(mpkg *std.MemPackage) (*Package, error) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, mfile := range mpkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		// go/doc requires the names of go files.
+		f, err := parser.ParseFile(fset, mfile.Name+".go", mfile.Body, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no gno files in package %s", mpkg.Path)
+	}
+	dpkg, err := godoc.NewFromFiles(fset, files, mpkg.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &Package{fset: fset, doc: dpkg}, nil
+}
+
+// FetchMemPackage returns the package pkgPath with the files returned by
+// qfile, which queries files as the vm/qfile query does: with the path of
+// a package, it returns the names of its files, one per line, and with the
+// path of a file, its content.
+func FetchMemPackage(pkgPath string, qfile func(path string) (string, error)) (*std.MemPackage, error) {
+	list, err := qfile(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	mpkg := &std.MemPackage{Path: pkgPath}
+	for _, name := range strings.Split(list, "\n") {
+		if name == "" {
+			continue
+		}
+		body, err := qfile(pkgPath + "/" + name)
+		if err != nil {
+			return nil, err
+		}
+		mpkg.Files = append(mpkg.Files, &std.MemFile{Name: name, Body: body})
+	}
+	return mpkg, nil
+}
+
+// WriteText writes the documentation of the package to w, or, if symbol is
+// not empty, the documentation of the symbol, which is the name of a
+// constant, variable, function or type, or <type>.<method>.
+func (pkg *Package) WriteText(w io.Writer, symbol string) error {
+	var buf bytes.Buffer
+	if symbol == "" {
+		pkg.writePackage(&buf)
+	} else if err := pkg.writeSymbol(&buf, symbol); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (pkg *Package) writePackage(buf *bytes.Buffer) {
+	d := pkg.doc
+	fmt.Fprintf(buf, "package %s // import %q\n\n", d.Name, d.ImportPath)
+	if d.Doc != "" {
+		buf.Write(d.Text(d.Doc))
+		buf.WriteString("\n")
+	}
+
+	section := func(title string) {
+		if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n\n")) {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(title + "\n\n")
+	}
+	if len(d.Consts) > 0 {
+		section("CONSTANTS")
+		for _, v := range d.Consts {
+			pkg.writeValue(buf, v)
+		}
+	}
+	if len(d.Vars) > 0 {
+		section("VARIABLES")
+		for _, v := range d.Vars {
+			pkg.writeValue(buf, v)
+		}
+	}
+	if len(d.Funcs) > 0 {
+		section("FUNCTIONS")
+		for _, f := range d.Funcs {
+			buf.WriteString(oneLine(pkg.funcDecl(f.Decl)) + "\n")
+		}
+	}
+	if len(d.Types) > 0 {
+		section("TYPES")
+		for _, t := range d.Types {
+			buf.WriteString(pkg.typeSummary(t) + "\n")
+			for _, f := range t.Funcs {
+				buf.WriteString("    " + oneLine(pkg.funcDecl(f.Decl)) + "\n")
+			}
+			for _, f := range t.Methods {
+				buf.WriteString("    " + oneLine(pkg.funcDecl(f.Decl)) + "\n")
+			}
+		}
+	}
+}
+
+func (pkg *Package) writeSymbol(buf *bytes.Buffer, symbol string) error {
+	d := pkg.doc
+	typName, method, isMethod := strings.Cut(symbol, ".")
+	for _, t := range d.Types {
+		if t.Name != typName {
+			continue
+		}
+		if isMethod {
+			for _, f := range t.Methods {
+				if f.Name == method {
+					pkg.writeFunc(buf, f)
+					return nil
+				}
+			}
+			return fmt.Errorf("no method %s in type %s", method, typName)
+		}
+		pkg.writeType(buf, t)
+		return nil
+	}
+	if isMethod {
+		return fmt.Errorf("no type %s in package %s", typName, d.ImportPath)
+	}
+
+	for _, f := range d.Funcs {
+		if f.Name == symbol {
+			pkg.writeFunc(buf, f)
+			return nil
+		}
+	}
+	for _, t := range d.Types {
+		for _, f := range t.Funcs {
+			if f.Name == symbol {
+				pkg.writeFunc(buf, f)
+				return nil
+			}
+		}
+	}
+	values := append(append([]*godoc.Value{}, d.Consts...), d.Vars...)
+	for _, t := range d.Types {
+		values = append(append(values, t.Consts...), t.Vars...)
+	}
+	for _, v := range values {
+		for _, name := range v.Names {
+			if name == symbol {
+				pkg.writeValue(buf, v)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no symbol %s in package %s", symbol, d.ImportPath)
+}
+
+// writeValue writes the declaration of a group of constants or variables,
+// followed by its doc.
+func (pkg *Package) writeValue(buf *bytes.Buffer, v *godoc.Value) {
+	buf.WriteString(pkg.node(v.Decl) + "\n")
+	pkg.writeDoc(buf, v.Doc)
+	buf.WriteString("\n")
+}
+
+func (pkg *Package) writeFunc(buf *bytes.Buffer, f *godoc.Func) {
+	buf.WriteString(pkg.funcDecl(f.Decl) + "\n")
+	pkg.writeDoc(buf, f.Doc)
+}
+
+// writeType writes the declaration of t, its doc, and the summaries of its
+// constants, variables, constructors and methods.
+func (pkg *Package) writeType(buf *bytes.Buffer, t *godoc.Type) {
+	buf.WriteString(pkg.node(t.Decl) + "\n")
+	pkg.writeDoc(buf, t.Doc)
+	for _, v := range append(append([]*godoc.Value{}, t.Consts...), t.Vars...) {
+		buf.WriteString("\n")
+		pkg.writeValue(buf, v)
+	}
+	funcs := append(append([]*godoc.Func{}, t.Funcs...), t.Methods...)
+	if len(funcs) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, f := range funcs {
+		buf.WriteString(oneLine(pkg.funcDecl(f.Decl)) + "\n")
+	}
+}
+
+// writeDoc writes the doc text, indented, if not empty.
+func (pkg *Package) writeDoc(buf *bytes.Buffer, text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(pkg.doc.Text(text)), "\n"), "\n") {
+		if line == "" {
+			buf.WriteString("\n")
+		} else {
+			buf.WriteString("    " + line + "\n")
+		}
+	}
+}
+
+// funcDecl returns the signature of the function or method decl.
+func (pkg *Package) funcDecl(decl *ast.FuncDecl) string {
+	sig := *decl
+	sig.Doc, sig.Body = nil, nil
+	return pkg.node(&sig)
+}
+
+// typeSummary returns the declaration of t, with the fields of structs and
+// the methods of interfaces elided.
+func (pkg *Package) typeSummary(t *godoc.Type) string {
+	var spec *ast.TypeSpec
+	for _, s := range t.Decl.Specs {
+		if ts := s.(*ast.TypeSpec); ts.Name.Name == t.Name {
+			spec = ts
+		}
+	}
+	if spec == nil {
+		return "type " + t.Name
+	}
+	typ := ""
+	switch st := spec.Type.(type) {
+	case *ast.StructType:
+		typ = "struct{ ... }"
+	case *ast.InterfaceType:
+		typ = "interface{ ... }"
+		if st.Methods == nil || len(st.Methods.List) == 0 {
+			typ = "interface{}"
+		}
+	default:
+		typ = pkg.node(spec.Type)
+	}
+	if spec.Assign.IsValid() {
+		return "type " + t.Name + " = " + typ
+	}
+	return "type " + t.Name + " " + typ
+}
+
+func (pkg *Package) node(n interface{}) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, pkg.fset, n); err != nil {
+		panic(fmt.Sprintf("error formatting node: %v", err))
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// oneLine joins the lines of a declaration, e.g. of a signature whose
+// parameters are on several lines.
+func oneLine(decl string) string {
+	s := strings.Join(strings.Fields(decl), " ")
+	s = strings.ReplaceAll(s, "( ", "(")
+	return strings.ReplaceAll(s, ", )", ")")
+}
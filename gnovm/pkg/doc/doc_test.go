@@ -0,0 +1,132 @@
+package doc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
+)
+
+var testMemPackage = &std.MemPackage{
+	Name: "shapes",
+	Path: "gno.land/p/demo/shapes",
+	Files: []*std.MemFile{
+		{Name: "shapes.gno", Body: `// Package shapes measures shapes.
+package shapes
+
+// Unit is the unit of the lengths.
+const Unit = "cm"
+
+// A Rect is a rectangle.
+type Rect struct {
+	W, H int
+	name string
+}
+
+// NewSquare returns a square with sides
+// of length n.
+func NewSquare(n int) Rect {
+	return Rect{W: n, H: n}
+}
+
+// Area returns the area of r.
+func (r Rect) Area() int {
+	return r.W * r.H
+}
+
+func Sum(
+	a int,
+	b int,
+) int {
+	return a + b
+}
+
+func helper() {}
+`},
+		{Name: "shapes_test.gno", Body: `package shapes
+
+func TestArea(t *testing.T) {}
+`},
+		{Name: "README.md", Body: "# shapes"},
+	},
+}
+
+func TestWriteText(t *testing.T) {
+	dpkg, err := New(testMemPackage)
+	require.NoError(t, err)
+
+	cases := []struct {
+		symbol string
+		want   string
+		errStr string
+	}{
+		{"", `package shapes // import "gno.land/p/demo/shapes"
+
+Package shapes measures shapes.
+
+CONSTANTS
+
+const Unit = "cm"
+    Unit is the unit of the lengths.
+
+FUNCTIONS
+
+func Sum(a int, b int) int
+
+TYPES
+
+type Rect struct{ ... }
+    func NewSquare(n int) Rect
+    func (r Rect) Area() int
+`, ""},
+		{"Rect", `type Rect struct {
+	W, H int
+	// contains filtered or unexported fields
+}
+    A Rect is a rectangle.
+
+func NewSquare(n int) Rect
+func (r Rect) Area() int
+`, ""},
+		{"Rect.Area", "func (r Rect) Area() int\n    Area returns the area of r.\n", ""},
+		{"NewSquare", "func NewSquare(n int) Rect\n    NewSquare returns a square with sides of length n.\n", ""},
+		{"Unit", "const Unit = \"cm\"\n    Unit is the unit of the lengths.\n\n", ""},
+		{"helper", "", "no symbol helper in package gno.land/p/demo/shapes"},
+		{"Rect.Perimeter", "", "no method Perimeter in type Rect"},
+		{"Circle.Area", "", "no type Circle in package gno.land/p/demo/shapes"},
+	}
+	for _, c := range cases {
+		t.Run(c.symbol, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := dpkg.WriteText(&buf, c.symbol)
+			if c.errStr != "" {
+				assert.EqualError(t, err, c.errStr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, buf.String())
+		})
+	}
+}
+
+func TestFetchMemPackage(t *testing.T) {
+	qfile := func(path string) (string, error) {
+		if path == testMemPackage.Path {
+			return "shapes.gno\nREADME.md", nil
+		}
+		for _, mfile := range testMemPackage.Files {
+			if path == testMemPackage.Path+"/"+mfile.Name {
+				return mfile.Body, nil
+			}
+		}
+		t.Fatalf("unexpected query %s", path)
+		return "", nil
+	}
+	mpkg, err := FetchMemPackage(testMemPackage.Path, qfile)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(mpkg.Files))
+	assert.Equal(t, testMemPackage.Files[0], mpkg.Files[0])
+	assert.Equal(t, "README.md", mpkg.Files[1].Name)
+}
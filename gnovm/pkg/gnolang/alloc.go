@@ -26,6 +26,7 @@ const (
 	_allocSliceValue       = 40
 	_allocFuncValue        = 136
 	_allocMapValue         = 144
+	_allocChanValue        = 96
 	_allocBoundMethodValue = 176
 	_allocBlock            = 464
 	_allocNativeValue      = 48
@@ -53,6 +54,8 @@ const (
 	allocFunc        = _allocBase + _allocPointer + _allocFuncValue
 	allocMap         = _allocBase + _allocPointer + _allocMapValue
 	allocMapItem     = _allocTypedValue * 3 // XXX
+	allocChan        = _allocBase + _allocPointer + _allocChanValue
+	allocChanItem    = _allocTypedValue
 	allocBoundMethod = _allocBase + _allocPointer + _allocBoundMethodValue
 	allocBlock       = _allocBase + _allocPointer + _allocBlock
 	allocBlockItem   = _allocTypedValue
@@ -147,6 +150,10 @@ func (alloc *Allocator) AllocateMapItem() {
 	alloc.Allocate(allocMapItem)
 }
 
+func (alloc *Allocator) AllocateChan(items int64) {
+	alloc.Allocate(allocChan + allocChanItem*items)
+}
+
 func (alloc *Allocator) AllocateBoundMethod() {
 	alloc.Allocate(allocBoundMethod)
 }
@@ -274,6 +281,14 @@ func (alloc *Allocator) NewMap(size int) *MapValue {
 	return mv
 }
 
+func (alloc *Allocator) NewChan(size int) *ChanValue {
+	alloc.AllocateChan(int64(size))
+	return &ChanValue{
+		Cap:    size,
+		Buffer: make([]TypedValue, 0, size),
+	}
+}
+
 func (alloc *Allocator) NewBlock(source BlockNode, parent *Block) *Block {
 	alloc.AllocateBlock(int64(source.GetNumNames()))
 	return NewBlock(source, parent)
@@ -0,0 +1,367 @@
+package gnolang
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Browsing lets values of the store be inspected without a machine, e.g.
+// to audit realm state: a selector such as `board.threads[3].title` is
+// resolved from a package block or from an object, following fields,
+// indices, map keys and pointers, and the value found is returned along
+// with the metadata of the object that holds it.
+
+// BrowseResult is the JSON representation of a browsed value.
+type BrowseResult struct {
+	// The object holding the value, or the value itself if it is (or
+	// points to) an object.
+	ObjectID string `json:"ObjectID"`
+	OwnerID  string `json:"OwnerID,omitempty"`
+	RefCount int    `json:"RefCount"`
+	Hash     string `json:"Hash,omitempty"` // as of the last save.
+	Type     string `json:"Type"`
+	// A block is an object mapping names to JSONValue.
+	Value interface{} `json:"Value"`
+}
+
+// BrowsePackage resolves selector from the block of the package pkgPath.
+// The selector starts with a package level name; if empty, the package
+// block itself is returned.
+func BrowsePackage(store Store, pkgPath string, selector string) (*BrowseResult, error) {
+	pv := store.GetPackage(pkgPath, false)
+	if pv == nil {
+		return nil, fmt.Errorf("package not found: %s", pkgPath)
+	}
+	cur := browseCursor{store: store, block: pv.GetBlock(store)}
+	cur.obj = cur.block
+	if selector != "" {
+		x, err := ParseExpr(selector)
+		if err != nil {
+			return nil, err
+		}
+		if err := cur.walk(x, false); err != nil {
+			return nil, err
+		}
+	}
+	return cur.result()
+}
+
+// BrowseObject resolves selector from the object oid. The selector starts
+// with a field or an index, as in `.title` or `[3]`; if empty, the object
+// itself is returned.
+func BrowseObject(store Store, oid ObjectID, selector string) (*BrowseResult, error) {
+	oo := store.GetObjectSafe(oid)
+	if oo == nil {
+		return nil, fmt.Errorf("object not found: %s", oid.String())
+	}
+	cur := browseCursor{store: store, obj: oo}
+	if b, ok := oo.(*Block); ok {
+		cur.block = b
+	} else {
+		// objects do not know their type, but their owner does.
+		t := typeOfObject(store, oo)
+		if t == nil {
+			return nil, fmt.Errorf("cannot determine type of object %s",
+				oid.String())
+		}
+		cur.tv = &TypedValue{T: t, V: oo}
+	}
+	if selector != "" {
+		x, err := ParseExpr("_" + selector)
+		if err != nil {
+			return nil, err
+		}
+		if err := cur.walk(x, true); err != nil {
+			return nil, err
+		}
+	}
+	return cur.result()
+}
+
+// A browseCursor is at a block, or at the value tv held by obj.
+type browseCursor struct {
+	store Store
+	block *Block
+	tv    *TypedValue
+	obj   Object
+}
+
+// walk moves the cursor along x. If rooted, the name "_" is the starting
+// point of the cursor.
+func (cur *browseCursor) walk(x Expr, rooted bool) error {
+	switch x := x.(type) {
+	case *NameExpr:
+		if rooted && x.Name == "_" {
+			return nil
+		}
+		if rooted || cur.block == nil {
+			return fmt.Errorf("unexpected name %s", x.Name)
+		}
+		return cur.selectName(x.Name)
+	case *SelectorExpr:
+		if err := cur.walk(x.X, rooted); err != nil {
+			return err
+		}
+		return cur.selectName(x.Sel)
+	case *IndexExpr:
+		if err := cur.walk(x.X, rooted); err != nil {
+			return err
+		}
+		lit, ok := x.Index.(*BasicLitExpr)
+		if !ok {
+			return fmt.Errorf("invalid index %s: must be a literal",
+				x.Index.String())
+		}
+		return cur.selectIndex(lit)
+	default:
+		return fmt.Errorf("invalid selector %s", x.String())
+	}
+}
+
+func (cur *browseCursor) selectName(n Name) error {
+	if cur.block != nil {
+		names := cur.block.GetSource(cur.store).GetBlockNames()
+		for i, name := range names {
+			if name == n {
+				cur.obj = cur.block
+				cur.block = nil
+				cur.tv = fillValueTV(cur.store, &cur.obj.(*Block).Values[i])
+				return nil
+			}
+		}
+		return fmt.Errorf("name %s not declared", n)
+	}
+	tv := cur.deref()
+	st, ok := baseOf(tv.T).(*StructType)
+	if !ok {
+		return fmt.Errorf("cannot select %s of %s", n, tv.T.String())
+	}
+	for i, f := range st.Fields {
+		if f.Name == n {
+			sv := tv.V.(*StructValue)
+			cur.obj = sv
+			cur.tv = fillValueTV(cur.store, &sv.Fields[i])
+			return nil
+		}
+	}
+	return fmt.Errorf("%s has no field %s", tv.T.String(), n)
+}
+
+func (cur *browseCursor) selectIndex(lit *BasicLitExpr) error {
+	if cur.block != nil {
+		return fmt.Errorf("cannot index block")
+	}
+	tv := cur.deref()
+	switch bt := baseOf(tv.T).(type) {
+	case *ArrayType, *SliceType:
+		ii, err := strconv.Atoi(lit.Value)
+		if lit.Kind != INT || err != nil {
+			return fmt.Errorf("invalid index %s", lit.Value)
+		}
+		var av *ArrayValue
+		var offset, length int
+		if sv, ok := tv.V.(*SliceValue); ok {
+			av = sv.GetBase(cur.store)
+			offset, length = sv.Offset, sv.Length
+		} else if tv.V != nil {
+			av = tv.V.(*ArrayValue)
+			length = av.GetLength()
+		}
+		if ii < 0 || length <= ii {
+			return fmt.Errorf("index %d out of range [0:%d]", ii, length)
+		}
+		cur.obj = av
+		if av.Data != nil {
+			etv := TypedValue{T: bt.Elem()}
+			etv.SetUint8(av.Data[offset+ii])
+			cur.tv = &etv
+		} else {
+			cur.tv = fillValueTV(cur.store, &av.List[offset+ii])
+		}
+		return nil
+	case *MapType:
+		key, err := browseMapKey(lit, bt.Key)
+		if err != nil {
+			return err
+		}
+		if tv.V == nil {
+			return fmt.Errorf("key %s not found", lit.Value)
+		}
+		mv := tv.V.(*MapValue)
+		val, ok := mv.GetValueForKey(cur.store, &key)
+		if !ok {
+			return fmt.Errorf("key %s not found", lit.Value)
+		}
+		cur.obj = mv
+		cur.tv = fillValueTV(cur.store, &val)
+		return nil
+	default:
+		return fmt.Errorf("cannot index %s", tv.T.String())
+	}
+}
+
+// deref returns the value at the cursor, following pointers, and moves the
+// cursor to the value pointed to.
+func (cur *browseCursor) deref() *TypedValue {
+	for cur.tv.T != nil && cur.tv.T.Kind() == PointerKind && cur.tv.V != nil {
+		pv := cur.tv.V.(PointerValue)
+		if oo, ok := pv.Base.(Object); ok {
+			cur.obj = oo
+		}
+		cur.tv = fillValueTV(cur.store, pv.TV)
+	}
+	if cur.tv.T == nil {
+		panic("should not happen")
+	}
+	return cur.tv
+}
+
+func (cur *browseCursor) result() (*BrowseResult, error) {
+	enc := jsonEncoder{store: cur.store, visiting: make(map[interface{}]struct{})}
+	res := &BrowseResult{}
+	if cur.block != nil {
+		names := cur.block.GetSource(cur.store).GetBlockNames()
+		values := make(map[string]JSONValue, len(names))
+		for i, name := range names {
+			if name == "_" || name[0] == '.' {
+				continue
+			}
+			jv, err := enc.encode(fillValueTV(cur.store, &cur.block.Values[i]))
+			if err != nil {
+				return nil, err
+			}
+			values[string(name)] = jv
+		}
+		res.Type = "block"
+		res.Value = values
+	} else {
+		jv, err := enc.encode(cur.tv)
+		if err != nil {
+			return nil, err
+		}
+		if cur.tv.T != nil {
+			res.Type = cur.tv.T.String()
+		}
+		res.Value = jv
+		if oo := objectOfValue(cur.store, cur.tv.V); oo != nil {
+			cur.obj = oo
+		}
+	}
+	oi := cur.obj.GetObjectInfo()
+	res.ObjectID = oi.ID.String()
+	if !oi.OwnerID.IsZero() {
+		res.OwnerID = oi.OwnerID.String()
+	}
+	res.RefCount = oi.RefCount
+	if !oi.Hash.IsZero() {
+		res.Hash, _ = oi.Hash.MarshalAmino()
+	}
+	return res, nil
+}
+
+// browseMapKey converts the literal lit to a map key of type kt.
+func browseMapKey(lit *BasicLitExpr, kt Type) (tv TypedValue, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid key %s: %v", lit.Value, r)
+		}
+	}()
+	switch lit.Kind {
+	case STRING:
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil || baseOf(kt).Kind() != StringKind {
+			return tv, fmt.Errorf("invalid key %s", lit.Value)
+		}
+		tv = TypedValue{T: kt, V: StringValue(s)}
+	case INT:
+		bi, ok := new(big.Int).SetString(lit.Value, 0)
+		if !ok {
+			return tv, fmt.Errorf("invalid key %s", lit.Value)
+		}
+		ConvertUntypedBigintTo(&tv, BigintValue{V: bi}, kt)
+	default:
+		return tv, fmt.Errorf("invalid key %s", lit.Value)
+	}
+	return tv, nil
+}
+
+// objectOfValue returns the object v is, or refers to, or nil.
+func objectOfValue(store Store, v Value) Object {
+	switch cv := v.(type) {
+	case RefValue:
+		return store.GetObject(cv.ObjectID)
+	case PointerValue:
+		if cv.TV != nil {
+			if oo := objectOfValue(store, cv.TV.V); oo != nil {
+				return oo
+			}
+		}
+		return objectOfValue(store, cv.Base)
+	case *SliceValue:
+		return cv.GetBase(store)
+	case *PackageValue:
+		return cv.GetBlock(store)
+	case Object:
+		return cv
+	default:
+		return nil
+	}
+}
+
+// typeOfObject returns the type of the object oo, found in its owner, or
+// nil if it cannot be determined.
+func typeOfObject(store Store, oo Object) Type {
+	oid := oo.GetObjectID()
+	ownerID := oo.GetObjectInfo().OwnerID
+	if ownerID.IsZero() {
+		return nil
+	}
+	var tvs []TypedValue
+	switch owner := store.GetObject(ownerID).(type) {
+	case *Block:
+		tvs = owner.Values
+	case *StructValue:
+		tvs = owner.Fields
+	case *ArrayValue:
+		tvs = owner.List
+	case *MapValue:
+		for cur := owner.List.Head; cur != nil; cur = cur.Next {
+			tvs = append(tvs, cur.Key, cur.Value)
+		}
+	case *BoundMethodValue:
+		tvs = []TypedValue{owner.Receiver}
+	}
+	for _, tv := range tvs {
+		switch cv := tv.V.(type) {
+		case PointerValue:
+			if cv.TV != nil && isObjectValue(cv.TV.V, oid) {
+				return cv.TV.T
+			}
+		case *SliceValue:
+			if isObjectValue(cv.Base, oid) {
+				return &ArrayType{
+					Len: cv.GetCapacity(),
+					Elt: baseOf(tv.T).Elem(),
+				}
+			}
+		default:
+			if isObjectValue(cv, oid) {
+				return tv.T
+			}
+		}
+	}
+	return nil
+}
+
+// isObjectValue returns true if v is the object oid, or a reference to it.
+func isObjectValue(v Value, oid ObjectID) bool {
+	switch cv := v.(type) {
+	case RefValue:
+		return cv.ObjectID == oid
+	case Object:
+		return cv.GetObjectID() == oid
+	default:
+		return false
+	}
+}
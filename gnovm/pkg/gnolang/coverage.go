@@ -0,0 +1,175 @@
+package gnolang
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Coverage records how many times the statements of registered files are
+// executed, by line: each executed statement counts as a hit on the
+// Location (with no nonce) of its line. Statements of files that are not
+// registered are ignored. See Machine.Coverage.
+//
+// Coverage is reported by line, as Gno nodes only know their line, in the
+// format of Go cover profiles (see WriteProfile), so that the tools of Go,
+// e.g. `go tool cover -html`, also work with .gno files.
+type Coverage struct {
+	lines map[Location]*coverLine
+	paths map[string]map[int]*coverLine // by file path and line.
+}
+
+// A coverLine is a line of a file with statements.
+type coverLine struct {
+	path     string // path of the file in profiles.
+	line     int
+	startCol int // of the first non-blank character.
+	endCol   int // after the last character.
+	numStmts int
+	count    int
+}
+
+func NewCoverage() *Coverage {
+	return &Coverage{
+		lines: make(map[Location]*coverLine),
+		paths: make(map[string]map[int]*coverLine),
+	}
+}
+
+// AddFile registers the statements of the file fname of the package
+// pkgPath, whose source is body, to be reported as path. A file may be
+// registered with the same path under several package paths, e.g. as the
+// directory of the package and as its import path; hits under any of them
+// are then reported together.
+func (c *Coverage) AddFile(pkgPath string, fname string, path string, body string) error {
+	fn, err := ParseFile(fname, body)
+	if err != nil {
+		return err
+	}
+	srcLines := strings.Split(body, "\n")
+	pls, ok := c.paths[path]
+	if !ok {
+		pls = make(map[int]*coverLine)
+		c.paths[path] = pls
+	}
+	// count the statements of each line.
+	numStmts := make(map[int]int)
+	for _, d := range fn.Decls {
+		fd, ok := d.(*FuncDecl)
+		if !ok {
+			continue
+		}
+		Transcribe(fd, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+			if stage != TRANS_ENTER {
+				return n, TRANS_CONTINUE
+			}
+			switch n.(type) {
+			case *BlockStmt, *EmptyStmt,
+				*IfCaseStmt, *SwitchClauseStmt, *SelectCaseStmt:
+				// not statements of their own.
+			case Stmt:
+				if ln := n.GetLine(); ln > 0 && ln <= len(srcLines) {
+					numStmts[ln]++
+				}
+			}
+			return n, TRANS_CONTINUE
+		})
+	}
+	for ln, num := range numStmts {
+		cl, ok := pls[ln]
+		if !ok {
+			src := srcLines[ln-1]
+			cl = &coverLine{
+				path:     path,
+				line:     ln,
+				startCol: len(src) - len(strings.TrimLeft(src, " \t")) + 1,
+				endCol:   len(strings.TrimRight(src, " \t\r")) + 1,
+				numStmts: num,
+			}
+			pls[ln] = cl
+		}
+		loc := Location{PkgPath: pkgPath, File: fname, Line: ln}
+		c.lines[loc] = cl
+	}
+	return nil
+}
+
+// Hit records the execution of a statement at loc.
+func (c *Coverage) Hit(loc Location) {
+	loc.Nonce = 0
+	if cl, ok := c.lines[loc]; ok {
+		cl.count++
+	}
+}
+
+// Percent returns the percentage of the statements of registered files
+// that were executed, or 0 if there are none.
+func (c *Coverage) Percent() float64 {
+	var total, covered int
+	for _, pls := range c.paths {
+		for _, cl := range pls {
+			total += cl.numStmts
+			if cl.count > 0 {
+				covered += cl.numStmts
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(covered) / float64(total)
+}
+
+// NumCovered returns the number of lines of registered files with
+// executed statements. As it only grows, fuzzing uses it to find inputs
+// that reach new code.
+func (c *Coverage) NumCovered() int {
+	var covered int
+	for _, pls := range c.paths {
+		for _, cl := range pls {
+			if cl.count > 0 {
+				covered++
+			}
+		}
+	}
+	return covered
+}
+
+// WriteProfile writes the blocks of a Go cover profile in "count" mode,
+// one per line with statements, sorted by path and line. The leading
+// "mode: count" line is not written, so that the profiles of several
+// packages can be written one after the other.
+func (c *Coverage) WriteProfile(w io.Writer) error {
+	cls := make([]*coverLine, 0, len(c.lines))
+	for _, pls := range c.paths {
+		for _, cl := range pls {
+			cls = append(cls, cl)
+		}
+	}
+	sort.Slice(cls, func(i, j int) bool {
+		if cls[i].path != cls[j].path {
+			return cls[i].path < cls[j].path
+		}
+		return cls[i].line < cls[j].line
+	})
+	for _, cl := range cls {
+		_, err := fmt.Fprintf(w, "%s:%d.%d,%d.%d %d %d\n",
+			cl.path, cl.line, cl.startCol, cl.line, cl.endCol,
+			cl.numStmts, cl.count)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Records the execution of s, if covering.
+func (m *Machine) coverStmt(s Stmt) {
+	if _, ok := s.(*bodyStmt); ok {
+		return
+	}
+	loc := m.LastBlock().GetSource(m.Store).GetLocation()
+	loc.Line = s.GetLine()
+	m.Coverage.Hit(loc)
+}
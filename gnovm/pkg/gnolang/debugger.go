@@ -0,0 +1,452 @@
+package gnolang
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DebugAction tells a Debugger where to stop next.
+type DebugAction int
+
+const (
+	DebugContinue DebugAction = iota // run until a breakpoint.
+	DebugStep                        // stop at the next line.
+	DebugNext                        // stop at the next line of the frame or its callers.
+	DebugStepOut                     // stop at the next line of a caller.
+)
+
+// Debugger stops the execution of a machine at breakpoints, or after
+// stepping, and hands control to OnStop, which inspects the machine and
+// returns how to resume. Breakpoints are set by file and line; a breakpoint
+// file matches the files of executed statements that have it as a path
+// suffix, e.g. "main.gno" or "gno.land/r/demo/foo/foo.gno". See
+// Machine.Debugger.
+//
+// OnStop is called on the goroutine running the machine, which waits for
+// it to return. To abort the execution, OnStop may panic.
+type Debugger struct {
+	OnStop func(m *Machine, loc Location) DebugAction
+
+	breakpoints map[int][]string // files by line.
+	action      DebugAction
+	stopDepth   int      // number of call frames when last stopped.
+	depth       int      // number of call frames of the last statement.
+	loc         Location // of the last executed statement.
+}
+
+// NewDebugger returns a debugger that first stops as if resumed with
+// action, e.g. DebugStep to stop at the first executed statement.
+func NewDebugger(action DebugAction, onStop func(m *Machine, loc Location) DebugAction) *Debugger {
+	return &Debugger{
+		OnStop:      onStop,
+		breakpoints: make(map[int][]string),
+		action:      action,
+	}
+}
+
+// SetBreakpoint sets a breakpoint at the line of file.
+func (d *Debugger) SetBreakpoint(file string, line int) {
+	for _, f := range d.breakpoints[line] {
+		if f == file {
+			return
+		}
+	}
+	d.breakpoints[line] = append(d.breakpoints[line], file)
+}
+
+// ClearBreakpoint clears the breakpoint at the line of file, and returns
+// whether there was one.
+func (d *Debugger) ClearBreakpoint(file string, line int) bool {
+	files := d.breakpoints[line]
+	for i, f := range files {
+		if f == file {
+			d.breakpoints[line] = append(files[:i:i], files[i+1:]...)
+			if len(d.breakpoints[line]) == 0 {
+				delete(d.breakpoints, line)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// ClearBreakpoints clears the breakpoints of file, or all breakpoints if
+// file is empty.
+func (d *Debugger) ClearBreakpoints(file string) {
+	for line, files := range d.breakpoints {
+		for _, f := range files {
+			if file == "" || f == file {
+				d.ClearBreakpoint(f, line)
+			}
+		}
+	}
+}
+
+// Breakpoints returns the breakpoints, sorted by file and line.
+func (d *Debugger) Breakpoints() []Location {
+	var locs []Location
+	for line, files := range d.breakpoints {
+		for _, f := range files {
+			locs = append(locs, Location{File: f, Line: line})
+		}
+	}
+	sort.Slice(locs, func(i, j int) bool {
+		if locs[i].File != locs[j].File {
+			return locs[i].File < locs[j].File
+		}
+		return locs[i].Line < locs[j].Line
+	})
+	return locs
+}
+
+func (d *Debugger) isBreakpoint(loc Location) bool {
+	for _, f := range d.breakpoints[loc.Line] {
+		full := path.Join(loc.PkgPath, loc.File)
+		if f == loc.File || f == full || strings.HasSuffix(full, "/"+f) {
+			return true
+		}
+	}
+	return false
+}
+
+// Called before the execution of s, if debugging.
+func (m *Machine) debugStmt(s Stmt) {
+	if _, ok := s.(*bodyStmt); ok {
+		return
+	}
+	if s.GetLine() <= 0 {
+		return
+	}
+	d := m.Debugger
+	loc := m.LastBlock().GetSource(m.Store).GetLocation()
+	loc.Line = s.GetLine()
+	loc.Nonce = 0
+	depth := m.numCallFrames()
+	moved := loc != d.loc || depth != d.depth
+	d.loc, d.depth = loc, depth
+	if !moved {
+		return
+	}
+	stop := d.isBreakpoint(loc)
+	switch d.action {
+	case DebugStep:
+		stop = true
+	case DebugNext:
+		stop = stop || depth <= d.stopDepth
+	case DebugStepOut:
+		stop = stop || depth < d.stopDepth
+	}
+	if !stop {
+		return
+	}
+	d.stopDepth = depth
+	if d.OnStop == nil {
+		d.action = DebugContinue
+		return
+	}
+	d.action = d.OnStop(m, loc)
+}
+
+func (m *Machine) numCallFrames() int {
+	n := 0
+	for i := range m.Frames {
+		if m.Frames[i].Func != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// DebugFrame is a function call of a stopped machine.
+type DebugFrame struct {
+	Func string   // e.g. "main.fib" or "gno.land/p/demo/avl.(*Node).Get".
+	Loc  Location // of the current statement of the call.
+
+	block *Block // innermost block of the call.
+}
+
+// Stack returns the function calls of the machine, innermost first,
+// stopped at loc, the location of the current statement.
+func (d *Debugger) Stack(m *Machine, loc Location) []DebugFrame {
+	var dfs []DebugFrame
+	end := len(m.Blocks)
+	for i := len(m.Frames) - 1; i >= 0; i-- {
+		fr := &m.Frames[i]
+		if fr.Func == nil {
+			continue
+		}
+		if end == 0 {
+			break
+		}
+		dfs = append(dfs, DebugFrame{
+			Func:  debugFuncName(fr.Func),
+			Loc:   loc,
+			block: m.Blocks[end-1],
+		})
+		// the caller is at the call, in the block before the call.
+		end = fr.NumBlocks
+		loc = Location{}
+		if end > 0 {
+			loc = m.Blocks[end-1].GetSource(m.Store).GetLocation()
+			loc.Nonce = 0
+			if fr.Source != nil {
+				loc.Line = fr.Source.GetLine()
+			}
+		}
+	}
+	return dfs
+}
+
+func debugFuncName(fv *FuncValue) string {
+	name := string(fv.Name)
+	if name == "" {
+		name = "func1"
+	}
+	if fd, ok := fv.Source.(*FuncDecl); ok && fd.IsMethod {
+		recv := fd.Recv.Type.String()
+		if !strings.HasPrefix(recv, "*") {
+			name = recv + "." + name
+		} else {
+			name = "(" + recv + ")." + name
+		}
+	}
+	return fv.PkgPath + "." + name
+}
+
+// DebugVar is a named value of a stopped machine.
+type DebugVar struct {
+	Name  string
+	Value TypedValue
+}
+
+// Locals returns the variables declared in the function of the call,
+// by block, innermost first, including the variables it captures from
+// enclosing functions.
+func (df DebugFrame) Locals(store Store) []DebugVar {
+	var vars []DebugVar
+	seen := make(map[Name]bool)
+	for b := df.block; b != nil; b = b.GetParent(store) {
+		switch b.GetSource(store).(type) {
+		case *FileNode, *PackageNode:
+			return vars
+		}
+		vars = appendBlockVars(store, vars, b, seen, false)
+	}
+	return vars
+}
+
+// Globals returns the variables declared in the package of the call,
+// sorted by name. Types and functions are not included.
+func (df DebugFrame) Globals(store Store) []DebugVar {
+	b := df.packageBlock(store)
+	if b == nil {
+		return nil
+	}
+	vars := appendBlockVars(store, nil, b, make(map[Name]bool), true)
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].Name < vars[j].Name
+	})
+	return vars
+}
+
+func (df DebugFrame) packageBlock(store Store) *Block {
+	for b := df.block; b != nil; b = b.GetParent(store) {
+		if _, ok := b.GetSource(store).(*PackageNode); ok {
+			return b
+		}
+	}
+	return nil
+}
+
+func appendBlockVars(store Store, vars []DebugVar, b *Block, seen map[Name]bool, global bool) []DebugVar {
+	names := b.GetSource(store).GetBlockNames()
+	for i, name := range names {
+		if name == "_" || name == "" || name[0] == '.' || seen[name] || i >= len(b.Values) {
+			continue
+		}
+		seen[name] = true
+		tv := fillValueTV(store, &b.Values[i])
+		if global {
+			if tv.T != nil && tv.T.Kind() == TypeKind {
+				continue
+			}
+			if fv, ok := tv.V.(*FuncValue); ok && fv.Name == name {
+				continue
+			}
+		}
+		vars = append(vars, DebugVar{Name: string(name), Value: *tv})
+	}
+	return vars
+}
+
+// Inspect returns the value of selector in the scope of the call, e.g.
+// "x", "node.Left.Key" or "list[2]" (indexes must be literals).
+func (df DebugFrame) Inspect(store Store, selector string) (TypedValue, error) {
+	x, err := ParseExpr(selector)
+	if err != nil {
+		return TypedValue{}, err
+	}
+	root := x
+	for {
+		switch rx := root.(type) {
+		case *SelectorExpr:
+			root = rx.X
+			continue
+		case *IndexExpr:
+			root = rx.X
+			continue
+		}
+		break
+	}
+	nx, ok := root.(*NameExpr)
+	if !ok {
+		return TypedValue{}, fmt.Errorf("invalid selector %s", selector)
+	}
+	for b := df.block; b != nil; b = b.GetParent(store) {
+		names := b.GetSource(store).GetBlockNames()
+		for _, name := range names {
+			if name != nx.Name {
+				continue
+			}
+			cur := browseCursor{store: store, block: b}
+			if err := cur.walk(x, false); err != nil {
+				return TypedValue{}, err
+			}
+			return *cur.tv, nil
+		}
+	}
+	return TypedValue{}, fmt.Errorf("name %s not declared", nx.Name)
+}
+
+// DebugChildren returns the elements of tv that can be inspected: the
+// fields of a struct, the elements of an array, slice or map, and the
+// value pointed to by a pointer, named "*".
+func DebugChildren(store Store, tv TypedValue) []DebugVar {
+	if tv.T == nil || tv.V == nil {
+		return nil
+	}
+	var vars []DebugVar
+	switch bt := baseOf(tv.T).(type) {
+	case *PointerType:
+		pv := tv.V.(PointerValue)
+		if pv.TV != nil {
+			vars = append(vars, DebugVar{Name: "*", Value: *fillValueTV(store, pv.TV)})
+		}
+	case *StructType:
+		sv := tv.V.(*StructValue)
+		for i, f := range bt.Fields {
+			vars = append(vars, DebugVar{Name: string(f.Name), Value: *fillValueTV(store, &sv.Fields[i])})
+		}
+	case *ArrayType, *SliceType:
+		var av *ArrayValue
+		var offset, length int
+		if sv, ok := tv.V.(*SliceValue); ok {
+			av = sv.GetBase(store)
+			offset, length = sv.Offset, sv.Length
+		} else {
+			av = tv.V.(*ArrayValue)
+			length = av.GetLength()
+		}
+		for i := 0; i < length; i++ {
+			var etv TypedValue
+			if av.Data != nil {
+				etv = TypedValue{T: bt.Elem()}
+				etv.SetUint8(av.Data[offset+i])
+			} else {
+				etv = *fillValueTV(store, &av.List[offset+i])
+			}
+			vars = append(vars, DebugVar{Name: fmt.Sprintf("[%d]", i), Value: etv})
+		}
+	case *MapType:
+		mv := tv.V.(*MapValue)
+		for item := mv.List.Head; item != nil; item = item.Next {
+			name := "[" + debugValueString(store, item.Key, 1) + "]"
+			vars = append(vars, DebugVar{Name: name, Value: *fillValueTV(store, &item.Value)})
+		}
+	}
+	return vars
+}
+
+// DebugValueString returns a short representation of tv, in the syntax of
+// Go values, e.g. `point{X: 1, Y: 2}`, `"foo"` or `[1, 2, 3]`. Long or
+// nested values are elided with "...".
+func DebugValueString(store Store, tv TypedValue) string {
+	return debugValueString(store, tv, 2)
+}
+
+const debugMaxElems = 10
+
+func debugValueString(store Store, tv TypedValue, depth int) string {
+	if tv.T == nil {
+		return nilStr
+	}
+	switch bt := baseOf(tv.T).(type) {
+	case PrimitiveType:
+		if bt.Kind() == StringKind {
+			return strconv.Quote(tv.GetString())
+		}
+		ptv := tv
+		ptv.T = bt // for Sprint not to call methods.
+		return ptv.Sprint(nil)
+	case *PointerType:
+		if tv.V == nil {
+			return nilStr
+		}
+		pv := tv.V.(PointerValue)
+		if pv.TV == nil {
+			return nilStr
+		}
+		return "&" + debugValueString(store, *fillValueTV(store, pv.TV), depth)
+	case *StructType:
+		name := tv.T.String()
+		if dt, ok := tv.T.(*DeclaredType); ok {
+			name = string(dt.Name)
+		}
+		if depth <= 0 {
+			return name + "{...}"
+		}
+		var ss []string
+		for _, c := range DebugChildren(store, tv) {
+			ss = append(ss, c.Name+": "+debugValueString(store, c.Value, depth-1))
+		}
+		return name + "{" + strings.Join(ss, ", ") + "}"
+	case *ArrayType, *SliceType, *MapType:
+		if tv.V == nil {
+			return nilStr
+		}
+		if depth <= 0 {
+			return "[...]"
+		}
+		_, isMap := bt.(*MapType)
+		var ss []string
+		for i, c := range DebugChildren(store, tv) {
+			if i == debugMaxElems {
+				ss = append(ss, "...")
+				break
+			}
+			s := debugValueString(store, c.Value, depth-1)
+			if isMap {
+				s = strings.TrimSuffix(strings.TrimPrefix(c.Name, "["), "]") + ": " + s
+			}
+			ss = append(ss, s)
+		}
+		if isMap {
+			return "map[" + strings.Join(ss, ", ") + "]"
+		}
+		return "[" + strings.Join(ss, ", ") + "]"
+	case *FuncType:
+		switch fv := tv.V.(type) {
+		case nil:
+			return nilStr
+		case *FuncValue:
+			return debugFuncName(fv)
+		default:
+			return tv.T.String()
+		}
+	default:
+		return tv.String()
+	}
+}
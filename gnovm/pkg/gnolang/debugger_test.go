@@ -0,0 +1,128 @@
+package gnolang
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jaekwon/testify/assert"
+)
+
+const debugTestMain = `package test
+
+type point struct {
+	X, Y int
+}
+
+var origin = point{}
+
+func dist(p point) int {
+	d := p.X - origin.X
+	if d < 0 {
+		d = -d
+	}
+	return d + p.Y
+}
+
+func main() {
+	ps := []point{{1, 2}, {-3, 4}}
+	sum := 0
+	for _, p := range ps {
+		sum += dist(p)
+	}
+	println(sum)
+}`
+
+// runs debugTestMain, calling onStop at each stop with the stack.
+func runDebugTest(t *testing.T, action DebugAction, bps []int, onStop func(d *Debugger, dfs []DebugFrame) DebugAction) {
+	t.Helper()
+
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath: "test",
+		Output:  io.Discard,
+	})
+	m.RunFiles(MustParseFile("main.gno", debugTestMain))
+	var d *Debugger
+	d = NewDebugger(action, func(m *Machine, loc Location) DebugAction {
+		return onStop(d, d.Stack(m, loc))
+	})
+	for _, line := range bps {
+		d.SetBreakpoint("main.gno", line)
+	}
+	m.Debugger = d
+	m.RunMain()
+}
+
+func TestDebuggerBreakpoints(t *testing.T) {
+	var stops []string
+	runDebugTest(t, DebugContinue, []int{10, 14}, func(d *Debugger, dfs []DebugFrame) DebugAction {
+		var funcs []string
+		for _, df := range dfs {
+			funcs = append(funcs, fmt.Sprintf("%s:%d", df.Func, df.Loc.Line))
+		}
+		stops = append(stops, strings.Join(funcs, " "))
+		return DebugContinue
+	})
+	assert.Equal(t, []string{
+		"test.dist:10 test.main:21",
+		"test.dist:14 test.main:21",
+		"test.dist:10 test.main:21",
+		"test.dist:14 test.main:21",
+	}, stops)
+}
+
+func TestDebuggerStepping(t *testing.T) {
+	var lines []int
+	actions := []DebugAction{DebugNext, DebugNext, DebugNext, DebugNext, DebugStep, DebugStepOut, DebugContinue}
+	runDebugTest(t, DebugStep, nil, func(d *Debugger, dfs []DebugFrame) DebugAction {
+		lines = append(lines, dfs[0].Loc.Line)
+		action := actions[0]
+		actions = actions[1:]
+		return action
+	})
+	// 18-21: main and the first iteration, over the call to dist; 21: the
+	// second iteration, 10: into dist, 23: out of it, after the loop.
+	assert.Equal(t, []int{18, 19, 20, 21, 21, 10, 23}, lines)
+}
+
+func TestDebuggerInspect(t *testing.T) {
+	stops := 0
+	runDebugTest(t, DebugContinue, []int{14}, func(d *Debugger, dfs []DebugFrame) DebugAction {
+		stops++
+		if stops > 1 {
+			return DebugContinue
+		}
+		store := NewStore(nil, nil, nil)
+
+		var locals []string
+		for _, v := range dfs[0].Locals(store) {
+			locals = append(locals, v.Name+"="+v.Value.String())
+		}
+		assert.Equal(t, []string{"p=(struct{(1 int),(2 int)} test.point)", "d=(1 int)"}, locals)
+
+		var globals []string
+		for _, v := range dfs[0].Globals(store) {
+			globals = append(globals, v.Name)
+		}
+		assert.Equal(t, []string{"origin"}, globals)
+
+		tv, err := dfs[1].Inspect(store, "ps[1].X")
+		assert.NoError(t, err)
+		assert.Equal(t, "(-3 int)", tv.String())
+		_, err = dfs[1].Inspect(store, "nope")
+		assert.EqualError(t, err, "name nope not declared")
+
+		tv, err = dfs[1].Inspect(store, "ps")
+		assert.NoError(t, err)
+		assert.Equal(t, "[point{X: 1, Y: 2}, point{X: -3, Y: 4}]", DebugValueString(store, tv))
+		children := DebugChildren(store, tv)
+		assert.Equal(t, 2, len(children))
+		assert.Equal(t, "[1]", children[1].Name)
+		fields := DebugChildren(store, children[1].Value)
+		assert.Equal(t, "Y", fields[1].Name)
+		assert.Equal(t, "(4 int)", fields[1].Value.String())
+		return DebugContinue
+	})
+	assert.Equal(t, 2, stops)
+}
@@ -0,0 +1,837 @@
+package gnolang
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Generic functions and types are instantiated by the preprocessor. A
+// generic declaration is not preprocessed itself; its name is only
+// predefined with a *genericType static type, and has no runtime value.
+// When it is instantiated with a list of type arguments, given explicitly
+// as in `Max[int]` or inferred from the arguments of a call, a copy of its
+// declaration is made with the type parameters substituted, which is then
+// preprocessed like any other declaration. For a generic type, the copies
+// of its methods become the methods of the new *DeclaredType.
+//
+// Instances are named after their type arguments, e.g. "Pair[int,string]",
+// and are cached in the store, so each is only instantiated once. As
+// nodes, instances are not persisted but re-instantiated when the package
+// that uses them is preprocessed again.
+
+// The static type of the name of a generic declaration.
+type genericType struct {
+	Decl Decl      // *FuncDecl or *TypeDecl with TypeParams.
+	File *FileNode // file of Decl.
+}
+
+func (gt *genericType) assertType() {}
+
+func (gt *genericType) Kind() Kind {
+	return InvalidKind
+}
+
+func (gt *genericType) TypeID() TypeID {
+	return typeid("generic{%s.%s}", gt.GetPkgPath(), gt.Name())
+}
+
+func (gt *genericType) String() string {
+	return fmt.Sprintf("%s[%s]", gt.Name(), gt.TypeParams().String())
+}
+
+func (gt *genericType) Elem() Type {
+	panic("generic type has no elem type")
+}
+
+func (gt *genericType) GetPkgPath() string {
+	return packageOf(gt.File).PkgPath
+}
+
+func (gt *genericType) Name() Name {
+	return gt.Decl.GetDeclNames()[0]
+}
+
+func (gt *genericType) TypeParams() FieldTypeExprs {
+	switch d := gt.Decl.(type) {
+	case *FuncDecl:
+		return d.TypeParams
+	case *TypeDecl:
+		return d.TypeParams
+	default:
+		panic("should not happen")
+	}
+}
+
+func isGenericDecl(d Node) bool {
+	switch d := d.(type) {
+	case *FuncDecl:
+		return len(d.TypeParams) > 0
+	case *TypeDecl:
+		return len(d.TypeParams) > 0
+	default:
+		return false
+	}
+}
+
+// Returns the generic declaration of x, or of x's generic in an explicit
+// (possibly partial) instantiation, or nil if x isn't generic.
+func genericOf(store Store, last BlockNode, x Expr) *genericType {
+	switch x := x.(type) {
+	case *NameExpr, *SelectorExpr:
+		gt, _ := evalStaticTypeOf(store, last, x).(*genericType)
+		return gt
+	case *IndexExpr:
+		return genericOf(store, last, x.X)
+	case *IndexListExpr:
+		return genericOf(store, last, x.X)
+	}
+	return nil
+}
+
+// Returns the explicit type arguments of x, if any.
+func typeArgsOf(store Store, last BlockNode, x Expr) []Type {
+	var idxs []Expr
+	switch x := x.(type) {
+	case *IndexExpr:
+		idxs = []Expr{x.Index}
+	case *IndexListExpr:
+		idxs = x.Indices
+	}
+	targs := make([]Type, len(idxs))
+	for i, idx := range idxs {
+		targs[i] = evalStaticType(store, last, idx)
+	}
+	return targs
+}
+
+// Instantiates the generic of x, which is X[Index] or X[Indices...], and
+// returns the expression to replace x with. If the type arguments of a
+// called generic function are partial, x is returned for the *CallExpr to
+// infer the rest.
+func instantiateIndex(store Store, last BlockNode, ftype TransField, x Expr, gt *genericType) Expr {
+	targs := typeArgsOf(store, last, x)
+	if _, ok := gt.Decl.(*FuncDecl); ok &&
+		ftype == TRANS_CALL_FUNC && len(targs) < len(gt.TypeParams()) {
+		return x
+	}
+	tv := instantiate(store, gt, targs)
+	return instanceExpr(x, tv)
+}
+
+// Instantiates the generic function called by cx, inferring any type
+// arguments not given explicitly from the call arguments, and returns the
+// expression to replace cx.Func with.
+func instantiateCall(store Store, last BlockNode, cx *CallExpr, gt *genericType) Expr {
+	fd, ok := gt.Decl.(*FuncDecl)
+	if !ok {
+		panic(fmt.Sprintf(
+			"cannot use generic type %s without instantiation",
+			gt.Name()))
+	}
+	targs := typeArgsOf(store, last, cx.Func)
+	if len(targs) < len(fd.TypeParams) {
+		targs = inferTypeArgs(store, last, fd, targs, cx)
+	}
+	tv := instantiate(store, gt, targs)
+	return instanceExpr(cx.Func, tv)
+}
+
+func instanceExpr(source Expr, tv TypedValue) Expr {
+	if tv.T.Kind() == TypeKind {
+		t := tv.GetType()
+		// for the deferred composite type elision.
+		source.SetAttribute(ATTR_TYPE_VALUE, t)
+		return constType(source, t)
+	}
+	cx := &ConstExpr{
+		Source:     source,
+		TypedValue: tv,
+	}
+	cx.SetAttribute(ATTR_PREPROCESSED, true)
+	setConstAttrs(cx)
+	return cx
+}
+
+// ----------------------------------------
+// Instantiation
+
+// Instances are preprocessed in two phases: first their signatures, then
+// once the outermost instantiation is done, the bodies of functions and
+// methods, so that the bodies of mutually recursive instances can refer
+// to each other's methods.
+var (
+	instantiating    int
+	pendingInstances []pendingInstance
+	instanceKeys     []string // cached during outermost instantiation.
+)
+
+type pendingInstance struct {
+	File *FileNode
+	Decl *FuncDecl
+}
+
+// Returns the instance of gt for targs, which is a *FuncValue or a type
+// value of a *DeclaredType.
+func instantiate(store Store, gt *genericType, targs []Type) TypedValue {
+	tparams := gt.TypeParams()
+	if len(targs) != len(tparams) {
+		panic(fmt.Sprintf(
+			"wrong number of type arguments for %s: have %d, want %d",
+			gt.Name(), len(targs), len(tparams)))
+	}
+	name := instanceName(gt.Name(), targs)
+	key := gt.GetPkgPath() + "." + string(name)
+	if tv := store.GetCacheInstance(key); tv != nil {
+		return *tv
+	}
+	checkTypeArgs(store, gt, targs)
+
+	instantiating++
+	defer func() {
+		instantiating--
+		if instantiating == 0 {
+			if r := recover(); r != nil {
+				// uncache incomplete instances.
+				for _, key := range instanceKeys {
+					store.SetCacheInstance(key, TypedValue{})
+				}
+				pendingInstances = nil
+				instanceKeys = nil
+				panic(r)
+			}
+		}
+	}()
+	var tv TypedValue
+	switch d := gt.Decl.(type) {
+	case *FuncDecl:
+		tv = instantiateFunc(store, gt, d, targs, name, key)
+	case *TypeDecl:
+		tv = instantiateType(store, gt, d, targs, name, key)
+	default:
+		panic("should not happen")
+	}
+	if instantiating == 1 {
+		for len(pendingInstances) > 0 {
+			pi := pendingInstances[0]
+			pendingInstances = pendingInstances[1:]
+			fd := Preprocess(store, pi.File, pi.Decl).(*FuncDecl)
+			saveInstanceNodes(store, fd)
+		}
+		pendingInstances = nil
+		instanceKeys = nil
+	}
+	return tv
+}
+
+func cacheInstance(store Store, key string, tv TypedValue) {
+	store.SetCacheInstance(key, tv)
+	instanceKeys = append(instanceKeys, key)
+}
+
+func instanceName(name Name, targs []Type) Name {
+	strs := make([]string, len(targs))
+	for i, targ := range targs {
+		strs[i] = targ.String()
+	}
+	return Name(fmt.Sprintf("%s[%s]", name, strings.Join(strs, ",")))
+}
+
+// Instance nodes are located in a pseudo-file named after the instance,
+// so that their locations do not collide with those of the generic.
+func instanceFileName(fn *FileNode, name Name) string {
+	return fmt.Sprintf("%s:%s", fn.Name, name)
+}
+
+func instantiateFunc(store Store, gt *genericType, fd *FuncDecl, targs []Type, name Name, key string) TypedValue {
+	fn := gt.File
+	pn := packageOf(fn)
+	fd2 := copyWithLines(fd).(*FuncDecl)
+	substTypeParams(fd2, fd.TypeParams, targs)
+	fd2.TypeParams = nil
+	fd2.Name = name
+	SetNodeLocations(pn.PkgPath, instanceFileName(fn, name), fd2)
+	predefineDependencies(store, fn, fd2)
+	fd2.Type = *Preprocess(store, fn, &fd2.Type).(*FuncTypeExpr)
+	ft := evalStaticType(store, fn, &fd2.Type).(*FuncType)
+	tv := TypedValue{
+		T: ft,
+		V: &FuncValue{
+			Type:       ft,
+			IsMethod:   false,
+			Source:     fd2,
+			Name:       name,
+			Closure:    nil, // set lazily.
+			FileName:   fn.Name,
+			PkgPath:    pn.PkgPath,
+			body:       fd2.Body,
+			nativeBody: nil,
+		},
+	}
+	cacheInstance(store, key, tv)
+	fd2.SetAttribute(ATTR_PREDEFINED, true)
+	pendingInstances = append(pendingInstances, pendingInstance{fn, fd2})
+	return tv
+}
+
+func instantiateType(store Store, gt *genericType, td *TypeDecl, targs []Type, name Name, key string) TypedValue {
+	if td.IsAlias {
+		panic(fmt.Sprintf(
+			"generic type %s cannot be an alias",
+			td.Name))
+	}
+	fn := gt.File
+	pn := packageOf(fn)
+	// if store has this type (e.g. upon restart), use that,
+	// but still instantiate its methods for their nodes.
+	tid := DeclaredTypeID(pn.PkgPath, name)
+	dt, _ := store.GetTypeSafe(tid).(*DeclaredType)
+	fresh := dt == nil
+	if fresh {
+		dt = &DeclaredType{
+			PkgPath: pn.PkgPath,
+			Name:    name,
+			Base:    placeholderType(td.Type),
+		}
+	}
+	// cache before preprocessing, for recursive types.
+	tv := asValue(dt)
+	cacheInstance(store, key, tv)
+	td2 := copyWithLines(td).(*TypeDecl)
+	substTypeParams(td2, td.TypeParams, targs)
+	predefineDependencies(store, fn, td2)
+	tx := Preprocess(store, fn, td2.Type).(Expr)
+	if fresh {
+		dt.Base = baseOf(evalStaticType(store, fn, tx))
+		dt.Seal()
+	}
+	// instantiate methods.
+	if pn.FileSet != nil {
+		for _, mfn := range pn.FileSet.Files {
+			for _, d := range mfn.Decls {
+				md, ok := d.(*FuncDecl)
+				if !ok || !md.IsMethod || len(md.TypeParams) == 0 ||
+					recvTypeName(md) != td.Name {
+					continue
+				}
+				md2 := instantiateMethod(store, mfn, md, targs, name)
+				// the signature is evaluated even if the type was
+				// stored, for the body to be preprocessed.
+				ft := evalStaticType(store, mfn, &md2.Type).(*FuncType)
+				rft := evalStaticType(store, mfn, &md2.Recv).(FieldType)
+				if fresh {
+					dt.DefineMethod(&FuncValue{
+						Type:       ft.UnboundType(rft),
+						IsMethod:   true,
+						Source:     md2,
+						Name:       md2.Name,
+						Closure:    nil, // set lazily.
+						FileName:   mfn.Name,
+						PkgPath:    pn.PkgPath,
+						body:       md2.Body,
+						nativeBody: nil,
+					})
+				}
+			}
+		}
+	}
+	if fresh {
+		store.SetType(dt)
+	}
+	return tv
+}
+
+// Returns an empty type of the kind of type expression x, to be the base
+// of a recursive type while it is being declared.
+func placeholderType(x Expr) Type {
+	switch x.(type) {
+	case *FuncTypeExpr:
+		return &FuncType{}
+	case *ArrayTypeExpr:
+		return &ArrayType{}
+	case *SliceTypeExpr:
+		return &SliceType{}
+	case *InterfaceTypeExpr:
+		return &InterfaceType{}
+	case *ChanTypeExpr:
+		return &ChanType{}
+	case *MapTypeExpr:
+		return &MapType{}
+	default:
+		return &StructType{}
+	}
+}
+
+// Instantiates the signature of method md of the generic type instance
+// named name, deferring its body.
+func instantiateMethod(store Store, fn *FileNode, md *FuncDecl, targs []Type, name Name) *FuncDecl {
+	pn := packageOf(fn)
+	md2 := copyWithLines(md).(*FuncDecl)
+	if len(md.TypeParams) != len(targs) {
+		panic(fmt.Sprintf(
+			"wrong number of type parameters in receiver of %s.%s",
+			name, md.Name))
+	}
+	substTypeParams(md2, md.TypeParams, targs)
+	md2.TypeParams = nil
+	SetNodeLocations(pn.PkgPath, instanceFileName(fn, name), md2)
+	if md2.Recv.Name == "" || md2.Recv.Name == "_" {
+		// create a hidden var with leading dot.
+		md2.Recv.Name = ".recv"
+	}
+	predefineDependencies(store, fn, md2)
+	md2.Recv = *Preprocess(store, fn, &md2.Recv).(*FieldTypeExpr)
+	md2.Type = *Preprocess(store, fn, &md2.Type).(*FuncTypeExpr)
+	md2.SetAttribute(ATTR_PREDEFINED, true)
+	pendingInstances = append(pendingInstances, pendingInstance{fn, md2})
+	return md2
+}
+
+// Returns the name of the (generic) receiver type of method md.
+func recvTypeName(md *FuncDecl) Name {
+	rx := md.Recv.Type
+	if sx, ok := rx.(*StarExpr); ok {
+		rx = sx.X
+	}
+	switch ix := rx.(type) {
+	case *IndexExpr:
+		rx = ix.X
+	case *IndexListExpr:
+		rx = ix.X
+	}
+	if nx, ok := rx.(*NameExpr); ok {
+		return nx.Name
+	}
+	return ""
+}
+
+// Checks that each type argument satisfies its constraint.
+func checkTypeArgs(store Store, gt *genericType, targs []Type) {
+	tparams := gt.TypeParams()
+	for i, tp := range tparams {
+		cx := copyWithLines(tp.Type).(Expr)
+		cx = substTypeParams(cx, tparams, targs).(Expr)
+		if isTypeTermExpr(cx) {
+			// a union or approximation element, as in
+			// `[T ~int | ~string]`, is short for an interface.
+			cx = &InterfaceTypeExpr{
+				Methods: []FieldTypeExpr{{Type: cx}},
+			}
+		}
+		cx = Preprocess(store, gt.File, cx).(Expr)
+		ct := evalStaticType(store, gt.File, cx)
+		if !satisfies(targs[i], ct) {
+			panic(fmt.Sprintf(
+				"%s does not satisfy %s",
+				targs[i].String(), ct.String()))
+		}
+	}
+}
+
+// Returns true if t satisfies the constraint ct.
+func satisfies(t Type, ct Type) bool {
+	if ct == gComparableType {
+		return isComparableType(t)
+	}
+	if it, ok := baseOf(ct).(*InterfaceType); ok {
+		return it.IsImplementedBy(t)
+	}
+	// a non-interface constraint is its only type.
+	return t.TypeID() == ct.TypeID()
+}
+
+// Returns true if values of t can be compared with ==.
+func isComparableType(t Type) bool {
+	switch ct := baseOf(t).(type) {
+	case *SliceType, *MapType, *FuncType:
+		return false
+	case *ArrayType:
+		return isComparableType(ct.Elt)
+	case *StructType:
+		for _, f := range ct.Fields {
+			if !isComparableType(f.Type) {
+				return false
+			}
+		}
+		return true
+	case *NativeType:
+		return ct.Type.Comparable()
+	default:
+		return true
+	}
+}
+
+// ----------------------------------------
+// Inference
+
+// Infers the type arguments of the call cx to the generic function fd,
+// given the explicit type arguments, from the types of its arguments.
+func inferTypeArgs(store Store, last BlockNode, fd *FuncDecl, explicit []Type, cx *CallExpr) []Type {
+	bound := make(map[Name]Type, len(fd.TypeParams))
+	for i, targ := range explicit {
+		bound[fd.TypeParams[i].Name] = targ
+	}
+	tparams := make(map[Name]struct{}, len(fd.TypeParams))
+	for _, tp := range fd.TypeParams {
+		tparams[tp.Name] = struct{}{}
+	}
+	// get param exprs and argument types, pairwise.
+	var ats []Type
+	if len(cx.Args) == 1 {
+		if tt, ok := evalStaticTypeOfRaw(store, last, cx.Args[0]).(*tupleType); ok {
+			ats = tt.Elts
+		}
+	}
+	if ats == nil {
+		ats = make([]Type, len(cx.Args))
+		for i, ax := range cx.Args {
+			ats[i] = evalStaticTypeOf(store, last, ax)
+		}
+	}
+	params := fd.Type.Params
+	pxs := make([]Expr, len(ats))
+	for i := range ats {
+		switch {
+		case len(params) == 0:
+		case i < len(params)-1:
+			pxs[i] = params[i].Type
+		default:
+			lpx := params[len(params)-1].Type
+			if sx, ok := lpx.(*SliceTypeExpr); ok && sx.Vrd && !cx.Varg {
+				pxs[i] = sx.Elt // variadic argument.
+			} else if i == len(params)-1 {
+				pxs[i] = lpx
+			}
+		}
+	}
+	// typed arguments first, then untyped constants.
+	for i, at := range ats {
+		if pxs[i] == nil || at == nil || isUntyped(at) {
+			continue
+		}
+		unify(pxs[i], at, tparams, bound)
+	}
+	for i, at := range ats {
+		if pxs[i] == nil || at == nil || !isUntyped(at) {
+			continue
+		}
+		if nx, ok := pxs[i].(*NameExpr); ok {
+			if _, ok := tparams[nx.Name]; ok && bound[nx.Name] == nil {
+				bound[nx.Name] = defaultTypeOf(at)
+			}
+		}
+	}
+	targs := make([]Type, len(fd.TypeParams))
+	for i, tp := range fd.TypeParams {
+		targs[i] = bound[tp.Name]
+		if targs[i] == nil {
+			panic(fmt.Sprintf(
+				"cannot infer %s in call to %s",
+				tp.Name, fd.Name))
+		}
+	}
+	return targs
+}
+
+// Binds the type parameters in the parameter type expression px by
+// matching it against the argument type at.
+func unify(px Expr, at Type, tparams map[Name]struct{}, bound map[Name]Type) {
+	switch px := px.(type) {
+	case *NameExpr:
+		if _, ok := tparams[px.Name]; ok {
+			if bound[px.Name] == nil {
+				bound[px.Name] = at
+			}
+		}
+	case *StarExpr:
+		if pt, ok := at.(*PointerType); ok {
+			unify(px.X, pt.Elt, tparams, bound)
+		}
+	case *SliceTypeExpr:
+		if st, ok := baseOf(at).(*SliceType); ok {
+			unify(px.Elt, st.Elt, tparams, bound)
+		}
+	case *ArrayTypeExpr:
+		if at, ok := baseOf(at).(*ArrayType); ok {
+			unify(px.Elt, at.Elt, tparams, bound)
+		}
+	case *MapTypeExpr:
+		if mt, ok := baseOf(at).(*MapType); ok {
+			unify(px.Key, mt.Key, tparams, bound)
+			unify(px.Value, mt.Value, tparams, bound)
+		}
+	case *ChanTypeExpr:
+		if ct, ok := baseOf(at).(*ChanType); ok {
+			unify(px.Value, ct.Elt, tparams, bound)
+		}
+	case *FuncTypeExpr:
+		if ft, ok := baseOf(at).(*FuncType); ok {
+			if len(px.Params) == len(ft.Params) &&
+				len(px.Results) == len(ft.Results) {
+				for i := range px.Params {
+					unify(px.Params[i].Type, ft.Params[i].Type, tparams, bound)
+				}
+				for i := range px.Results {
+					unify(px.Results[i].Type, ft.Results[i].Type, tparams, bound)
+				}
+			}
+		}
+	}
+}
+
+// ----------------------------------------
+// Node helpers
+
+// Like n.Copy(), but also copies the line numbers, labels, and iota
+// attributes, which are needed to preprocess the copy.
+func copyWithLines(n Node) Node {
+	n2 := n.Copy()
+	var srcs []Node
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			srcs = append(srcs, n)
+		}
+		return n, TRANS_CONTINUE
+	})
+	i := 0
+	Transcribe(n2, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			if i >= len(srcs) {
+				panic("should not happen")
+			}
+			src := srcs[i]
+			if reflect.TypeOf(src) != reflect.TypeOf(n) {
+				panic("should not happen")
+			}
+			n.SetLine(src.GetLine())
+			n.SetLabel(src.GetLabel())
+			if iota := src.GetAttribute(ATTR_IOTA); iota != nil {
+				n.SetAttribute(ATTR_IOTA, iota)
+			}
+			i++
+		}
+		return n, TRANS_CONTINUE
+	})
+	if i != len(srcs) {
+		panic("should not happen")
+	}
+	return n2
+}
+
+// Replaces the names of tparams in n with their type arguments.
+func substTypeParams(n Node, tparams FieldTypeExprs, targs []Type) Node {
+	m := make(map[Name]Type, len(tparams))
+	for i, tp := range tparams {
+		if tp.Name != "_" {
+			m[tp.Name] = targs[i]
+		}
+	}
+	return Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER || ftype == TRANS_COMPOSITE_KEY {
+			return n, TRANS_CONTINUE
+		}
+		if nx, ok := n.(*NameExpr); ok {
+			if t, ok := m[nx.Name]; ok {
+				return constType(nx, t), TRANS_SKIP
+			}
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+// Predefines any package declarations named in the instance n that are
+// not yet, as n may be instantiated before them. Names declared in n,
+// e.g. its receiver, shadow the package declarations.
+func predefineDependencies(store Store, fn *FileNode, n Node) {
+	pn := packageOf(fn)
+	if pn.FileSet == nil {
+		return
+	}
+	locals := localNames(n)
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if nx, ok := n.(*NameExpr); ok {
+			if _, ok := fn.GetLocalIndex(nx.Name); ok {
+				return n, TRANS_CONTINUE // import
+			}
+			if _, ok := locals[nx.Name]; ok {
+				return n, TRANS_CONTINUE
+			}
+			dfn, decl, ok := pn.FileSet.GetDeclForSafe(nx.Name)
+			if ok && (*decl).GetAttribute(ATTR_PREDEFINED) != true {
+				*decl, _ = predefineNow(store, dfn, *decl)
+			}
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+// Returns the names declared within n: receivers, parameters and results
+// of functions, and local variables, constants and types. Their scopes
+// are not tracked, which is only imprecise for a name that is both
+// declared and used as a package declaration within n.
+func localNames(n Node) map[Name]struct{} {
+	names := make(map[Name]struct{})
+	addFields := func(ftxs FieldTypeExprs) {
+		for _, ftx := range ftxs {
+			names[ftx.Name] = struct{}{}
+		}
+	}
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		switch cn := n.(type) {
+		case *FuncDecl:
+			if cn.IsMethod {
+				names[cn.Recv.Name] = struct{}{}
+			}
+			addFields(cn.Type.Params)
+			addFields(cn.Type.Results)
+		case *FuncLitExpr:
+			addFields(cn.Type.Params)
+			addFields(cn.Type.Results)
+		case *AssignStmt:
+			if cn.Op == DEFINE {
+				for _, lx := range cn.Lhs {
+					if nx, ok := lx.(*NameExpr); ok {
+						names[nx.Name] = struct{}{}
+					}
+				}
+			}
+		case *RangeStmt:
+			if cn.Op == DEFINE {
+				for _, x := range []Expr{cn.Key, cn.Value} {
+					if nx, ok := x.(*NameExpr); ok {
+						names[nx.Name] = struct{}{}
+					}
+				}
+			}
+		case *SwitchStmt:
+			if cn.VarName != "" {
+				names[cn.VarName] = struct{}{}
+			}
+		case *DeclStmt:
+			for _, d := range cn.Body {
+				for _, dn := range d.(Decl).GetDeclNames() {
+					names[dn] = struct{}{}
+				}
+			}
+		}
+		return n, TRANS_CONTINUE
+	})
+	delete(names, "")
+	return names
+}
+
+// Saves the block nodes of instance n to the store, for their
+// functions to be found by location.
+func saveInstanceNodes(store Store, n Node) {
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if bn, ok := n.(BlockNode); ok {
+			store.SetBlockNode(bn)
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+// Panics unless the generic gt is used in an instantiation, or a call
+// where its type arguments are inferred, as given by ftype.
+func checkGenericUse(ftype TransField, gt *genericType) {
+	switch ftype {
+	case TRANS_INDEX_X, TRANS_CALL_FUNC:
+		return
+	}
+	if _, ok := gt.Decl.(*TypeDecl); ok {
+		panic(fmt.Sprintf(
+			"cannot use generic type %s without instantiation",
+			gt.Name()))
+	}
+	panic(fmt.Sprintf(
+		"cannot use generic function %s without instantiation",
+		gt.Name()))
+}
+
+// ----------------------------------------
+// Type sets
+
+// Moves the type elements of the constraint interface x, as in
+// `interface{ ~int | ~string }`, from its methods to its terms.
+func preprocessTypeTerms(store Store, last BlockNode, x *InterfaceTypeExpr) {
+	methods := make([]FieldTypeExpr, 0, len(x.Methods))
+	for _, m := range x.Methods {
+		if m.Name != "" {
+			methods = append(methods, m)
+			continue
+		}
+		var terms []TypeTerm
+		if isTypeTermExpr(m.Type) {
+			terms = evalTypeTerms(store, last, m.Type)
+		} else {
+			m.Type = Preprocess(store, last, m.Type).(Expr)
+			t := evalStaticType(store, last, m.Type)
+			if t.Kind() == InterfaceKind {
+				// embedded interface.
+				methods = append(methods, m)
+				continue
+			}
+			terms = []TypeTerm{{Type: t}}
+		}
+		if x.Terms != nil {
+			panic("multiple type set elements in interface not yet supported")
+		}
+		x.Terms = terms
+	}
+	x.Methods = methods
+}
+
+func isTypeTermExpr(x Expr) bool {
+	switch x := x.(type) {
+	case *BinaryExpr:
+		return x.Op == BOR
+	case *UnaryExpr:
+		return x.Op == TILDE
+	default:
+		return false
+	}
+}
+
+// Evaluates the union x of type terms.
+func evalTypeTerms(store Store, last BlockNode, x Expr) []TypeTerm {
+	switch x := x.(type) {
+	case *BinaryExpr:
+		if x.Op == BOR {
+			return append(
+				evalTypeTerms(store, last, x.Left),
+				evalTypeTerms(store, last, x.Right)...)
+		}
+	case *UnaryExpr:
+		if x.Op == TILDE {
+			tx := Preprocess(store, last, x.X).(Expr)
+			t := evalStaticType(store, last, tx)
+			if t.Kind() == InterfaceKind {
+				panic(fmt.Sprintf(
+					"invalid use of ~ (%s is an interface)",
+					t.String()))
+			}
+			return []TypeTerm{{Tilde: true, Type: t}}
+		}
+	}
+	tx := Preprocess(store, last, x).(Expr)
+	t := evalStaticType(store, last, tx)
+	if it, ok := baseOf(t).(*InterfaceType); ok {
+		if len(it.Terms) == 0 {
+			panic(fmt.Sprintf(
+				"cannot use %s in union",
+				t.String()))
+		}
+		// union with constraint, e.g. `Integer | Float`.
+		return it.Terms
+	}
+	return []TypeTerm{{Type: t}}
+}
@@ -47,6 +47,41 @@ func next(i int) int {
 	fmt.Println(res)
 }
 
+// a variable named x is not shadowed by the result of the wrapping function.
+func TestEvalName(t *testing.T) {
+	m := NewMachine("test", nil)
+	c := `package test
+var x = 7`
+	n := MustParseFile("main.go", c)
+	m.RunFiles(n)
+	res := m.Eval(X("x"))
+	assert.Equal(t, "(7 int)", res[0].String())
+}
+
+// goroutines left when main or Eval returns never resume later.
+func TestEvalGoroutinesDropped(t *testing.T) {
+	m := NewMachine("test", nil)
+	c := `package test
+var n int
+func main() {
+	go func() { n += 10 }()
+}
+func Start() {
+	go func() { n++ }()
+}
+func Sync() int {
+	c := make(chan int)
+	go func() { c <- n }()
+	return <-c
+}`
+	n := MustParseFile("main.go", c)
+	m.RunFiles(n)
+	m.RunMain()
+	m.Eval(Call("Start"))
+	res := m.Eval(Call("Sync"))
+	assert.Equal(t, "(0 int)", res[0].String())
+}
+
 func assertOutput(t *testing.T, input string, output string) {
 	t.Helper()
 
@@ -173,6 +173,11 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 			X:     toExpr(fs, gon.X),
 			Index: toExpr(fs, gon.Index),
 		}
+	case *ast.IndexListExpr:
+		return &IndexListExpr{
+			X:       toExpr(fs, gon.X),
+			Indices: toExprs(fs, gon.Indices),
+		}
 	case *ast.SelectorExpr:
 		return &SelectorExpr{
 			X:   toExpr(fs, gon.X),
@@ -318,6 +323,20 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 		return &DeferStmt{
 			Call: *cx,
 		}
+	case *ast.GoStmt:
+		cx := toExpr(fs, gon.Call).(*CallExpr)
+		return &GoStmt{
+			Call: *cx,
+		}
+	case *ast.SendStmt:
+		return &SendStmt{
+			Chan:  toExpr(fs, gon.Chan),
+			Value: toExpr(fs, gon.Value),
+		}
+	case *ast.SelectStmt:
+		return &SelectStmt{
+			Cases: toSelectCases(fs, gon.Body.List),
+		}
 	case *ast.ExprStmt:
 		if cx, ok := gon.X.(*ast.CallExpr); ok {
 			if ix, ok := cx.Fun.(*ast.Ident); ok && ix.Name == "panic" {
@@ -423,21 +442,26 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 	case *ast.FuncDecl:
 		isMethod := gon.Recv != nil
 		recv := FieldTypeExpr{}
+		var tparams []FieldTypeExpr
 		if isMethod {
 			if len(gon.Recv.List) > 1 {
 				panic("*ast.FuncDecl cannot have multiple receivers")
 			}
 			recv = *Go2Gno(fs, gon.Recv.List[0]).(*FieldTypeExpr)
+			tparams = toRecvTypeParams(gon.Recv.List[0].Type)
+		} else {
+			tparams = toFieldsFromList(fs, gon.Type.TypeParams)
 		}
 		name := toName(gon.Name)
 		type_ := Go2Gno(fs, gon.Type).(*FuncTypeExpr)
 		body := Go2Gno(fs, gon.Body).(*BlockStmt).Body
 		return &FuncDecl{
-			IsMethod: isMethod,
-			Recv:     recv,
-			NameExpr: NameExpr{Name: name},
-			Type:     *type_,
-			Body:     body,
+			IsMethod:   isMethod,
+			Recv:       recv,
+			NameExpr:   NameExpr{Name: name},
+			TypeParams: tparams,
+			Type:       *type_,
+			Body:       body,
 		}
 	case *ast.GenDecl:
 		panic("unexpected *ast.GenDecl; use toDecls(fs,) instead")
@@ -519,6 +543,7 @@ var token2word = map[token.Token]Word{
 	token.LEQ:            LEQ,
 	token.GEQ:            GEQ,
 	token.DEFINE:         DEFINE,
+	token.TILDE:          TILDE,
 	token.BREAK:          BREAK,
 	token.CASE:           CASE,
 	token.CHAN:           CHAN,
@@ -623,12 +648,14 @@ func toDecls(fs *token.FileSet, gd *ast.GenDecl) (ds Decls) {
 		switch s := s.(type) {
 		case *ast.TypeSpec:
 			name := toName(s.Name)
+			tparams := toFieldsFromList(fs, s.TypeParams)
 			tipe := toExpr(fs, s.Type)
 			alias := s.Assign != 0
 			ds = append(ds, &TypeDecl{
-				NameExpr: NameExpr{Name: name},
-				Type:     tipe,
-				IsAlias:  alias,
+				NameExpr:   NameExpr{Name: name},
+				TypeParams: tparams,
+				Type:       tipe,
+				IsAlias:    alias,
 			})
 		case *ast.ValueSpec:
 			if gd.Tok == token.CONST {
@@ -740,6 +767,36 @@ func toFields(fs *token.FileSet, fields ...*ast.Field) (ftxs []FieldTypeExpr) {
 	return
 }
 
+// Returns the type parameters of a method of a generic type, as named
+// in its receiver type, e.g. K and V for `func (m *Map[K, V]) Get()`.
+// Their constraints are those of the generic type, so they are any here.
+func toRecvTypeParams(rt ast.Expr) (ftxs []FieldTypeExpr) {
+	if sx, ok := rt.(*ast.StarExpr); ok {
+		rt = sx.X
+	}
+	var idxs []ast.Expr
+	switch rt := rt.(type) {
+	case *ast.IndexExpr:
+		idxs = []ast.Expr{rt.Index}
+	case *ast.IndexListExpr:
+		idxs = rt.Indices
+	default:
+		return nil
+	}
+	ftxs = make([]FieldTypeExpr, len(idxs))
+	for i, idx := range idxs {
+		id, ok := idx.(*ast.Ident)
+		if !ok {
+			panic("receiver type parameter must be an identifier")
+		}
+		ftxs[i] = FieldTypeExpr{
+			Name: toName(id),
+			Type: Nx("any"),
+		}
+	}
+	return
+}
+
 func toKeyValueExprs(fs *token.FileSet, elts []ast.Expr) (kvxs KeyValueExprs) {
 	kvxs = make([]KeyValueExpr, len(elts))
 	for i, x := range elts {
@@ -779,6 +836,28 @@ func toClauses(fs *token.FileSet, csz []ast.Stmt) []SwitchClauseStmt {
 	return res
 }
 
+// NOTE: unlike switch clauses, the default case is kept in place, as
+// select cases are evaluated in source order.
+func toSelectCases(fs *token.FileSet, ccs []ast.Stmt) []SelectCaseStmt {
+	res := make([]SelectCaseStmt, len(ccs))
+	hasDefault := false
+	for i, cs := range ccs {
+		cc := cs.(*ast.CommClause)
+		if cc.Comm == nil {
+			if hasDefault {
+				panic("duplicate default case in select")
+			}
+			hasDefault = true
+		}
+		res[i] = SelectCaseStmt{
+			Comm: toStmt(fs, cc.Comm),
+			Body: toStmts(fs, cc.Body),
+		}
+		setLoc(fs, cc.Pos(), &res[i])
+	}
+	return res
+}
+
 func toSwitchClauseStmt(fs *token.FileSet, cc *ast.CaseClause) SwitchClauseStmt {
 	return SwitchClauseStmt{
 		Cases: toExprs(fs, cc.List),
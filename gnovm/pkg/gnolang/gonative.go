@@ -1076,7 +1076,7 @@ func gno2GoValue(tv *TypedValue, rv reflect.Value) (ret reflect.Value) {
 		rv = rv2       // swaparoo
 		defer func() { // TODO: improve?
 			rv1.Set(rv2)
-			ret = rv
+			ret = rv1
 		}()
 	} else {
 		ret = rv
@@ -0,0 +1,295 @@
+package gnolang
+
+// Goroutines are run by a cooperative, deterministic scheduler: the
+// running goroutine runs until it exits or blocks on a channel operation,
+// and then the next ready goroutine is resumed, in FIFO order. There is
+// no preemption, so a goroutine that busy-waits starves all others, and a
+// select with several ready cases picks the first one in source order.
+// Given the same program, goroutines are thus always scheduled the same.
+//
+// Goroutines share the machine; the state of a goroutine (its ops,
+// values, exprs, stmts, blocks and frames) is swapped in and out of the
+// machine as it is resumed and parked. As in Go, the program ends when
+// the main goroutine returns, even if other goroutines are still running.
+//
+// NOTE: goroutines are not yet supported in realm packages, as channels
+// and goroutines cannot be persisted.
+
+type Goroutine struct {
+	ID int // 0 for the main goroutine.
+
+	ops        []Op
+	numOps     int
+	values     []TypedValue
+	numValues  int
+	exprs      []Expr
+	stmts      []Stmt
+	blocks     []*Block
+	frames     []Frame
+	pkg        *PackageValue
+	realm      *Realm
+	exception  *TypedValue
+	numResults int
+
+	wait *chanWaiter // set while parked.
+}
+
+type waitKind int
+
+const (
+	waitSend   waitKind = iota // <X> <- <Value>
+	waitRecv                   // <-<X>
+	waitSelect                 // select { ... }
+)
+
+// A chanWaiter is a goroutine parked on channel operations.
+type chanWaiter struct {
+	g     *Goroutine
+	kind  waitKind
+	hasOK bool         // if true, push ok after received value.
+	chans []*ChanValue // channels the waiter is queued on.
+
+	// set when woken.
+	index  int        // index of ready select case.
+	value  TypedValue // value received.
+	ok     bool       // false if received from closed channel.
+	closed bool       // true if sending on closed channel.
+}
+
+// A chanCase is a pending send or receive on a channel.
+type chanCase struct {
+	w     *chanWaiter
+	index int        // select case index.
+	value TypedValue // value to send, if sending.
+}
+
+// Returns the running goroutine, which is the main goroutine until
+// another one is resumed.
+func (m *Machine) currentRoutine() *Goroutine {
+	if m.Routine == nil {
+		m.Routine = &Goroutine{ID: 0}
+	}
+	return m.Routine
+}
+
+// Drops all goroutines but main. Called when main returns, which ends the
+// program, and before each Eval, so that goroutines left by a previous
+// run never resume in a later one.
+func (m *Machine) resetRoutines() {
+	m.Routine = nil
+	m.ReadyQueue = nil
+	m.NumRoutines = 0
+}
+
+// Creates a new goroutine to call ftv with args, and queues it.
+func (m *Machine) startRoutine(cx *CallExpr, ftv TypedValue, args []TypedValue) {
+	m.currentRoutine()
+	m.NumRoutines++
+	g := &Goroutine{
+		ID:     m.NumRoutines,
+		ops:    make([]Op, 64),
+		numOps: 3,
+		values: make([]TypedValue, 64+len(args)),
+		exprs:  []Expr{cx},
+		blocks: []*Block{m.LastBlock()},
+		pkg:    m.Package,
+		realm:  m.Realm,
+	}
+	g.ops[0] = OpHalt
+	g.ops[1] = OpPopResults
+	g.ops[2] = OpPrecall
+	g.values[0] = ftv
+	copy(g.values[1:], args)
+	g.numValues = 1 + len(args)
+	m.ReadyQueue = append(m.ReadyQueue, g)
+}
+
+func (m *Machine) saveRoutine(g *Goroutine) {
+	g.ops, g.numOps = m.Ops, m.NumOps
+	g.values, g.numValues = m.Values, m.NumValues
+	g.exprs = m.Exprs
+	g.stmts = m.Stmts
+	g.blocks = m.Blocks
+	g.frames = m.Frames
+	g.pkg = m.Package
+	g.realm = m.Realm
+	g.exception = m.Exception
+	g.numResults = m.NumResults
+}
+
+func (m *Machine) loadRoutine(g *Goroutine) {
+	m.Ops, m.NumOps = g.ops, g.numOps
+	m.Values, m.NumValues = g.values, g.numValues
+	m.Exprs = g.exprs
+	m.Stmts = g.stmts
+	m.Blocks = g.blocks
+	m.Frames = g.frames
+	m.Package = g.pkg
+	m.Realm = g.realm
+	m.Exception = g.exception
+	m.NumResults = g.numResults
+	m.Routine = g
+}
+
+// Parks the running goroutine until w is woken, and resumes the next.
+func (m *Machine) parkRoutine(w *chanWaiter) {
+	if m.runDepth > 1 {
+		panic("cannot block on channel operation here")
+	}
+	g := m.currentRoutine()
+	w.g = g
+	g.wait = w
+	m.saveRoutine(g)
+	m.resumeNext()
+}
+
+// Called when the running goroutine (other than main) returns.
+func (m *Machine) exitRoutine() {
+	m.resumeNext()
+}
+
+func (m *Machine) resumeNext() {
+	if len(m.ReadyQueue) == 0 {
+		panic("all goroutines are asleep - deadlock!")
+	}
+	g := m.ReadyQueue[0]
+	m.ReadyQueue[0] = nil
+	m.ReadyQueue = m.ReadyQueue[1:]
+	m.loadRoutine(g)
+	if w := g.wait; w != nil {
+		g.wait = nil
+		m.completeWait(w)
+	}
+}
+
+// Completes the channel operation a resumed goroutine was parked on.
+func (m *Machine) completeWait(w *chanWaiter) {
+	switch w.kind {
+	case waitRecv:
+		m.PushValue(w.value)
+		if w.hasOK {
+			m.PushValue(untypedBool(w.ok))
+		}
+	case waitSend:
+		if w.closed {
+			panic("send on closed channel")
+		}
+	case waitSelect:
+		if w.closed {
+			panic("send on closed channel")
+		}
+		ss := m.PeekStmt1().(*SelectStmt)
+		m.execSelectCase(ss, w.index, w.value, w.ok)
+	default:
+		panic("should not happen")
+	}
+}
+
+// Wakes the waiter of c, and dequeues it from all its channels.
+func (m *Machine) wakeWaiter(c *chanCase, value TypedValue, ok bool) {
+	w := c.w
+	w.index = c.index
+	w.value = value
+	w.ok = ok
+	for _, cv := range w.chans {
+		cv.sendq = removeChanCases(cv.sendq, w)
+		cv.recvq = removeChanCases(cv.recvq, w)
+	}
+	w.chans = nil
+	m.ReadyQueue = append(m.ReadyQueue, w.g)
+}
+
+func removeChanCases(cs []*chanCase, w *chanWaiter) []*chanCase {
+	res := cs[:0]
+	for _, c := range cs {
+		if c.w != w {
+			res = append(res, c)
+		}
+	}
+	for i := len(res); i < len(cs); i++ {
+		cs[i] = nil
+	}
+	return res
+}
+
+// Returns true if a send on cv would not block.
+func (cv *ChanValue) canSend() bool {
+	return cv.Closed || len(cv.recvq) > 0 || len(cv.Buffer) < cv.Cap
+}
+
+// Returns true if a receive on cv would not block.
+func (cv *ChanValue) canRecv() bool {
+	return cv.Closed || len(cv.Buffer) > 0 || len(cv.sendq) > 0
+}
+
+// Sends tv on cv unless it would block, and returns true if sent.
+func (m *Machine) chanTrySend(cv *ChanValue, tv TypedValue) bool {
+	if cv.Closed {
+		panic("send on closed channel")
+	}
+	if len(cv.recvq) > 0 {
+		m.wakeWaiter(cv.recvq[0], tv, true)
+		return true
+	}
+	if len(cv.Buffer) < cv.Cap {
+		cv.Buffer = append(cv.Buffer, tv)
+		return true
+	}
+	return false
+}
+
+// Receives from cv unless it would block, and returns true as done if
+// received. If cv is closed and empty, the zero value of elt is received
+// and ok is false.
+func (m *Machine) chanTryRecv(cv *ChanValue, elt Type) (tv TypedValue, ok bool, done bool) {
+	if len(cv.Buffer) > 0 {
+		tv = cv.Buffer[0]
+		copy(cv.Buffer, cv.Buffer[1:])
+		cv.Buffer[len(cv.Buffer)-1] = TypedValue{}
+		cv.Buffer = cv.Buffer[:len(cv.Buffer)-1]
+		// a blocked sender may now fill the buffer.
+		if len(cv.sendq) > 0 {
+			c := cv.sendq[0]
+			cv.Buffer = append(cv.Buffer, c.value)
+			m.wakeWaiter(c, TypedValue{}, true)
+		}
+		return tv, true, true
+	}
+	if len(cv.sendq) > 0 {
+		c := cv.sendq[0]
+		m.wakeWaiter(c, TypedValue{}, true)
+		return c.value, true, true
+	}
+	if cv.Closed {
+		return defaultTypedValue(m.Alloc, elt), false, true
+	}
+	return TypedValue{}, false, false
+}
+
+// Closes the channel tv, waking all its blocked receivers with the zero
+// value, and all its blocked senders, which then panic.
+func (m *Machine) chanClose(tv *TypedValue) {
+	ct, ok := baseOf(tv.T).(*ChanType)
+	if !ok {
+		panic("invalid operation: close of non-channel " + tv.T.String())
+	}
+	if ct.Dir == RECV {
+		panic("invalid operation: cannot close receive-only channel")
+	}
+	if tv.V == nil {
+		panic("close of nil channel")
+	}
+	cv := tv.V.(*ChanValue)
+	if cv.Closed {
+		panic("close of closed channel")
+	}
+	cv.Closed = true
+	for len(cv.recvq) > 0 {
+		m.wakeWaiter(cv.recvq[0], defaultTypedValue(m.Alloc, ct.Elt), false)
+	}
+	for len(cv.sendq) > 0 {
+		c := cv.sendq[0]
+		c.w.closed = true
+		m.wakeWaiter(c, TypedValue{}, false)
+	}
+}
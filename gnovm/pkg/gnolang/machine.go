@@ -13,6 +13,7 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 )
 
 //----------------------------------------
@@ -35,10 +36,20 @@ type Machine struct {
 	NumResults int           // number of results returned
 	Cycles     int64         // number of "cpu" cycles
 
+	// Goroutines, see goroutine.go
+	Routine     *Goroutine   // running goroutine, or nil if main
+	ReadyQueue  []*Goroutine // goroutines ready to resume, in order
+	NumRoutines int          // number of goroutines started
+	runDepth    int          // number of nested Run() calls
+
 	// Configuration
-	CheckTypes bool // not yet used
-	ReadOnly   bool
-	MaxCycles  int64
+	CheckTypes  bool // not yet used
+	ReadOnly    bool
+	MaxCycles   int64
+	GasMeter    store.GasMeter
+	GasPerCycle int64
+	Coverage    *Coverage // records executed statements, if set.
+	Debugger    *Debugger // stops the execution, if set.
 
 	Output  io.Writer
 	Store   Store
@@ -68,9 +79,13 @@ type MachineOptions struct {
 	Output        io.Writer
 	Store         Store
 	Context       interface{}
-	Alloc         *Allocator // or see MaxAllocBytes.
-	MaxAllocBytes int64      // or 0 for no limit.
-	MaxCycles     int64      // or 0 for no limit.
+	Alloc         *Allocator     // or see MaxAllocBytes.
+	MaxAllocBytes int64          // or 0 for no limit.
+	MaxCycles     int64          // or 0 for no limit.
+	GasMeter      store.GasMeter // or nil for no gas accounting.
+	GasPerCycle   int64          // gas consumed per cpu cycle, if GasMeter.
+	Coverage      *Coverage      // or nil for no coverage.
+	Debugger      *Debugger      // or nil for no debugging.
 }
 
 // the machine constructor gets spammed
@@ -90,6 +105,8 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 	checkTypes := opts.CheckTypes
 	readOnly := opts.ReadOnly
 	maxCycles := opts.MaxCycles
+	gasMeter := opts.GasMeter
+	gasPerCycle := opts.GasPerCycle
 	output := opts.Output
 	if output == nil {
 		output = os.Stdout
@@ -117,18 +134,22 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 	context := opts.Context
 	mm := machinePool.Get().(*Machine)
 	*mm = Machine{
-		Ops:        mm.Ops,
-		NumOps:     0,
-		Values:     mm.Values,
-		NumValues:  0,
-		Package:    pv,
-		Alloc:      alloc,
-		CheckTypes: checkTypes,
-		ReadOnly:   readOnly,
-		MaxCycles:  maxCycles,
-		Output:     output,
-		Store:      store,
-		Context:    context,
+		Ops:         mm.Ops,
+		NumOps:      0,
+		Values:      mm.Values,
+		NumValues:   0,
+		Package:     pv,
+		Alloc:       alloc,
+		CheckTypes:  checkTypes,
+		ReadOnly:    readOnly,
+		MaxCycles:   maxCycles,
+		GasMeter:    gasMeter,
+		GasPerCycle: gasPerCycle,
+		Coverage:    opts.Coverage,
+		Debugger:    opts.Debugger,
+		Output:      output,
+		Store:       store,
+		Context:     context,
 	}
 
 	if pv != nil {
@@ -326,6 +347,11 @@ func (m *Machine) TestMemPackage(t *testing.T, memPkg *std.MemPackage) {
 // in case of panic, inject location information to exception.
 func (m *Machine) injectLocOnPanic() {
 	if r := recover(); r != nil {
+		// Out of gas is not a program error; let the caller
+		// handle it as is.
+		if _, ok := r.(store.OutOfGasException); ok {
+			panic(r)
+		}
 		// Show last location information.
 		// First, determine the line number of expression or statement if any.
 		lastLine := 0
@@ -443,6 +469,10 @@ func (m *Machine) runFiles(fns ...*FileNode) {
 	// recursive function for var declarations.
 	var runDeclarationFor func(fn *FileNode, decl Decl)
 	runDeclarationFor = func(fn *FileNode, decl Decl) {
+		// generic decls are declared by their instances.
+		if isGenericDecl(decl) {
+			return
+		}
 		// get fileblock of fn.
 		// fb := pv.GetFileBlock(nil, fn.Name)
 		// get dependencies of decl.
@@ -574,6 +604,7 @@ func (m *Machine) RunMain() {
 		}
 	}()
 	m.RunStatement(S(Call(X("main"))))
+	m.resetRoutines()
 }
 
 // Evaluate throwaway expression in new block scope.
@@ -598,7 +629,7 @@ func (m *Machine) Eval(x Expr) []TypedValue {
 	// doesn't get modified.
 	// XXX Just use a BlockStmt?
 	if _, ok := x.(*CallExpr); !ok {
-		x = Call(Fn(nil, Flds("x", InterfaceT(nil)),
+		x = Call(Fn(nil, Flds("", InterfaceT(nil)),
 			Ss(
 				Return(x),
 			)))
@@ -608,6 +639,7 @@ func (m *Machine) Eval(x Expr) []TypedValue {
 	// Preprocess x.
 	x = Preprocess(m.Store, last, x).(Expr)
 	// Evaluate x.
+	m.resetRoutines()
 	start := m.NumValues
 	m.PushOp(OpHalt)
 	m.PushExpr(x)
@@ -844,6 +876,7 @@ const (
 	OpDefine      Op = 0x8C // X... := Y...
 	OpInc         Op = 0x8D // X++
 	OpDec         Op = 0x8E // X--
+	OpSend        Op = 0x8F // X <- Y
 
 	/* Decl operators */
 	OpValueDecl Op = 0x90 // var/const ...
@@ -858,12 +891,17 @@ const (
 	OpRangeIterMap      Op = 0xD5
 	OpRangeIterArrayPtr Op = 0xD6
 	OpReturnCallDefers  Op = 0xD7 // TODO rename?
+	OpRangeIterChan     Op = 0xD8
 )
 
 //----------------------------------------
 // "CPU" steps.
 
 func (m *Machine) incrCPU(cycles int64) {
+	if m.GasMeter != nil {
+		// panics with store.OutOfGasException if out of gas.
+		m.GasMeter.ConsumeGas(cycles*m.GasPerCycle, "CPUCycles")
+	}
 	m.Cycles += cycles
 	if m.MaxCycles != 0 && m.Cycles > m.MaxCycles {
 		panic("CPU cycle overrun")
@@ -978,6 +1016,7 @@ const (
 	OpCPUDefine      = 1
 	OpCPUInc         = 1
 	OpCPUDec         = 1
+	OpCPUSend        = 1
 
 	/* Decl operators */
 	OpCPUValueDecl = 1
@@ -992,12 +1031,15 @@ const (
 	OpCPURangeIterMap      = 1
 	OpCPURangeIterArrayPtr = 1
 	OpCPUReturnCallDefers  = 1
+	OpCPURangeIterChan     = 1
 )
 
 //----------------------------------------
 // main run loop.
 
 func (m *Machine) Run() {
+	m.runDepth++
+	defer func() { m.runDepth-- }()
 	for {
 		op := m.PopOp()
 		// TODO: this can be optimized manually, even into tiers.
@@ -1005,6 +1047,11 @@ func (m *Machine) Run() {
 		/* Control operators */
 		case OpHalt:
 			m.incrCPU(OpCPUHalt)
+			if m.NumOps == 0 && m.Routine != nil && m.Routine.ID != 0 {
+				// goroutine returned.
+				m.exitRoutine()
+				continue
+			}
 			return
 		case OpNoop:
 			m.incrCPU(OpCPUNoop)
@@ -1044,10 +1091,10 @@ func (m *Machine) Run() {
 			m.doOpCallDeferNativeBody()
 		case OpGo:
 			m.incrCPU(OpCPUGo)
-			panic("not yet implemented")
+			m.doOpGo()
 		case OpSelect:
 			m.incrCPU(OpCPUSelect)
-			panic("not yet implemented")
+			m.doOpSelect()
 		case OpSwitchClause:
 			m.incrCPU(OpCPUSwitchClause)
 			m.doOpSwitchClause()
@@ -1291,6 +1338,9 @@ func (m *Machine) Run() {
 		case OpDec:
 			m.incrCPU(OpCPUDec)
 			m.doOpDec()
+		case OpSend:
+			m.incrCPU(OpCPUSend)
+			m.doOpSend()
 		/* Decl operators */
 		case OpValueDecl:
 			m.incrCPU(OpCPUValueDecl)
@@ -1317,6 +1367,9 @@ func (m *Machine) Run() {
 		case OpRangeIterMap:
 			m.incrCPU(OpCPURangeIterMap)
 			m.doOpExec(op)
+		case OpRangeIterChan:
+			m.incrCPU(OpCPURangeIterChan)
+			m.doOpExec(op)
 		case OpReturnCallDefers:
 			m.incrCPU(OpCPUReturnCallDefers)
 			m.doOpReturnCallDefers()
@@ -45,6 +45,7 @@ var wordTokenStrings = map[Word]string{
 	LEQ:             "<=",
 	GEQ:             ">=",
 	DEFINE:          ":=",
+	TILDE:           "~",
 
 	// Branch operations
 	BREAK:       "break",
@@ -122,6 +123,10 @@ func (x IndexExpr) String() string {
 	return fmt.Sprintf("%s[%s]", x.X, x.Index)
 }
 
+func (x IndexListExpr) String() string {
+	return fmt.Sprintf("%s[%s]", x.X, x.Indices.String())
+}
+
 func (x SelectorExpr) String() string {
 	// NOTE: for debugging selector issues:
 	// return fmt.Sprintf("%s.(%v).%s", n.X, n.Path.Type, n.Sel)
@@ -368,6 +373,9 @@ func (x SelectStmt) String() string {
 }
 
 func (x SelectCaseStmt) String() string {
+	if x.Comm == nil {
+		return fmt.Sprintf("default: %s", x.Body.String())
+	}
 	return fmt.Sprintf("case %v: %s", x.Comm.String(), x.Body.String())
 }
 
@@ -408,8 +416,12 @@ func (x FuncDecl) String() string {
 	if x.IsMethod {
 		recv = "(" + x.Recv.String() + ") "
 	}
-	return fmt.Sprintf("func %s%s%s { %s }",
-		recv, x.Name, x.Type.String()[4:], x.Body.String())
+	tparams := ""
+	if !x.IsMethod && 0 < len(x.TypeParams) {
+		tparams = "[" + x.TypeParams.String() + "]"
+	}
+	return fmt.Sprintf("func %s%s%s%s { %s }",
+		recv, x.Name, tparams, x.Type.String()[4:], x.Body.String())
 }
 
 func (x ImportDecl) String() string {
@@ -440,6 +452,10 @@ func (x TypeDecl) String() string {
 	if x.IsAlias {
 		return fmt.Sprintf("type %s = %s", x.Name, x.Type.String())
 	}
+	if 0 < len(x.TypeParams) {
+		return fmt.Sprintf("type %s[%s] %s",
+			x.Name, x.TypeParams.String(), x.Type.String())
+	}
 	return fmt.Sprintf("type %s %s", x.Name, x.Type.String())
 }
 
@@ -344,6 +344,10 @@ func isEql(store Store, lv, rv *TypedValue) bool {
 	} else if rvu {
 		return false
 	}
+	// Interface values of different dynamic types are not equal.
+	if lv.T != rv.T && lv.T.TypeID() != rv.T.TypeID() {
+		return false
+	}
 	if lnt, ok := lv.T.(*NativeType); ok {
 		if rnt, ok := rv.T.(*NativeType); ok {
 			if lnt.Type != rnt.Type {
@@ -443,6 +447,8 @@ func isEql(store Store, lv, rv *TypedValue) bool {
 			}
 		}
 		return lv.V == rv.V
+	case ChanKind:
+		return lv.V == rv.V
 	case SliceKind:
 		if debug {
 			if lv.V != nil && rv.V != nil {
@@ -476,6 +482,12 @@ func isEql(store Store, lv, rv *TypedValue) bool {
 		}
 	case PointerKind:
 		// TODO: assumes runtime instance normalization.
+		if lpv, ok := lv.V.(PointerValue); ok && lpv.TV.T == DataByteType {
+			// pointers to data bytes are allocated on the fly.
+			rpv, ok := rv.V.(PointerValue)
+			return ok && rpv.TV.T == DataByteType &&
+				lpv.Base == rpv.Base && lpv.Index == rpv.Index
+		}
 		return lv.V == rv.V
 	default:
 		panic(fmt.Sprintf(
@@ -269,7 +269,10 @@ func (m *Machine) doOpReturnCallDefers() {
 		pts := ft.Params
 		numParams := len(ft.Params)
 		// Create new block scope for defer.
-		b := m.Alloc.NewBlock(fv.GetSource(m.Store), dfr.Parent)
+		// NOTE: like doOpCall(), the parent is the closure of the func,
+		// e.g. for defer f()() it isn't the block which deferred it.
+		clo := fv.GetClosure(m.Store)
+		b := m.Alloc.NewBlock(fv.GetSource(m.Store), clo)
 		m.PushBlock(b)
 		if fv.nativeBody == nil {
 			fbody := fv.GetBodyFromSource(m.Store)
@@ -385,6 +388,22 @@ func (m *Machine) doOpDefer() {
 	}
 }
 
+func (m *Machine) doOpGo() {
+	if m.Realm != nil {
+		panic("goroutines are not yet supported in realm packages")
+	}
+	gs := m.PopStmt().(*GoStmt)
+	// Pop arguments
+	args := m.PopCopyValues(gs.Call.NumArgs)
+	// Pop func
+	ftv := *m.PopValue()
+	if ftv.V == nil {
+		panic("go of nil func value")
+	}
+	// Queue goroutine.
+	m.startRoutine(&gs.Call, ftv, args)
+}
+
 func (m *Machine) doOpPanic1() {
 	// Pop exception
 	var ex TypedValue = m.PopValue().Copy(m.Alloc)
@@ -57,7 +57,8 @@ func (m *Machine) doOpEval() {
 				case 'x', 'X':
 					_, ok = bi.SetString(x.Value[2:], 16)
 				default:
-					ok = false
+					// e.g. 0660, the legacy octal form.
+					_, ok = bi.SetString(x.Value[1:], 8)
 				}
 				if !ok {
 					panic(fmt.Sprintf(
@@ -39,7 +39,11 @@ SwitchStmt -> +block
   OpTypeSwitch
 
 SelectStmt ->
-  OpSelectCase +block
+  OpSelect -> +block
+    OpBody
+
+SendStmt ->
+  OpSend
 
 */
 
@@ -425,12 +429,92 @@ func (m *Machine) doOpExec(op Op) {
 				panic("should not happen")
 			}
 		}
+	case OpRangeIterChan:
+		bs := s.(*bodyStmt)
+		switch bs.NextBodyIndex {
+		case -2: // init.
+			bs.NumOps = m.NumOps
+			bs.NumValues = m.NumValues
+			bs.NumExprs = len(m.Exprs)
+			bs.NumStmts = len(m.Stmts)
+			bs.NextBodyIndex++
+			fallthrough
+		case -1: // receive next element.
+			xv := &m.Values[bs.NumValues-1]
+			if xv.V == nil {
+				// receive on nil channel blocks forever.
+				bs.NextBodyIndex = -3
+				m.parkRoutine(&chanWaiter{kind: waitRecv, hasOK: true})
+				return
+			}
+			cv := xv.V.(*ChanValue)
+			elt := baseOf(xv.T).(*ChanType).Elt
+			tv, ok, done := m.chanTryRecv(cv, elt)
+			if !done {
+				// resume at -3 with received element.
+				bs.NextBodyIndex = -3
+				w := &chanWaiter{kind: waitRecv, hasOK: true, chans: []*ChanValue{cv}}
+				cv.recvq = append(cv.recvq, &chanCase{w: w})
+				m.parkRoutine(w)
+				return
+			}
+			m.PushValue(tv)
+			m.PushValue(untypedBool(ok))
+			fallthrough
+		case -3: // assign received element.
+			ok := m.PopValue().GetBool()
+			ev := *m.PopValue()
+			if !ok { // channel closed.
+				m.PopFrameAndReset()
+				return
+			}
+			if bs.Key != nil {
+				switch bs.Op {
+				case ASSIGN:
+					m.PopAsPointer(bs.Key).Assign2(m.Alloc, m.Store, m.Realm, ev, false)
+				case DEFINE:
+					knxp := bs.Key.(*NameExpr).Path
+					ptr := m.LastBlock().GetPointerTo(m.Store, knxp)
+					ptr.TV.Assign(m.Alloc, ev, false)
+				default:
+					panic("should not happen")
+				}
+			}
+			bs.NextBodyIndex = 0
+			fallthrough
+		default:
+			if bs.NextBodyIndex < bs.BodyLen {
+				next := bs.Body[bs.NextBodyIndex]
+				bs.NextBodyIndex++
+				// continue onto exec stmt.
+				bs.Active = next
+				s = next // switch on bs.Active
+				goto EXEC_SWITCH
+			} else if bs.NextBodyIndex == bs.BodyLen {
+				// set up next assign if needed.
+				if bs.Op == ASSIGN && bs.Key != nil {
+					m.PushForPointer(bs.Key)
+				}
+				bs.ListIndex++
+				bs.NextBodyIndex = -1
+				bs.Active = nil
+				return // redo doOpExec:*bodyStmt
+			} else {
+				panic("should not happen")
+			}
+		}
 	}
 
 EXEC_SWITCH:
 	if debug {
 		debug.Printf("EXEC: %v\n", s)
 	}
+	if m.Coverage != nil {
+		m.coverStmt(s)
+	}
+	if m.Debugger != nil {
+		m.debugStmt(s)
+	}
 	switch cs := s.(type) {
 	case *AssignStmt:
 		switch cs.Op {
@@ -596,6 +680,8 @@ EXEC_SWITCH:
 			m.PushOp(OpRangeIterString)
 		} else if cs.IsArrayPtr {
 			m.PushOp(OpRangeIterArrayPtr)
+		} else if cs.IsChan {
+			m.PushOp(OpRangeIterChan)
 		} else {
 			m.PushOp(OpRangeIter)
 		}
@@ -628,7 +714,7 @@ EXEC_SWITCH:
 			for {
 				fr := m.LastFrame()
 				switch fr.Source.(type) {
-				case *ForStmt, *RangeStmt, *SwitchStmt:
+				case *ForStmt, *RangeStmt, *SwitchStmt, *SelectStmt:
 					if cs.Label != "" && cs.Label != fr.Label {
 						m.PopFrame()
 					} else {
@@ -731,6 +817,31 @@ EXEC_SWITCH:
 		m.PushOp(OpTypeDecl)
 		m.PushExpr(cs.Type)
 		m.PushOp(OpEval)
+	case *GoStmt:
+		m.PushOp(OpGo)
+		// evaluate args
+		args := cs.Call.Args
+		for i := len(args) - 1; 0 <= i; i-- {
+			m.PushExpr(args[i])
+			m.PushOp(OpEval)
+		}
+		// evaluate func
+		m.PushExpr(cs.Call.Func)
+		m.PushOp(OpEval)
+	case *SendStmt:
+		m.PushOp(OpSend)
+		// evaluate value
+		m.PushExpr(cs.Value)
+		m.PushOp(OpEval)
+		// evaluate chan
+		m.PushExpr(cs.Chan)
+		m.PushOp(OpEval)
+	case *SelectStmt:
+		m.PushFrameBasic(cs)
+		m.PushOp(OpPopFrameAndReset)
+		m.PushOp(OpSelect)
+		// push select case index 0
+		m.PushValue(typedInt(0))
 	case *DeferStmt:
 		m.PushOp(OpDefer)
 		// evaluate args
@@ -1001,3 +1112,178 @@ func (m *Machine) doOpSwitchClauseCase() {
 		}
 	}
 }
+
+// selectComm is the evaluated communication of a select case.
+type selectComm struct {
+	cv    *ChanValue // nil for nil channels and the default case.
+	elt   Type       // channel element type.
+	send  bool       // true if sending value.
+	value TypedValue // value to send.
+}
+
+// Returns the channel and value expressions of a send case, or the
+// channel expression of a receive case.
+func selectCaseExprs(sc *SelectCaseStmt) (cx Expr, vx Expr) {
+	var rx Expr
+	switch comm := sc.Comm.(type) {
+	case *SendStmt:
+		return comm.Chan, comm.Value
+	case *ExprStmt:
+		rx = comm.X
+	case *AssignStmt:
+		rx = comm.Rhs[0]
+	default:
+		panic(fmt.Sprintf(
+			"unexpected select case %s",
+			sc.Comm.String()))
+	}
+	ux, ok := rx.(*UnaryExpr)
+	if !ok || ux.Op != ARROW {
+		panic(fmt.Sprintf(
+			"select case must be receive, send or assign recv: %s",
+			sc.Comm.String()))
+	}
+	return ux.X, nil
+}
+
+func (m *Machine) doOpSelect() {
+	ss := m.PeekStmt1().(*SelectStmt)
+	fr := m.LastFrame()
+	sciv := &m.Values[fr.NumValues] // select case index (reuse)
+	idx := sciv.GetInt()
+	if idx < len(ss.Cases) && ss.Cases[idx].Comm == nil {
+		idx++ // default case, nothing to evaluate.
+	}
+	if idx < len(ss.Cases) {
+		// evaluate channel (and value) of case, in the case block
+		// where they were preprocessed.
+		sc := &ss.Cases[idx]
+		sciv.SetInt(idx + 1)
+		m.PushOp(OpSelect)
+		m.PushBlock(m.Alloc.NewBlock(sc, m.LastBlock()))
+		m.PushOp(OpPopBlock)
+		cx, vx := selectCaseExprs(sc)
+		if vx != nil {
+			m.PushExpr(vx)
+			m.PushOp(OpEval)
+		}
+		m.PushExpr(cx)
+		m.PushOp(OpEval)
+		return
+	}
+	// all cases evaluated.
+	tvs := m.PopCopyValues(m.NumValues - fr.NumValues - 1)
+	m.PopValue() // pop select case index
+	comms := make([]selectComm, len(ss.Cases))
+	dflt := -1
+	for i := range ss.Cases {
+		sc := &ss.Cases[i]
+		if sc.Comm == nil {
+			dflt = i
+			continue
+		}
+		xv := tvs[0]
+		tvs = tvs[1:]
+		comm := &comms[i]
+		comm.elt = baseOf(xv.T).(*ChanType).Elt
+		if xv.V != nil {
+			comm.cv = xv.V.(*ChanValue)
+		}
+		if _, ok := sc.Comm.(*SendStmt); ok {
+			comm.send = true
+			comm.value = tvs[0].Copy(m.Alloc)
+			tvs = tvs[1:]
+		}
+	}
+	// NOTE: unlike Go, which picks one at random, the first ready case
+	// in source order is selected, for determinism.
+	for i, comm := range comms {
+		if comm.cv == nil {
+			continue
+		}
+		if comm.send && comm.cv.canSend() {
+			m.chanTrySend(comm.cv, comm.value)
+			m.execSelectCase(ss, i, TypedValue{}, false)
+			return
+		}
+		if !comm.send && comm.cv.canRecv() {
+			tv, ok, _ := m.chanTryRecv(comm.cv, comm.elt)
+			m.execSelectCase(ss, i, tv, ok)
+			return
+		}
+	}
+	if dflt >= 0 {
+		m.execSelectCase(ss, dflt, TypedValue{}, false)
+		return
+	}
+	// no case is ready: block on all (non-nil) channels.
+	w := &chanWaiter{kind: waitSelect}
+	for i, comm := range comms {
+		if comm.cv == nil {
+			continue
+		}
+		c := &chanCase{w: w, index: i, value: comm.value}
+		if comm.send {
+			comm.cv.sendq = append(comm.cv.sendq, c)
+		} else {
+			comm.cv.recvq = append(comm.cv.recvq, c)
+		}
+		w.chans = append(w.chans, comm.cv)
+	}
+	m.parkRoutine(w)
+}
+
+// Executes the selected case idx of ss, given the value (and ok) received
+// if it is a receive case.
+func (m *Machine) execSelectCase(ss *SelectStmt, idx int, tv TypedValue, ok bool) {
+	m.PopStmt() // pop select stmt
+	sc := &ss.Cases[idx]
+	b := m.Alloc.NewBlock(sc, m.LastBlock())
+	b.bodyStmt = bodyStmt{
+		Body:          sc.Body,
+		BodyLen:       len(sc.Body),
+		NextBodyIndex: -2,
+	}
+	m.PushBlock(b)
+	m.PushOp(OpPopBlock)
+	m.PushOp(OpBody)
+	m.PushStmt(b.GetBodyStmt())
+	// define or assign received value (and ok).
+	if as, isAssign := sc.Comm.(*AssignStmt); isAssign {
+		m.PushStmt(as)
+		if as.Op == DEFINE {
+			m.PushOp(OpDefine)
+		} else {
+			m.PushOp(OpAssign)
+		}
+		if len(as.Lhs) == 2 {
+			m.PushExpr(&ConstExpr{TypedValue: untypedBool(ok)})
+			m.PushOp(OpEval)
+		}
+		m.PushExpr(&ConstExpr{TypedValue: tv})
+		m.PushOp(OpEval)
+		if as.Op != DEFINE {
+			for i := len(as.Lhs) - 1; 0 <= i; i-- {
+				m.PushForPointer(as.Lhs[i])
+			}
+		}
+	}
+}
+
+func (m *Machine) doOpSend() {
+	m.PopStmt()
+	tv := m.PopValue().Copy(m.Alloc)
+	xv := m.PopValue()
+	if xv.V == nil {
+		// send on nil channel blocks forever.
+		m.parkRoutine(&chanWaiter{kind: waitSend})
+		return
+	}
+	cv := xv.V.(*ChanValue)
+	if m.chanTrySend(cv, tv) {
+		return
+	}
+	w := &chanWaiter{kind: waitSend, chans: []*ChanValue{cv}}
+	cv.sendq = append(cv.sendq, &chanCase{w: w, value: tv})
+	m.parkRoutine(w)
+}
@@ -190,8 +190,19 @@ func (m *Machine) doOpRef() {
 			nv.Value = rv2
 		}
 	}
+	elt := xv.TV.T
+	if elt == DataByteType {
+		elt = xv.TV.V.(DataByteValue).ElemType
+	}
+	if t, ok := rx.X.GetAttribute(ATTR_TYPEOF_VALUE).(Type); ok {
+		if _, ok := baseOf(t).(*InterfaceType); ok {
+			// The value of an interface is of its dynamic type,
+			// or nil, but the pointer is to the interface.
+			elt = t
+		}
+	}
 	m.PushValue(TypedValue{
-		T: m.Alloc.NewType(&PointerType{Elt: xv.TV.T}),
+		T: m.Alloc.NewType(&PointerType{Elt: elt}),
 		V: xv,
 	})
 }
@@ -145,6 +145,7 @@ func (m *Machine) doOpInterfaceType() {
 		PkgPath: m.Package.PkgPath,
 		Methods: methods,
 		Generic: x.Generic,
+		Terms:   x.Terms,
 	}
 	m.PushValue(TypedValue{
 		T: gTypeType,
@@ -473,6 +474,9 @@ func (m *Machine) doOpStaticTypeOf() {
 			}))
 		} else if xt.Kind() == StringKind {
 			m.PushValue(asValue(StringType))
+		} else if xt.Kind() == SliceKind {
+			// slicing a slice keeps its (declared) type.
+			m.PushValue(asValue(xt))
 		} else {
 			m.PushValue(asValue(&SliceType{
 				Elt: xt.Elem(),
@@ -508,8 +512,19 @@ func (m *Machine) doOpStaticTypeOf() {
 			m.PushOp(OpEval)
 		}
 	case *UnaryExpr:
-		m.PushExpr(x.X)
-		m.PushOp(OpStaticTypeOf)
+		if x.Op == ARROW {
+			// the type of <-X is the element type of chan X.
+			start := m.NumValues
+			m.PushOp(OpHalt)
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+			m.Run() // XXX replace
+			xt := m.ReapValues(start)[0].GetType()
+			m.PushValue(asValue(baseOf(xt).(*ChanType).Elt))
+		} else {
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+		}
 	case *CompositeLitExpr:
 		m.PushExpr(x.Type)
 		m.PushOp(OpEval)
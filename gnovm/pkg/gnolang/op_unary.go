@@ -121,5 +121,27 @@ func (m *Machine) doOpUxor() {
 }
 
 func (m *Machine) doOpUrecv() {
-	panic("not yet implemented")
+	ux := m.PopExpr().(*UnaryExpr)
+	if debug {
+		debug.Printf("doOpUrecv(%v)\n", ux)
+	}
+	xv := m.PopValue()
+	if xv.V == nil {
+		// receive on nil channel blocks forever.
+		m.parkRoutine(&chanWaiter{kind: waitRecv, hasOK: ux.HasOK})
+		return
+	}
+	cv := xv.V.(*ChanValue)
+	elt := baseOf(xv.T).(*ChanType).Elt
+	tv, ok, done := m.chanTryRecv(cv, elt)
+	if done {
+		m.PushValue(tv)
+		if ux.HasOK {
+			m.PushValue(untypedBool(ok))
+		}
+		return
+	}
+	w := &chanWaiter{kind: waitRecv, hasOK: ux.HasOK, chans: []*ChanValue{cv}}
+	cv.recvq = append(cv.recvq, &chanCase{w: w})
+	m.parkRoutine(w)
 }
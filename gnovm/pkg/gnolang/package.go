@@ -53,6 +53,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	BinaryExpr{},
 	CallExpr{},
 	IndexExpr{},
+	IndexListExpr{},
 	SelectorExpr{},
 	SliceExpr{},
 	StarExpr{},
@@ -118,6 +119,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	&FuncType{},
 	&MapType{},
 	&InterfaceType{},
+	TypeTerm{},
 	&TypeType{},
 	&DeclaredType{},
 	&PackageType{},
@@ -34,6 +34,7 @@ var stdlibWhitelist = []string{
 	"bytes",
 	"compress/gzip",
 	"context",
+	"crypto/ed25519",
 	"crypto/md5",
 	"crypto/sha1",
 	"encoding/json",
@@ -48,6 +49,7 @@ var stdlibWhitelist = []string{
 	"math",
 	"math/big",
 	"math/rand",
+	"reflect",
 	"regexp",
 	"sort",
 	"strconv",
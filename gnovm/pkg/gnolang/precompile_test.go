@@ -40,12 +40,21 @@ func TestPrecompile(t *testing.T) {
 			expectedOutput: "package foo\nimport bar \"github.com/gnolang/gno/gnovm/stdlibs/stdshim\"\nfunc hello() string { _ = bar.Foo\nreturn \"world\"}",
 		}, {
 			name:                      "blacklisted-package",
-			source:                    "package foo\nimport \"reflect\"\nfunc foo() { _ = reflect.ValueOf}",
-			expectedPreprocessorError: errors.New(`import "reflect" is not in the whitelist`),
+			source:                    "package foo\nimport \"os\"\nfunc foo() { _ = os.Exit}",
+			expectedPreprocessorError: errors.New(`import "os" is not in the whitelist`),
 		}, {
 			name:           "whitelisted-package",
 			source:         "package foo\nimport \"regexp\"\nfunc foo() { _ = regexp.MatchString}",
 			expectedOutput: "package foo\nimport \"regexp\"\nfunc foo() { _ = regexp.MatchString}",
+		}, {
+			name:           "whitelisted-crypto-package",
+			source:         "package foo\nimport \"crypto/ed25519\"\nfunc foo() { _ = ed25519.Verify}",
+			expectedOutput: "package foo\nimport \"crypto/ed25519\"\nfunc foo() { _ = ed25519.Verify}",
+		}, {
+			// gno-only packages without a go shim can't be precompiled.
+			name:                      "gno-only-crypto-package",
+			source:                    "package foo\nimport \"crypto/keccak256\"\nfunc foo() { _ = keccak256.Sum256}",
+			expectedPreprocessorError: errors.New(`import "crypto/keccak256" is not in the whitelist`),
 		},
 		// multiple files
 		// syntax error
@@ -215,7 +215,14 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 				// but for testing convenience we allow
 				// importing directly onto the package.
 				// Uverse requires this.
-				if n.GetAttribute(ATTR_PREDEFINED) == true {
+				if isGenericDecl(n) {
+					// generic decls are only preprocessed
+					// when instantiated.
+					if n.GetAttribute(ATTR_PREDEFINED) != true {
+						predefineNow(store, last, n.(Decl))
+					}
+					return n, TRANS_SKIP
+				} else if n.GetAttribute(ATTR_PREDEFINED) == true {
 					// skip declarations already predefined
 					// (e.g. through recursion for a dependent)
 				} else {
@@ -228,6 +235,11 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					}
 				}
 
+			// TRANS_ENTER -----------------------
+			case *InterfaceTypeExpr:
+				// Move type elements of constraints to n.Terms.
+				preprocessTypeTerms(store, last, n)
+
 			// TRANS_ENTER -----------------------
 			case *FuncTypeExpr:
 				for i := range n.Params {
@@ -303,6 +315,18 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					}
 					xt = xt.Elem()
 					n.IsArrayPtr = true
+				case ChanKind:
+					if baseOf(xt).(*ChanType).Dir == SEND {
+						panic(fmt.Sprintf(
+							"invalid operation: range %s receive from send-only channel",
+							n.X.String()))
+					}
+					if n.Value != nil {
+						panic(fmt.Sprintf(
+							"range over %s permits only one iteration variable",
+							n.X.String()))
+					}
+					n.IsChan = true
 				}
 				// key value if define.
 				if n.Op == DEFINE {
@@ -317,6 +341,12 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 							vn := n.Value.(*NameExpr).Name
 							last.Define(vn, anyValue(vt))
 						}
+					} else if xt.Kind() == ChanKind {
+						if n.Key != nil {
+							et := xt.Elem()
+							kn := n.Key.(*NameExpr).Name
+							last.Define(kn, anyValue(et))
+						}
 					} else if xt.Kind() == StringKind {
 						if n.Key != nil {
 							it := IntType
@@ -729,6 +759,10 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 								n.Name))
 						}
 					}
+					// Generics must be instantiated.
+					if gt, ok := nt.(*genericType); ok {
+						checkGenericUse(ftype, gt)
+					}
 				}
 
 			// TRANS_LEAVE -----------------------
@@ -937,6 +971,10 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 
 			// TRANS_LEAVE -----------------------
 			case *CallExpr:
+				// Instantiate generic function, inferring type arguments.
+				if gt := genericOf(store, last, n.Func); gt != nil {
+					n.Func = instantiateCall(store, last, n, gt)
+				}
 				// Func type evaluation.
 				var ft *FuncType
 				ift := evalStaticTypeOf(store, last, n.Func)
@@ -969,8 +1007,19 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 							}
 						}
 						// (const) untyped decimal -> float64.
-						// (const) untyped bigint -> int.
-						convertConst(store, last, arg0, nil)
+						// (const) untyped bigint -> int,
+						// or ct if integer, e.g. uint(1<<64 - 1).
+						switch ct.Kind() {
+						case IntKind, Int8Kind, Int16Kind, Int32Kind, Int64Kind,
+							UintKind, Uint8Kind, Uint16Kind, Uint32Kind, Uint64Kind:
+							if arg0.T == UntypedBigintType {
+								convertConst(store, last, arg0, ct)
+							} else {
+								convertConst(store, last, arg0, nil)
+							}
+						default:
+							convertConst(store, last, arg0, nil)
+						}
 						// evaluate the new expression.
 						cx := evalConst(store, last, n)
 						// Though cx may be undefined if ct is interface,
@@ -979,6 +1028,20 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 						return cx, TRANS_CONTINUE
 					} else {
 						ct := evalStaticType(store, last, n.Func)
+						// e.g. Word(1<<s - 1), the untyped operands
+						// of a non-constant shift take the type ct.
+						if bx, ok := n.Args[0].(*BinaryExpr); ok &&
+							ct.Kind() != InterfaceKind &&
+							isUntyped(evalStaticTypeOf(store, last, bx)) {
+							switch bx.Op {
+							case ADD, SUB, MUL, QUO, REM, BAND, BOR, XOR,
+								BAND_NOT:
+								checkOrConvertType(store, last, &bx.Left, ct, false)
+								checkOrConvertType(store, last, &bx.Right, ct, false)
+							case SHL, SHR:
+								checkOrConvertType(store, last, &bx.Left, ct, false)
+							}
+						}
 						n.SetAttribute(ATTR_TYPEOF_VALUE, ct)
 						return n, TRANS_CONTINUE
 					}
@@ -1153,6 +1216,10 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 			// TRANS_LEAVE -----------------------
 			case *IndexExpr:
 				dt := evalStaticTypeOf(store, last, n.X)
+				if gt, ok := dt.(*genericType); ok {
+					// Instantiate generic with single type argument.
+					return instantiateIndex(store, last, ftype, n, gt), TRANS_CONTINUE
+				}
 				if dt.Kind() == PointerKind {
 					// if a is a pointer to an array,
 					// a[low : high : max] is shorthand
@@ -1175,6 +1242,17 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 						dt.String()))
 				}
 
+			// TRANS_LEAVE -----------------------
+			case *IndexListExpr:
+				// Instantiate generic with type arguments.
+				gt, ok := evalStaticTypeOf(store, last, n.X).(*genericType)
+				if !ok {
+					panic(fmt.Sprintf(
+						"invalid operation: %s is not a generic function or type",
+						n.X.String()))
+				}
+				return instantiateIndex(store, last, ftype, n, gt), TRANS_CONTINUE
+
 			// TRANS_LEAVE -----------------------
 			case *SliceExpr:
 				// Replace const L/H/M with int *ConstExpr,
@@ -1183,6 +1261,12 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 				checkOrConvertIntegerType(store, last, n.High)
 				checkOrConvertIntegerType(store, last, n.Max)
 
+			// TRANS_LEAVE -----------------------
+			case *RefExpr:
+				// Cache the static type of X for doOpRef(),
+				// e.g. &x is a *interface{} even if x holds an int.
+				evalStaticTypeOf(store, last, n.X)
+
 			// TRANS_LEAVE -----------------------
 			case *TypeAssertExpr:
 				if n.Type == nil {
@@ -1218,6 +1302,14 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					cx := evalConst(store, last, n)
 					return cx, TRANS_CONTINUE
 				}
+				if n.Op == ARROW {
+					ct := getChanTypeOf(store, last, n.X)
+					if ct.Dir == SEND {
+						panic(fmt.Sprintf(
+							"invalid operation: cannot receive from send-only channel %s",
+							n.X.String()))
+					}
+				}
 
 			// TRANS_LEAVE -----------------------
 			case *CompositeLitExpr:
@@ -1415,6 +1507,10 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 						cx := evalConst(store, last, n)
 						return cx, TRANS_CONTINUE
 					}
+					// generics must be instantiated.
+					if gt, ok := tt.(*genericType); ok {
+						checkGenericUse(ftype, gt)
+					}
 				case *TypeType:
 					// unbound method
 					xt := evalStaticType(store, last, n.X)
@@ -1548,6 +1644,18 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 							// re-definitions
 							last.Define(lhs0, anyValue(mt.Value))
 							last.Define(lhs1, anyValue(BoolType))
+						case *UnaryExpr:
+							// Receive case: v, ok := <-x, x is chan.
+							if len(n.Lhs) != 2 || cx.Op != ARROW {
+								panic("should not happen")
+							}
+							cx.HasOK = true
+							lhs0 := n.Lhs[0].(*NameExpr).Name
+							lhs1 := n.Lhs[1].(*NameExpr).Name
+							ct := getChanTypeOf(store, last, cx.X)
+							// re-definitions
+							last.Define(lhs0, anyValue(ct.Elt))
+							last.Define(lhs1, anyValue(BoolType))
 						default:
 							panic("should not happen")
 						}
@@ -1597,6 +1705,12 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 								panic("should not happen")
 							}
 							cx.HasOK = true
+						case *UnaryExpr:
+							// Receive case: v, ok = <-x, x is chan.
+							if len(n.Lhs) != 2 || cx.Op != ARROW {
+								panic("should not happen")
+							}
+							cx.HasOK = true
 						default:
 							panic("should not happen")
 						}
@@ -1713,8 +1827,20 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 
 			// TRANS_LEAVE -----------------------
 			case *SendStmt:
-				// Value consts become default *ConstExprs.
-				checkOrConvertType(store, last, &n.Value, nil, false)
+				// Value consts become the channel element type.
+				ct := getChanTypeOf(store, last, n.Chan)
+				if ct.Dir == RECV {
+					panic(fmt.Sprintf(
+						"invalid operation: cannot send to receive-only channel %s",
+						n.Chan.String()))
+				}
+				checkOrConvertType(store, last, &n.Value, ct.Elt, false)
+
+			// TRANS_LEAVE -----------------------
+			case *PanicStmt:
+				// Replace const Exception with default *ConstExpr,
+				// e.g. panic(0xdead) panics with an int.
+				convertIfConst(store, last, n.Exception)
 
 			// TRANS_LEAVE -----------------------
 			case *SelectCaseStmt:
@@ -1882,6 +2008,10 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 				case *StructType:
 					*dst = *(tmp.(*StructType))
 				case *DeclaredType:
+					if n.IsAlias {
+						// alias of declared type, nothing to do.
+						break
+					}
 					// if store has this type, use that.
 					tid := DeclaredTypeID(lastpn.PkgPath, n.Name)
 					exists := false
@@ -2249,6 +2379,18 @@ func lastDecl(ns []Node) Decl {
 	return nil
 }
 
+// getChanTypeOf returns the channel type of x, which must be a channel.
+func getChanTypeOf(store Store, last BlockNode, x Expr) *ChanType {
+	xt := evalStaticTypeOf(store, last, x)
+	ct, ok := baseOf(xt).(*ChanType)
+	if !ok {
+		panic(fmt.Sprintf(
+			"invalid operation: %s (variable of type %s) is not a channel",
+			x.String(), xt.String()))
+	}
+	return ct
+}
+
 func lastSwitch(ns []Node) *SwitchStmt {
 	for i := len(ns) - 1; 0 <= i; i-- {
 		if d, ok := ns[i].(*SwitchStmt); ok {
@@ -2341,10 +2483,34 @@ func checkOrConvertType(store Store, last BlockNode, x *Expr, t Type, autoNative
 			cx := Expr(Call(constType(nil, t), *x))
 			cx = Preprocess(store, last, cx).(Expr)
 			*x = cx
+		} else if isUnnamedAssignable(xt, t) {
+			// e.g. a []Word to a nat, the value must have the
+			// declared type for its methods.
+			cx := Expr(Call(constType(nil, t), *x))
+			cx = Preprocess(store, last, cx).(Expr)
+			*x = cx
 		}
 	}
 }
 
+// isUnnamedAssignable returns true if xt is an unnamed (non-interface)
+// type which is assigned to the declared type t of the same base type.
+func isUnnamedAssignable(xt Type, t Type) bool {
+	if xt == nil || t == nil {
+		return false
+	}
+	dt, ok := t.(*DeclaredType)
+	if !ok || dt.Kind() == InterfaceKind {
+		return false
+	}
+	switch xt.(type) {
+	case *SliceType, *MapType, *ArrayType, *StructType, *PointerType, *FuncType, *ChanType:
+		return xt.TypeID() == dt.Base.TypeID()
+	default:
+		return false
+	}
+}
+
 // like checkOrConvertType(last, x, nil)
 func convertIfConst(store Store, last BlockNode, x Expr) {
 	if cx, ok := x.(*ConstExpr); ok {
@@ -2479,8 +2645,9 @@ func checkType(xt Type, dt Type, autoNative bool) {
 		}
 	} else if ddt, ok := dt.(*DeclaredType); ok {
 		// special case if implicitly named primitive type.
+		// an untyped value is assignable to ddt of the same kind.
 		// TODO simplify with .IsNamedType().
-		if _, ok := xt.(PrimitiveType); ok {
+		if _, ok := xt.(PrimitiveType); ok && !isUntyped(xt) {
 			panic(fmt.Sprintf(
 				"cannot use %s as %s without explicit conversion",
 				xt.String(),
@@ -2559,10 +2726,19 @@ func checkType(xt Type, dt Type, autoNative bool) {
 		panic("should not happen")
 	case *DeclaredType:
 		panic("should not happen")
-	case *StructType, *PackageType, *ChanType:
+	case *StructType, *PackageType:
+		if xt.TypeID() == cdt.TypeID() {
+			return // ok
+		}
+	case *ChanType:
 		if xt.TypeID() == cdt.TypeID() {
 			return // ok
 		}
+		// bidirectional channels can be used as directional ones.
+		if xct, ok := xt.(*ChanType); ok && xct.Dir == BOTH &&
+			xct.Elt.TypeID() == cdt.Elt.TypeID() {
+			return // ok
+		}
 	case *TypeType:
 		if xt.TypeID() == cdt.TypeID() {
 			return // ok
@@ -2792,6 +2968,17 @@ func findUndefined2(store Store, last BlockNode, x Expr, t Type) (un Name) {
 		if un != "" {
 			return
 		}
+	case *IndexListExpr:
+		un = findUndefined(store, last, cx.X)
+		if un != "" {
+			return
+		}
+		for i := range cx.Indices {
+			un = findUndefined(store, last, cx.Indices[i])
+			if un != "" {
+				return
+			}
+		}
 	case *constTypeExpr:
 		return
 	case *ConstExpr:
@@ -2890,6 +3077,10 @@ func predefineNow2(store Store, last BlockNode, d Decl, m map[Name]struct{}) (De
 			break
 		}
 	}
+	if isGenericDecl(d) {
+		// generic decls are only preprocessed when instantiated.
+		return d, true
+	}
 	switch cd := d.(type) {
 	case *FuncDecl:
 		// *FuncValue/*FuncType is mostly empty still; here
@@ -2965,6 +3156,31 @@ func tryPredefine(store Store, last BlockNode, d Decl) (un Name) {
 		}
 	}()
 
+	// Generic decls are predefined as such, without a value.
+	if isGenericDecl(d) {
+		fn, ok := last.(*FileNode)
+		if !ok {
+			panic(fmt.Sprintf(
+				"generic declaration %s must be at package level",
+				d.String()))
+		}
+		switch d := d.(type) {
+		case *FuncDecl:
+			if d.IsMethod {
+				// instantiated with its type.
+				return ""
+			}
+			packageOf(fn).Define2(false, d.Name,
+				&genericType{Decl: d, File: fn}, TypedValue{})
+			d.Path = last.GetPathForName(store, d.Name)
+		case *TypeDecl:
+			packageOf(fn).Define2(false, d.Name,
+				&genericType{Decl: d, File: fn}, TypedValue{})
+			d.Path = last.GetPathForName(store, d.Name)
+		}
+		return ""
+	}
+
 	// NOTE: These happen upon enter from the top,
 	// so value paths cannot be used here.
 	switch d := d.(type) {
@@ -3037,6 +3253,11 @@ func tryPredefine(store Store, last BlockNode, d Decl) (un Name) {
 			case *NameExpr:
 				if tv := last.GetValueRef(store, tx.Name); tv != nil {
 					// (file) block name
+					if _, ok := last.GetStaticTypeOf(store, tx.Name).(*genericType); ok {
+						panic(fmt.Sprintf(
+							"cannot use generic type %s without instantiation",
+							tx.Name))
+					}
 					t = tv.GetType()
 					if dt, ok := t.(*DeclaredType); ok {
 						if !dt.sealed {
@@ -3078,6 +3299,14 @@ func tryPredefine(store Store, last BlockNode, d Decl) (un Name) {
 				tx.Path = pn.GetPathForName(store, tx.Sel)
 				ptr := pv.GetBlock(store).GetPointerTo(store, tx.Path)
 				t = ptr.TV.T
+			case *IndexExpr, *IndexListExpr:
+				// instance of generic type.
+				un = findUndefined(store, last, tx)
+				if un != "" {
+					return
+				}
+				d.Type = Preprocess(store, last, tx).(Expr)
+				t = evalStaticType(store, last, d.Type)
 			default:
 				panic(fmt.Sprintf(
 					"unexpected type declaration type %v",
@@ -3476,6 +3705,11 @@ func findDependentNames(n Node, dst map[Name]struct{}) {
 	case *IndexExpr:
 		findDependentNames(cn.X, dst)
 		findDependentNames(cn.Index, dst)
+	case *IndexListExpr:
+		findDependentNames(cn.X, dst)
+		for i := range cn.Indices {
+			findDependentNames(cn.Indices[i], dst)
+		}
 	case *FuncLitExpr:
 		findDependentNames(&cn.Type, dst)
 		for _, n := range cn.GetExternNames() {
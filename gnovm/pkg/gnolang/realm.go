@@ -279,8 +279,7 @@ func (rlm *Realm) MarkNewEscaped(oo Object) {
 // OpReturn calls this when exiting a realm transaction.
 func (rlm *Realm) FinalizeRealmTransaction(readonly bool, store Store) {
 	if readonly {
-		if true ||
-			len(rlm.newCreated) > 0 ||
+		if len(rlm.newCreated) > 0 ||
 			len(rlm.newEscaped) > 0 ||
 			len(rlm.newDeleted) > 0 ||
 			len(rlm.created) > 0 ||
@@ -868,6 +867,8 @@ func getChildObjects(val Value, more []Value) []Value {
 		}
 		more = getSelfOrChildObjects(cv.Parent, more)
 		return more
+	case *ChanValue:
+		panic("channels cannot be persisted")
 	case *NativeValue:
 		panic("native values not supported")
 	default:
@@ -960,6 +961,20 @@ func copyFieldsWithRefs(fields []FieldType) []FieldType {
 	return fieldsCpy
 }
 
+func copyTermsWithRefs(terms []TypeTerm) []TypeTerm {
+	if terms == nil {
+		return nil
+	}
+	termsCpy := make([]TypeTerm, len(terms))
+	for i, term := range terms {
+		termsCpy[i] = TypeTerm{
+			Tilde: term.Tilde,
+			Type:  refOrCopyType(term.Type),
+		}
+	}
+	return termsCpy
+}
+
 // Copies type but with references to dependant types;
 // the result is suitable for persistence bytes serialization.
 func copyTypeWithRefs(typ Type) Type {
@@ -1005,6 +1020,7 @@ func copyTypeWithRefs(typ Type) Type {
 			PkgPath: ct.PkgPath,
 			Methods: copyFieldsWithRefs(ct.Methods),
 			Generic: ct.Generic,
+			Terms:   copyTermsWithRefs(ct.Terms),
 		}
 	case *TypeType:
 		return &TypeType{}
@@ -1245,6 +1261,9 @@ func fillType(store Store, typ Type) Type {
 		for i, mthd := range ct.Methods {
 			ct.Methods[i].Type = fillType(store, mthd.Type)
 		}
+		for i, term := range ct.Terms {
+			ct.Terms[i].Type = fillType(store, term.Type)
+		}
 		return ct
 	case *TypeType:
 		return ct // nothing to do
@@ -1341,10 +1360,12 @@ func fillTypesOfValue(store Store, val Value) Value {
 		fillTypesTV(store, &cv.Receiver)
 		return cv
 	case *MapValue:
-		cv.MakeMap(cv.List.Size) // TODO move out.
+		// rebuild the index of the loaded list.
+		cv.vmap = make(map[MapKey]*MapListItem, cv.List.Size) // TODO move out.
 		for cur := cv.List.Head; cur != nil; cur = cur.Next {
 			fillTypesTV(store, &cur.Key)
 			fillTypesTV(store, &cur.Value)
+			cv.vmap[cur.Key.ComputeMapKey(store, false)] = cur
 		}
 		return cv
 	case TypeValue:
@@ -24,6 +24,7 @@ type Store interface {
 	SetPackageGetter(PackageGetter)
 	GetPackage(pkgPath string, isImport bool) *PackageValue
 	SetCachePackage(*PackageValue)
+	DelCachePackage(pkgPath string)
 	GetPackageRealm(pkgPath string) *Realm
 	SetPackageRealm(*Realm)
 	GetObject(oid ObjectID) Object
@@ -37,6 +38,8 @@ type Store interface {
 	GetBlockNode(Location) BlockNode
 	GetBlockNodeSafe(Location) BlockNode
 	SetBlockNode(BlockNode)
+	GetCacheInstance(key string) *TypedValue
+	SetCacheInstance(key string, tv TypedValue)
 	// UNSTABLE
 	SetStrictGo2GnoMapping(bool)
 	AddGo2GnoMapping(rt reflect.Type, pkgPath string, name string)
@@ -68,6 +71,7 @@ type defaultStore struct {
 	cacheObjects     map[ObjectID]Object
 	cacheTypes       map[TypeID]Type
 	cacheNodes       map[Location]BlockNode
+	cacheInstances   map[string]TypedValue // generic instances, by key
 	cacheNativeTypes map[reflect.Type]Type // go spec: reflect.Type are comparable
 	baseStore        store.Store           // for objects, types, nodes
 	iavlStore        store.Store           // for escaped object hashes
@@ -87,6 +91,7 @@ func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore
 		cacheObjects:     make(map[ObjectID]Object),
 		cacheTypes:       make(map[TypeID]Type),
 		cacheNodes:       make(map[Location]BlockNode),
+		cacheInstances:   make(map[string]TypedValue),
 		cacheNativeTypes: make(map[reflect.Type]Type),
 		baseStore:        baseStore,
 		iavlStore:        iavlStore,
@@ -209,6 +214,31 @@ func (ds *defaultStore) SetCachePackage(pv *PackageValue) {
 	ds.cacheObjects[oid] = pv
 }
 
+// Used to remove throwaway packages that were never saved, along with
+// their cached nodes, and the cached types and instances referring to
+// them, so that they do not accumulate in long-lived stores.
+func (ds *defaultStore) DelCachePackage(pkgPath string) {
+	delete(ds.cacheObjects, ObjectIDFromPkgPath(pkgPath))
+	// instances of other packages' generics are named after their
+	// type arguments, e.g. "gno.land/p/demo/box.Box[<pkgPath>.T]".
+	ref := pkgPath + "."
+	for loc := range ds.cacheNodes {
+		if loc.PkgPath == pkgPath || strings.Contains(loc.File, ref) {
+			delete(ds.cacheNodes, loc)
+		}
+	}
+	for tid := range ds.cacheTypes {
+		if strings.Contains(string(tid), ref) {
+			delete(ds.cacheTypes, tid)
+		}
+	}
+	for key := range ds.cacheInstances {
+		if strings.Contains(key, ref) {
+			delete(ds.cacheInstances, key)
+		}
+	}
+}
+
 // Some atomic operation.
 func (ds *defaultStore) GetPackageRealm(pkgPath string) (rlm *Realm) {
 	oid := ObjectIDFromPkgPath(pkgPath)
@@ -489,6 +519,25 @@ func (ds *defaultStore) SetBlockNode(bn BlockNode) {
 	// XXX
 }
 
+// Instances of generic functions and types are only cached, as they are
+// re-instantiated when the packages using them are preprocessed again,
+// e.g. upon restart; the declared types of type instances are stored,
+// and reused then. Setting an undefined tv removes the instance.
+func (ds *defaultStore) GetCacheInstance(key string) *TypedValue {
+	if tv, exists := ds.cacheInstances[key]; exists {
+		return &tv
+	}
+	return nil
+}
+
+func (ds *defaultStore) SetCacheInstance(key string, tv TypedValue) {
+	if tv.IsUndefined() {
+		delete(ds.cacheInstances, key)
+		return
+	}
+	ds.cacheInstances[key] = tv
+}
+
 func (ds *defaultStore) NumMemPackages() int64 {
 	ctrkey := []byte(backendPackageIndexCtrKey())
 	ctrbz := ds.baseStore.Get(ctrkey)
@@ -599,6 +648,7 @@ func (ds *defaultStore) Fork() Store {
 		cacheObjects:     make(map[ObjectID]Object), // new cache.
 		cacheTypes:       ds.cacheTypes,
 		cacheNodes:       ds.cacheNodes,
+		cacheInstances:   ds.cacheInstances,
 		cacheNativeTypes: ds.cacheNativeTypes,
 		baseStore:        ds.baseStore,
 		iavlStore:        ds.iavlStore,
@@ -698,6 +748,7 @@ func (ds *defaultStore) ClearCache() {
 	ds.cacheObjects = make(map[ObjectID]Object)
 	ds.cacheTypes = make(map[TypeID]Type)
 	ds.cacheNodes = make(map[Location]BlockNode)
+	ds.cacheInstances = make(map[string]TypedValue)
 	ds.cacheNativeTypes = make(map[reflect.Type]Type)
 	// restore builtin types to cache.
 	InitStoreCaches(ds)
@@ -769,7 +820,8 @@ func InitStoreCaches(store Store) {
 		gPackageType,
 		blockType{},
 		Float32Type, Float64Type,
-		gErrorType, // from uverse.go
+		gErrorType,      // from uverse.go
+		gComparableType, // from uverse.go
 	}
 	for _, tt := range types {
 		store.SetCacheType(tt)
@@ -873,16 +873,55 @@ func (pt *PackageType) GetPkgPath() string {
 type InterfaceType struct {
 	PkgPath string
 	Methods []FieldType
-	Generic Name // for uverse "generics"
+	Generic Name       // for uverse "generics"
+	Terms   []TypeTerm `json:",omitempty"` // type set of constraint.
 
 	typeid TypeID
 }
 
+// A TypeTerm is an element of the type set of a constraint interface,
+// as in `interface{ ~int | string }`. A term with Tilde matches all types
+// whose underlying type is Type.
+type TypeTerm struct {
+	Tilde bool
+	Type  Type
+}
+
+func (tt TypeTerm) TypeID() TypeID {
+	if tt.Tilde {
+		return typeid("~" + tt.Type.TypeID().String())
+	}
+	return tt.Type.TypeID()
+}
+
+func (tt TypeTerm) String() string {
+	if tt.Tilde {
+		return "~" + tt.Type.String()
+	}
+	return tt.Type.String()
+}
+
+// Returns true if ot is in the type set of tt.
+func (tt TypeTerm) Matches(ot Type) bool {
+	if tt.Tilde {
+		return baseOf(ot).TypeID() == baseOf(tt.Type).TypeID()
+	}
+	return ot.TypeID() == tt.Type.TypeID()
+}
+
+func typeTermsString(tts []TypeTerm) string {
+	strs := make([]string, len(tts))
+	for i, tt := range tts {
+		strs[i] = tt.String()
+	}
+	return strings.Join(strs, "|")
+}
+
 // General empty interface.
 var gEmptyInterfaceType *InterfaceType = &InterfaceType{}
 
 func (it *InterfaceType) IsEmptyInterface() bool {
-	return len(it.Methods) == 0
+	return len(it.Methods) == 0 && len(it.Terms) == 0
 }
 
 func (it *InterfaceType) Kind() Kind {
@@ -903,7 +942,15 @@ func (it *InterfaceType) TypeID() TypeID {
 		ms := FieldTypeList(it.Methods)
 		// XXX pre-sort.
 		sort.Sort(ms)
-		it.typeid = typeid("interface{" + ms.TypeIDForPackage(it.PkgPath).String() + "}")
+		if len(it.Terms) == 0 {
+			it.typeid = typeid("interface{" + ms.TypeIDForPackage(it.PkgPath).String() + "}")
+		} else {
+			tids := make([]string, len(it.Terms))
+			for i, tt := range it.Terms {
+				tids[i] = tt.TypeID().String()
+			}
+			it.typeid = typeid("interface{" + ms.TypeIDForPackage(it.PkgPath).String() + ";" + strings.Join(tids, "|") + "}")
+		}
 	}
 	return it.typeid
 }
@@ -913,6 +960,10 @@ func (it *InterfaceType) String() string {
 		return fmt.Sprintf("<%s>{%s}",
 			it.Generic,
 			FieldTypeList(it.Methods).String())
+	} else if len(it.Terms) > 0 {
+		return fmt.Sprintf("interface{%s;%s}",
+			FieldTypeList(it.Methods).String(),
+			typeTermsString(it.Terms))
 	} else {
 		return fmt.Sprintf("interface{%s}",
 			FieldTypeList(it.Methods).String())
@@ -978,7 +1029,27 @@ func (it *InterfaceType) FindEmbeddedFieldType(callerPath string, n Name, m map[
 // For run-time type assertion.
 // TODO: optimize somehow.
 func (it *InterfaceType) IsImplementedBy(ot Type) (result bool) {
+	if len(it.Terms) > 0 {
+		// constraint with type set, ot must be in it.
+		found := false
+		for _, tt := range it.Terms {
+			if tt.Matches(ot) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
 	for _, im := range it.Methods {
+		if im.Type == gComparableType {
+			// embedded comparable constraint.
+			if !isComparableType(ot) {
+				return false
+			}
+			continue
+		}
 		if im.Type.Kind() == InterfaceKind {
 			// field is embedded interface...
 			im2 := baseOf(im.Type).(*InterfaceType)
@@ -1041,11 +1112,11 @@ func (ct *ChanType) TypeID() TypeID {
 	if ct.typeid.IsZero() {
 		switch ct.Dir {
 		case SEND | RECV:
-			ct.typeid = typeid("chan{%s}" + ct.Elt.TypeID().String())
+			ct.typeid = typeid("chan{%s}", ct.Elt.TypeID().String())
 		case SEND:
-			ct.typeid = typeid("<-chan{%s}" + ct.Elt.TypeID().String())
+			ct.typeid = typeid("chan<-{%s}", ct.Elt.TypeID().String())
 		case RECV:
-			ct.typeid = typeid("chan<-{%s}" + ct.Elt.TypeID().String())
+			ct.typeid = typeid("<-chan{%s}", ct.Elt.TypeID().String())
 		default:
 			panic("should not happen")
 		}
@@ -1058,9 +1129,9 @@ func (ct *ChanType) String() string {
 	case SEND | RECV:
 		return "chan " + ct.Elt.String()
 	case SEND:
-		return "<-chan " + ct.Elt.String()
-	case RECV:
 		return "chan<- " + ct.Elt.String()
+	case RECV:
+		return "<-chan " + ct.Elt.String()
 	default:
 		panic("should not happen")
 	}
@@ -1163,6 +1234,13 @@ func (ft *FuncType) Specify(store Store, argTVs []TypedValue, isVarg bool) *Func
 					vargt = varg.T
 				} else if isUntyped(varg.T) && vargt.TypeID() == defaultTypeOf(varg.T).TypeID() {
 					vargt = defaultTypeOf(varg.T)
+				} else if isUntyped(varg.T) && !isUntyped(vargt) {
+					// e.g. append(b, x, 'a'), the constant is
+					// converted to the type of x.
+					continue
+				} else if isUntyped(vargt) && !isUntyped(varg.T) {
+					// e.g. append(b, 'a', x), likewise.
+					vargt = varg.T
 				} else if vargt.TypeID() != varg.T.TypeID() {
 					panic(fmt.Sprintf(
 						"incompatible varg types: expected %v, got %s",
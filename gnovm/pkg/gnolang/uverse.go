@@ -55,6 +55,17 @@ var gStringerType = &DeclaredType{
 	sealed: true,
 }
 
+// The comparable constraint; its type set is all comparable types. It is
+// special-cased by InterfaceType.IsImplementedBy.
+var gComparableType = &DeclaredType{
+	PkgPath: uversePkgPath,
+	Name:    "comparable",
+	Base: &InterfaceType{
+		PkgPath: uversePkgPath,
+	},
+	sealed: true,
+}
+
 // ----------------------------------------
 // Uverse package
 
@@ -128,6 +139,8 @@ func UverseNode() *PackageNode {
 	// by a TypeValue.
 	def("typeval", asValue(gTypeType))
 	def("error", asValue(gErrorType))
+	def("any", asValue(gEmptyInterfaceType))
+	def("comparable", asValue(gComparableType))
 
 	// Values
 	def("true", untypedBool(true))
@@ -596,7 +609,16 @@ func UverseNode() *PackageNode {
 			return
 		},
 	)
-	def("close", undefined)
+	defNative("close",
+		Flds( // params
+			"c", AnyT(),
+		),
+		nil, // results
+		func(m *Machine) {
+			arg0 := m.LastBlock().GetParams1()
+			m.chanClose(arg0.TV)
+		},
+	)
 	def("complex", undefined)
 	defNative("copy",
 		Flds( // params
@@ -664,11 +686,22 @@ func UverseNode() *PackageNode {
 					}
 					dstv := dst.TV.V.(*SliceValue)
 					srcv := src.TV.V.(*SliceValue)
-					for i := 0; i < minl; i++ {
+					copyAt := func(i int) {
 						dstev := dstv.GetPointerAtIndexInt2(m.Store, i, bdt.Elt)
 						srcev := srcv.GetPointerAtIndexInt2(m.Store, i, bst.Elt)
 						dstev.Assign2(m.Alloc, m.Store, m.Realm, srcev.Deref(), false)
 					}
+					if dstv.Offset > srcv.Offset &&
+						dstv.GetBase(m.Store) == srcv.GetBase(m.Store) {
+						// overlapping, copy from the end.
+						for i := minl - 1; i >= 0; i-- {
+							copyAt(i)
+						}
+					} else {
+						for i := 0; i < minl; i++ {
+							copyAt(i)
+						}
+					}
 					res0 := TypedValue{
 						T: IntType,
 						V: nil,
@@ -832,9 +865,22 @@ func UverseNode() *PackageNode {
 				}
 			case *ChanType:
 				if vargsl == 0 {
-					panic("not yet implemented")
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.Alloc.NewChan(0),
+					})
+					return
 				} else if vargsl == 1 {
-					panic("not yet implemented")
+					sv := vargs.TV.GetPointerAtIndexInt(m.Store, 0).Deref()
+					si := sv.ConvertGetInt()
+					if si < 0 {
+						panic("makechan: size out of range")
+					}
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.Alloc.NewChan(si),
+					})
+					return
 				} else {
 					panic("make() of chan type takes 1 or 2 arguments")
 				}
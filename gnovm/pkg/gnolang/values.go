@@ -34,6 +34,7 @@ func (*SliceValue) assertValue()       {}
 func (*StructValue) assertValue()      {}
 func (*FuncValue) assertValue()        {}
 func (*MapValue) assertValue()         {}
+func (*ChanValue) assertValue()        {}
 func (*BoundMethodValue) assertValue() {}
 func (TypeValue) assertValue()         {}
 func (*PackageValue) assertValue()     {}
@@ -56,6 +57,7 @@ var (
 	_ Value = &StructValue{}
 	_ Value = &FuncValue{}
 	_ Value = &MapValue{}
+	_ Value = &ChanValue{}
 	_ Value = &BoundMethodValue{}
 	_ Value = TypeValue{}
 	_ Value = &PackageValue{}
@@ -661,7 +663,9 @@ func (ml *MapList) UnmarshalAmino(mlimg MapListImage) error {
 			ml.Head = item
 		}
 		item.Prev = ml.Tail
-		ml.Tail.Next = item
+		if ml.Tail != nil {
+			ml.Tail.Next = item
+		}
 		ml.Tail = item
 		ml.Size++
 	}
@@ -764,6 +768,29 @@ func (mv *MapValue) DeleteForKey(store Store, key *TypedValue) {
 	}
 }
 
+// ----------------------------------------
+// ChanValue
+
+// Channels only live in memory; they are not objects, and cannot be
+// persisted. Goroutines blocked sending to or receiving from a channel
+// are queued on it in order, see goroutine.go.
+type ChanValue struct {
+	Cap    int          // buffer capacity, 0 if unbuffered.
+	Buffer []TypedValue // buffered values, oldest first.
+	Closed bool
+
+	sendq []*chanCase // blocked senders, oldest first.
+	recvq []*chanCase // blocked receivers, oldest first.
+}
+
+func (cv *ChanValue) GetLength() int {
+	return len(cv.Buffer)
+}
+
+func (cv *ChanValue) GetCapacity() int {
+	return cv.Cap
+}
+
 // ----------------------------------------
 // TypeValue
 
@@ -1463,7 +1490,10 @@ func (tv *TypedValue) ComputeMapKey(store Store, omitType bool) MapKey {
 		pbz := tv.PrimitiveBytes()
 		bz = append(bz, pbz...)
 	case *PointerType:
-		ptr := uintptr(unsafe.Pointer(tv.V.(PointerValue).TV))
+		var ptr uintptr // nil pointer.
+		if tv.V != nil {
+			ptr = uintptr(unsafe.Pointer(tv.V.(PointerValue).TV))
+		}
 		bz = append(bz, uintptrToBytes(&ptr)...)
 	case FieldType:
 		panic("field (pseudo)type cannot be used as map key")
@@ -1493,7 +1523,7 @@ func (tv *TypedValue) ComputeMapKey(store Store, omitType bool) MapKey {
 		for i := 0; i < sl; i++ {
 			fv := fillValueTV(store, &sv.Fields[i])
 			ft := bt.Fields[i]
-			omitTypes := ft.Elem().Kind() != InterfaceKind
+			omitTypes := ft.Type.Kind() != InterfaceKind
 			bz = append(bz, fv.ComputeMapKey(store, omitTypes)...)
 			if i != sl-1 {
 				bz = append(bz, ',')
@@ -1981,7 +2011,7 @@ func (tv *TypedValue) GetLength() int {
 			return 0
 		case *ArrayType:
 			return bt.Len
-		case *SliceType:
+		case *SliceType, *ChanType:
 			return 0
 		default:
 			panic(fmt.Sprintf(
@@ -1998,6 +2028,8 @@ func (tv *TypedValue) GetLength() int {
 		return cv.GetLength()
 	case *MapValue:
 		return cv.GetLength()
+	case *ChanValue:
+		return cv.GetLength()
 	case *NativeValue:
 		return cv.Value.Len()
 	default:
@@ -2014,6 +2046,7 @@ func (tv *TypedValue) GetCapacity() int {
 			// strings have no capacity.
 			case *ArrayType:
 			case *SliceType:
+			case *ChanType:
 			default:
 				panic("should not happen")
 			}
@@ -2027,6 +2060,8 @@ func (tv *TypedValue) GetCapacity() int {
 		return cv.GetCapacity()
 	case *SliceValue:
 		return cv.GetCapacity()
+	case *ChanValue:
+		return cv.GetCapacity()
 	case *NativeValue:
 		return cv.Value.Cap()
 	default:
@@ -2431,6 +2466,10 @@ func defaultValue(alloc *Allocator, t Type) Value {
 	}
 }
 
+func DefaultTypedValue(alloc *Allocator, t Type) TypedValue {
+	return defaultTypedValue(alloc, t)
+}
+
 func defaultTypedValue(alloc *Allocator, t Type) TypedValue {
 	if t.Kind() == InterfaceKind {
 		return TypedValue{}
@@ -2466,6 +2505,10 @@ func typedString(s string) TypedValue {
 	return tv
 }
 
+func FillValueTV(store Store, tv *TypedValue) *TypedValue {
+	return fillValueTV(store, tv)
+}
+
 func fillValueTV(store Store, tv *TypedValue) *TypedValue {
 	switch cv := tv.V.(type) {
 	case RefValue:
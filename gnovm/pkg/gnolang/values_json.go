@@ -0,0 +1,233 @@
+package gnolang
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// JSONValue is the JSON representation of a TypedValue. T is the type of
+// the value, and V its value:
+//
+//   - bool, float32 and float64 are JSON booleans and numbers; NaN and
+//     infinities are strings.
+//   - 8, 16 and 32 bit integers are JSON numbers; int, int64, uint, uint64,
+//     bigint and bigdec are decimal strings, as in amino JSON.
+//   - strings, including std.Address, are JSON strings.
+//   - byte arrays and byte slices are base64 strings.
+//   - other arrays and slices are lists of JSONValue.
+//   - structs are objects mapping field names to JSONValue.
+//   - maps are lists of JSONMapEntry, in insertion order.
+//   - pointers are the JSONValue of the value pointed to.
+//   - nil pointers, slices, maps, funcs and interfaces are null.
+//   - funcs are their names, and types and packages their string form.
+//
+// T is null for the nil interface value.
+type JSONValue struct {
+	T *string     `json:"T"`
+	V interface{} `json:"V"`
+}
+
+// JSONMapEntry is a key/value pair of a JSON encoded map.
+type JSONMapEntry struct {
+	Key   JSONValue `json:"Key"`
+	Value JSONValue `json:"Value"`
+}
+
+// TypedValuesJSON returns the JSON encoding of tvs as a list of JSONValue,
+// e.g. the results of Machine.Eval. Values from the store are loaded as
+// needed. It fails on cyclic values, and on channels.
+func TypedValuesJSON(store Store, tvs []TypedValue) ([]byte, error) {
+	jvs := make([]JSONValue, len(tvs))
+	enc := jsonEncoder{store: store, visiting: make(map[interface{}]struct{})}
+	for i := range tvs {
+		jv, err := enc.encode(&tvs[i])
+		if err != nil {
+			return nil, err
+		}
+		jvs[i] = jv
+	}
+	return json.Marshal(jvs)
+}
+
+type jsonEncoder struct {
+	store Store
+	// referenced values being encoded, to detect cycles.
+	visiting map[interface{}]struct{}
+}
+
+// enter marks the referenced value ref as being encoded, and returns the
+// function to call once done. It fails if ref is already being encoded.
+func (enc jsonEncoder) enter(ref interface{}, ts string) (func(), error) {
+	if _, ok := enc.visiting[ref]; ok {
+		return nil, fmt.Errorf("cannot encode cyclic value of type %s", ts)
+	}
+	enc.visiting[ref] = struct{}{}
+	return func() { delete(enc.visiting, ref) }, nil
+}
+
+func (enc jsonEncoder) encode(tv *TypedValue) (jv JSONValue, err error) {
+	if tv.T == nil {
+		return JSONValue{}, nil
+	}
+	ts := tv.T.String()
+	jv.T = &ts
+	if nv, ok := tv.V.(*NativeValue); ok {
+		jv.V = fmt.Sprintf("%v", nv.Value.Interface())
+		return jv, nil
+	}
+	switch bt := baseOf(tv.T); bt.Kind() {
+	case BoolKind:
+		jv.V = tv.GetBool()
+	case StringKind:
+		jv.V = tv.GetString()
+	case IntKind:
+		jv.V = strconv.FormatInt(int64(tv.GetInt()), 10)
+	case Int8Kind:
+		jv.V = tv.GetInt8()
+	case Int16Kind:
+		jv.V = tv.GetInt16()
+	case Int32Kind:
+		jv.V = tv.GetInt32()
+	case Int64Kind:
+		jv.V = strconv.FormatInt(tv.GetInt64(), 10)
+	case UintKind:
+		jv.V = strconv.FormatUint(uint64(tv.GetUint()), 10)
+	case Uint8Kind:
+		jv.V = tv.GetUint8()
+	case Uint16Kind:
+		jv.V = tv.GetUint16()
+	case Uint32Kind:
+		jv.V = tv.GetUint32()
+	case Uint64Kind:
+		jv.V = strconv.FormatUint(tv.GetUint64(), 10)
+	case Float32Kind:
+		jv.V = jsonFloat(float64(tv.GetFloat32()))
+	case Float64Kind:
+		jv.V = jsonFloat(tv.GetFloat64())
+	case BigintKind:
+		jv.V = tv.GetBigInt().String()
+	case BigdecKind:
+		jv.V = tv.GetBigDec().String()
+	case ArrayKind:
+		av := fillValueTV(enc.store, tv).V.(*ArrayValue)
+		jv.V, err = enc.encodeList(av, 0, av.GetLength(), bt.Elem())
+	case SliceKind:
+		if tv.V == nil {
+			return jv, nil
+		}
+		sv := tv.V.(*SliceValue)
+		av := sv.GetBase(enc.store)
+		if av == nil {
+			return jv, nil
+		}
+		leave, err := enc.enter(av, ts)
+		if err != nil {
+			return jv, err
+		}
+		defer leave()
+		jv.V, err = enc.encodeList(av, sv.Offset, sv.Length, bt.Elem())
+		return jv, err
+	case StructKind:
+		st := bt.(*StructType)
+		sv := fillValueTV(enc.store, tv).V.(*StructValue)
+		fields := make(map[string]JSONValue, len(st.Fields))
+		for i, ft := range st.Fields {
+			fv := fillValueTV(enc.store, &sv.Fields[i])
+			if fields[string(ft.Name)], err = enc.encode(fv); err != nil {
+				return jv, err
+			}
+		}
+		jv.V = fields
+	case MapKind:
+		if tv.V == nil {
+			return jv, nil
+		}
+		mv := fillValueTV(enc.store, tv).V.(*MapValue)
+		if mv.List == nil {
+			return jv, nil
+		}
+		leave, err := enc.enter(mv, ts)
+		if err != nil {
+			return jv, err
+		}
+		defer leave()
+		entries := make([]JSONMapEntry, 0, mv.GetLength())
+		for item := mv.List.Head; item != nil; item = item.Next {
+			var entry JSONMapEntry
+			if entry.Key, err = enc.encode(fillValueTV(enc.store, &item.Key)); err != nil {
+				return jv, err
+			}
+			if entry.Value, err = enc.encode(fillValueTV(enc.store, &item.Value)); err != nil {
+				return jv, err
+			}
+			entries = append(entries, entry)
+		}
+		jv.V = entries
+		return jv, nil
+	case PointerKind:
+		if tv.V == nil {
+			return jv, nil
+		}
+		pv := fillValueTV(enc.store, tv).V.(PointerValue)
+		leave, err := enc.enter(pv.TV, ts)
+		if err != nil {
+			return jv, err
+		}
+		defer leave()
+		etv := pv.Deref()
+		jv.V, err = enc.encode(fillValueTV(enc.store, &etv))
+		return jv, err
+	case FuncKind:
+		switch fv := tv.V.(type) {
+		case nil:
+		case *FuncValue:
+			jv.V = string(fv.Name)
+		case *BoundMethodValue:
+			jv.V = string(fv.Func.Name)
+		}
+	case InterfaceKind:
+		// the nil value of a non-empty interface type.
+	case TypeKind:
+		jv.V = tv.V.(TypeValue).Type.String()
+	case PackageKind:
+		jv.V = fillValueTV(enc.store, tv).V.(*PackageValue).PkgPath
+	default:
+		return jv, fmt.Errorf("cannot encode value of type %s", ts)
+	}
+	return jv, err
+}
+
+// encodeList encodes length elements of av starting at offset.
+func (enc jsonEncoder) encodeList(av *ArrayValue, offset, length int, et Type) (interface{}, error) {
+	if av.Data != nil {
+		return base64.StdEncoding.EncodeToString(av.Data[offset : offset+length]), nil
+	}
+	if et.Kind() == Uint8Kind {
+		bz := make([]byte, length)
+		for i := range bz {
+			bz[i] = av.List[offset+i].GetUint8()
+		}
+		return base64.StdEncoding.EncodeToString(bz), nil
+	}
+	list := make([]JSONValue, length)
+	for i := range list {
+		ev := fillValueTV(enc.store, &av.List[offset+i])
+		jv, err := enc.encode(ev)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = jv
+	}
+	return list, nil
+}
+
+// jsonFloat returns f, or its string form if it is not a valid JSON number.
+func jsonFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return f
+}
@@ -118,6 +118,16 @@ func (mv *MapValue) String() string {
 	return "map{" + strings.Join(ss, ",") + "}"
 }
 
+// NOTE: unlike Go, does not print the address, so that output is
+// deterministic.
+func (cv *ChanValue) String() string {
+	ss := make([]string, len(cv.Buffer))
+	for i, tv := range cv.Buffer {
+		ss[i] = tv.String()
+	}
+	return "chan{" + strings.Join(ss, ",") + "}"
+}
+
 func (v TypeValue) String() string {
 	ptr := ""
 	if reflect.TypeOf(v.Type).Kind() == reflect.Ptr {
@@ -256,8 +266,10 @@ func (tv *TypedValue) Sprint(m *Machine) string {
 	case *PackageType:
 		return tv.V.(*PackageValue).String()
 	case *ChanType:
-		panic("not yet implemented")
-		// return tv.V.(*ChanValue).String()
+		if tv.V == nil {
+			return "nil " + tv.T.String()
+		}
+		return tv.V.(*ChanValue).String()
 	case *NativeType:
 		return fmt.Sprintf("%v",
 			tv.V.(*NativeValue).Value.Interface())
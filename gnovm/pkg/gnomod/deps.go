@@ -0,0 +1,291 @@
+package gnomod
+
+import (
+	"fmt"
+	"go/parser"
+	gotoken "go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// VendorDir is the directory of a module where gno mod vendor copies the
+// packages it requires.
+const VendorDir = "vendor"
+
+// isRequirable returns whether the package of the import path can be
+// required by a gno.mod, i.e. is not a standard library.
+func isRequirable(path string) bool {
+	return strings.HasPrefix(path, "gno.land/")
+}
+
+// ReadImports returns the imports of the gno files of the directory dir,
+// including its tests, which can be required by a gno.mod, sorted.
+func ReadImports(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	mpkg := &std.MemPackage{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".gno") {
+			continue
+		}
+		bz, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		mpkg.Files = append(mpkg.Files, &std.MemFile{Name: filepath.Join(dir, entry.Name()), Body: string(bz)})
+	}
+	return packageImports(mpkg)
+}
+
+// packageImports returns the imports of the gno files of mpkg which can be
+// required by a gno.mod, sorted.
+func packageImports(mpkg *std.MemPackage) ([]string, error) {
+	fset := gotoken.NewFileSet()
+	seen := make(map[string]bool)
+	var imports []string
+	for _, mfile := range mpkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, mfile.Name, mfile.Body, parser.ImportsOnly)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return nil, err
+			}
+			if isRequirable(path) && !seen[path] && path != mpkg.Path {
+				seen[path] = true
+				imports = append(imports, path)
+			}
+		}
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// Tidy sets the requirements of f to imports, keeping the versions of the
+// ones already required, and updates the syntax of f.
+func (f *File) Tidy(imports []string) {
+	versions := make(map[string]string)
+	for _, r := range f.Require {
+		versions[r.Mod.Path] = r.Mod.Version
+	}
+	var reqs []*modfile.Require
+	for _, path := range imports {
+		if f.Module != nil && path == f.Module.Mod.Path {
+			continue
+		}
+		version, ok := versions[path]
+		if !ok {
+			version = "v0.0.0"
+		}
+		reqs = append(reqs, &modfile.Require{Mod: module.Version{Path: path, Version: version}})
+	}
+
+	if f.Syntax == nil {
+		f.Syntax = &modfile.FileSyntax{}
+	}
+	// edit the syntax of the requirements with modfile, as gno.mod has the
+	// syntax of go.mod.
+	mf := &modfile.File{
+		Module:  f.Module,
+		Go:      f.Go,
+		Require: f.Require,
+		Replace: f.Replace,
+		Syntax:  f.Syntax,
+	}
+	mf.SetRequire(reqs)
+	mf.Cleanup()
+	f.Require = mf.Require
+}
+
+// Format returns the content of the gno.mod file of f.
+func (f *File) Format() []byte {
+	f.Syntax.Cleanup()
+	return modfile.Format(f.Syntax)
+}
+
+// A resolver reads the packages required by the gno.mod of a module, from
+// the directories that replace them, from the vendor directory, or from
+// the remote.
+type resolver struct {
+	f      *File
+	modDir string // the directory of the module.
+	remote string // if empty, the packages are not fetched.
+
+	noVendor bool // do not read the vendor directory.
+}
+
+// resolve returns the package pkgPath.
+func (r *resolver) resolve(pkgPath string) (*std.MemPackage, error) {
+	path := pkgPath
+	if mod, replaced := isReplaced(module.Version{Path: pkgPath}, r.f.Replace); replaced {
+		if modfile.IsDirectoryPath(mod.Path) {
+			dir := mod.Path
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(r.modDir, dir)
+			}
+			return readPackageDir(dir, pkgPath)
+		}
+		path = mod.Path
+	}
+	if !r.noVendor {
+		vendored := filepath.Join(r.modDir, VendorDir, filepath.FromSlash(pkgPath))
+		if info, err := os.Stat(vendored); err == nil && info.IsDir() {
+			return readPackageDir(vendored, pkgPath)
+		}
+	}
+	if r.remote == "" {
+		return nil, fmt.Errorf("package %s not found in %s, and no remote", pkgPath, VendorDir)
+	}
+
+	res, err := queryChain(r.remote, queryPathFile, []byte(path))
+	if err != nil {
+		return nil, fmt.Errorf("querychain: %w", err)
+	}
+	mpkg := &std.MemPackage{Path: pkgPath}
+	for _, name := range strings.Split(string(res.Data), "\n") {
+		if name == "" {
+			continue
+		}
+		res, err := queryChain(r.remote, queryPathFile, []byte(path+"/"+name))
+		if err != nil {
+			return nil, fmt.Errorf("querychain: %w", err)
+		}
+		mpkg.Files = append(mpkg.Files, &std.MemFile{Name: name, Body: string(res.Data)})
+	}
+	return mpkg, nil
+}
+
+// readPackageDir reads the gno files and the gno.mod of the package in dir.
+func readPackageDir(dir string, pkgPath string) (*std.MemPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	mpkg := &std.MemPackage{Path: pkgPath}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!strings.HasSuffix(name, ".gno") && name != "gno.mod") {
+			continue
+		}
+		bz, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		mpkg.Files = append(mpkg.Files, &std.MemFile{Name: name, Body: string(bz)})
+	}
+	return mpkg, nil
+}
+
+// walk resolves the requirements of f, and the packages they import,
+// recursively, and calls visit with each package and its imports, in
+// breadth-first order.
+func (r *resolver) walk(visit func(mpkg *std.MemPackage, imports []string) error) error {
+	var queue []string
+	seen := make(map[string]bool)
+	push := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			queue = append(queue, path)
+		}
+	}
+	for _, req := range r.f.Require {
+		push(req.Mod.Path)
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		mpkg, err := r.resolve(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		imports, err := packageImports(mpkg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := visit(mpkg, imports); err != nil {
+			return err
+		}
+		for _, imp := range imports {
+			push(imp)
+		}
+	}
+	return nil
+}
+
+// Graph returns the edges of the graph of the requirements of the module
+// f in modDir, as pairs of the requiring and the required package paths:
+// the requirements of f, and the imports of the packages they require,
+// recursively. The packages are read from the directories replacing them,
+// from the vendor directory, or else fetched from the remote.
+func (f *File) Graph(modDir string, remote string) ([][2]string, error) {
+	if f.Module == nil {
+		return nil, fmt.Errorf("requires module")
+	}
+	var edges [][2]string
+	for _, req := range f.Require {
+		edges = append(edges, [2]string{f.Module.Mod.Path, req.Mod.Path})
+	}
+	r := &resolver{f: f, modDir: modDir, remote: remote}
+	err := r.walk(func(mpkg *std.MemPackage, imports []string) error {
+		for _, imp := range imports {
+			edges = append(edges, [2]string{mpkg.Path, imp})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+// Vendor copies the packages required by the module f in modDir, and the
+// packages they import, recursively, in the vendor directory of modDir,
+// e.g. in vendor/gno.land/p/demo/avl, replacing its content. The packages
+// are read from the directories replacing them, or else fetched from the
+// remote.
+func (f *File) Vendor(modDir string, remote string) error {
+	if f.Module == nil {
+		return fmt.Errorf("requires module")
+	}
+	var pkgs []*std.MemPackage
+	// the vendor directory is replaced, so it is not read.
+	r := &resolver{f: f, modDir: modDir, remote: remote, noVendor: true}
+	err := r.walk(func(mpkg *std.MemPackage, _ []string) error {
+		pkgs = append(pkgs, mpkg)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	vendorDir := filepath.Join(modDir, VendorDir)
+	if err := os.RemoveAll(vendorDir); err != nil {
+		return err
+	}
+	for _, mpkg := range pkgs {
+		dir := filepath.Join(vendorDir, filepath.FromSlash(mpkg.Path))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("mkdir %q: %w", dir, err)
+		}
+		for _, mfile := range mpkg.Files {
+			fpath := filepath.Join(dir, mfile.Name)
+			if err := os.WriteFile(fpath, []byte(mfile.Body), 0o644); err != nil {
+				return fmt.Errorf("writefile %q: %w", fpath, err)
+			}
+		}
+	}
+	return nil
+}
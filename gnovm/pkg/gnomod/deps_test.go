@@ -0,0 +1,105 @@
+package gnomod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testModDepsDir = "../../tests/integ/mod-deps"
+
+func readTestGnoMod(t *testing.T, dir string) *File {
+	t.Helper()
+
+	modPath := filepath.Join(dir, "gno.mod")
+	data, err := os.ReadFile(modPath)
+	require.NoError(t, err)
+	f, err := Parse(modPath, data)
+	require.NoError(t, err)
+	return f
+}
+
+func TestReadImports(t *testing.T) {
+	imports, err := ReadImports(testModDepsDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gno.land/p/demo/dep1", "gno.land/p/demo/dep2"}, imports)
+}
+
+func TestTidy(t *testing.T) {
+	f := readTestGnoMod(t, testModDepsDir)
+	f.Tidy([]string{"gno.land/p/demo/dep1", "gno.land/p/demo/dep2", "gno.land/r/demo/moddeps"})
+	assert.Equal(t, `module gno.land/r/demo/moddeps
+
+require (
+	gno.land/p/demo/dep1 v0.0.1
+	gno.land/p/demo/dep2 v0.0.0
+)
+
+replace (
+	gno.land/p/demo/dep1 => ./deps/dep1
+	gno.land/p/demo/dep2 => ./deps/dep2
+	gno.land/p/demo/unused => ./deps/unused
+)
+`, string(f.Format()))
+	require.Len(t, f.Require, 2)
+	assert.Equal(t, "v0.0.1", f.Require[0].Mod.Version)
+
+	f = readTestGnoMod(t, testModDepsDir)
+	f.Tidy(nil)
+	assert.Empty(t, f.Require)
+	assert.NotContains(t, string(f.Format()), "require")
+}
+
+func TestGraph(t *testing.T) {
+	f := readTestGnoMod(t, testModDepsDir)
+	edges, err := f.Graph(testModDepsDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"gno.land/r/demo/moddeps", "gno.land/p/demo/dep1"},
+		{"gno.land/r/demo/moddeps", "gno.land/p/demo/unused"},
+		{"gno.land/p/demo/dep1", "gno.land/p/demo/dep2"},
+	}, edges)
+}
+
+func TestVendor(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"gno.mod", "moddeps.gno"} {
+		data, err := os.ReadFile(filepath.Join(testModDepsDir, name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o644))
+	}
+	// the replacements are relative to the module.
+	require.NoError(t, os.Symlink(filepath.Join(mustAbs(t, testModDepsDir), "deps"), filepath.Join(dir, "deps")))
+	stale := filepath.Join(dir, VendorDir, "gno.land", "p", "demo", "stale")
+	require.NoError(t, os.MkdirAll(stale, 0o755))
+
+	f := readTestGnoMod(t, dir)
+	require.NoError(t, f.Vendor(dir, ""))
+
+	for _, name := range []string{"dep1/dep1.gno", "dep2/dep2.gno", "unused/unused.gno"} {
+		got, err := os.ReadFile(filepath.Join(dir, VendorDir, "gno.land", "p", "demo", name))
+		require.NoError(t, err)
+		want, err := os.ReadFile(filepath.Join(testModDepsDir, "deps", name))
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got))
+	}
+	assert.NoDirExists(t, stale)
+
+	// the vendored packages are read when not replaced.
+	f = readTestGnoMod(t, dir)
+	f.Replace = nil
+	edges, err := f.Graph(dir, "")
+	require.NoError(t, err)
+	assert.Len(t, edges, 3)
+}
+
+func mustAbs(t *testing.T, path string) string {
+	t.Helper()
+
+	abs, err := filepath.Abs(path)
+	require.NoError(t, err)
+	return abs
+}
@@ -18,6 +18,7 @@ type Banker interface {
 	TotalCoin(denom string) int64
 	IssueCoin(addr crypto.Bech32Address, denom string, amount int64)
 	RemoveCoin(addr crypto.Bech32Address, denom string, amount int64)
+	SetDenomMetadata(denom string, symbol string, decimals uint8)
 }
 
 // Used in std.GetBanker(options).
@@ -32,7 +33,8 @@ const (
 	BankerTypeOrigSend
 	// Can send from all realm coins.
 	BankerTypeRealmSend
-	// Can issue and remove realm coins.
+	// Can send from all realm coins, and issue and remove
+	// the coins of the denoms of the realm.
 	BankerTypeRealmIssue
 )
 
@@ -67,6 +69,10 @@ func (rb ReadonlyBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amo
 	panic("ReadonlyBanker cannot remove coins")
 }
 
+func (rb ReadonlyBanker) SetDenomMetadata(denom string, symbol string, decimals uint8) {
+	panic("ReadonlyBanker cannot set denom metadata")
+}
+
 //----------------------------------------
 // OrigSendBanker
 
@@ -121,6 +127,10 @@ func (osb OrigSendBanker) RemoveCoin(addr crypto.Bech32Address, denom string, am
 	panic("OrigSendBanker cannot remove coins")
 }
 
+func (osb OrigSendBanker) SetDenomMetadata(denom string, symbol string, decimals uint8) {
+	panic("OrigSendBanker cannot set denom metadata")
+}
+
 //----------------------------------------
 // RealmSendBanker
 
@@ -160,3 +170,66 @@ func (rsb RealmSendBanker) IssueCoin(addr crypto.Bech32Address, denom string, am
 func (rsb RealmSendBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amount int64) {
 	panic("RealmSendBanker cannot remove coins")
 }
+
+func (rsb RealmSendBanker) SetDenomMetadata(denom string, symbol string, decimals uint8) {
+	panic("RealmSendBanker cannot set denom metadata")
+}
+
+//----------------------------------------
+// RealmIssueBanker
+
+// RealmIssueBanker can only issue and remove the denoms prefixed with the
+// package path of its realm, like gno.land/r/foo:token.
+type RealmIssueBanker struct {
+	banker  Banker
+	pkgAddr crypto.Bech32Address
+	pkgPath string
+}
+
+func NewRealmIssueBanker(banker Banker, pkgAddr crypto.Bech32Address, pkgPath string) RealmIssueBanker {
+	return RealmIssueBanker{
+		banker:  banker,
+		pkgAddr: pkgAddr,
+		pkgPath: pkgPath,
+	}
+}
+
+func (rib RealmIssueBanker) GetCoins(addr crypto.Bech32Address) (dst std.Coins) {
+	return rib.banker.GetCoins(addr)
+}
+
+func (rib RealmIssueBanker) SendCoins(from, to crypto.Bech32Address, amt std.Coins) {
+	if from != rib.pkgAddr {
+		panic(fmt.Sprintf(
+			"RealmIssueBanker can only send from the realm package address %q, but got %q",
+			rib.pkgAddr, from))
+	}
+	rib.banker.SendCoins(from, to, amt)
+}
+
+func (rib RealmIssueBanker) TotalCoin(denom string) int64 {
+	return rib.banker.TotalCoin(denom)
+}
+
+func (rib RealmIssueBanker) IssueCoin(addr crypto.Bech32Address, denom string, amount int64) {
+	rib.assertRealmDenom(denom)
+	rib.banker.IssueCoin(addr, denom, amount)
+}
+
+func (rib RealmIssueBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amount int64) {
+	rib.assertRealmDenom(denom)
+	rib.banker.RemoveCoin(addr, denom, amount)
+}
+
+func (rib RealmIssueBanker) SetDenomMetadata(denom string, symbol string, decimals uint8) {
+	rib.assertRealmDenom(denom)
+	rib.banker.SetDenomMetadata(denom, symbol, decimals)
+}
+
+func (rib RealmIssueBanker) assertRealmDenom(denom string) {
+	if std.DenomIssuer(denom) != rib.pkgPath {
+		panic(fmt.Sprintf(
+			"RealmIssueBanker can only use the denoms of the realm %q, like \"%s:token\", but got %q",
+			rib.pkgPath, rib.pkgPath, denom))
+	}
+}
@@ -17,4 +17,10 @@ type ExecContext struct {
 	OrigSend      std.Coins
 	OrigSendSpent *std.Coins // mutable
 	Banker        Banker
+	EventLogger   *sdk.EventLogger // mutable, or nil to discard events
+
+	// gas consumed by the natives verifying signatures, if the machine
+	// has a GasMeter; see auth.Params.
+	SigVerifyCostED25519   int64
+	SigVerifyCostSecp256k1 int64
 }
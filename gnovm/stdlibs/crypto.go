@@ -0,0 +1,111 @@
+package stdlibs
+
+import (
+	"github.com/btcsuite/btcd/btcec"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+)
+
+// getBytes returns the bytes of the []byte tv, which may be nil.
+func getBytes(m *gno.Machine, tv *gno.TypedValue) []byte {
+	if tv.V == nil {
+		return nil
+	}
+	slice := tv.V.(*gno.SliceValue)
+	array := slice.GetBase(m.Store)
+	return array.GetReadonlyBytes()[slice.Offset : slice.Offset+slice.Length]
+}
+
+// sigVerifyCost returns the gas to verify a signature of pubKey, as charged
+// by auth.DefaultSigVerificationGasConsumer.
+func sigVerifyCost(ctx ExecContext, pubKey crypto.PubKey) int64 {
+	switch pubKey := pubKey.(type) {
+	case ed25519.PubKeyEd25519:
+		return ctx.SigVerifyCostED25519
+	case secp256k1.PubKeySecp256k1:
+		return ctx.SigVerifyCostSecp256k1
+	case multisig.PubKeyMultisigThreshold:
+		var cost int64
+		for _, pk := range pubKey.PubKeys {
+			cost += sigVerifyCost(ctx, pk)
+		}
+		return cost
+	default:
+		return 0
+	}
+}
+
+// consumeSigVerifyGas consumes the gas to verify a signature of pubKey, if
+// the machine has a GasMeter.
+func consumeSigVerifyGas(m *gno.Machine, pubKey crypto.PubKey) {
+	ctx, ok := m.Context.(ExecContext)
+	if !ok || m.GasMeter == nil {
+		return
+	}
+	// panics with store.OutOfGasException if out of gas.
+	m.GasMeter.ConsumeGas(sigVerifyCost(ctx, pubKey), "SigVerify")
+}
+
+// verifyEd25519 reports whether sig is the ed25519 signature of msg by
+// pubKey.
+func verifyEd25519(m *gno.Machine, pubKey, msg, sig []byte) bool {
+	var pk ed25519.PubKeyEd25519
+	consumeSigVerifyGas(m, pk)
+	if len(pubKey) != len(pk) {
+		return false
+	}
+	copy(pk[:], pubKey)
+	return pk.VerifyBytes(msg, sig)
+}
+
+// verifySecp256k1 reports whether sig, in the 64 bytes R || S form, is the
+// secp256k1 signature of the sha256 of msg by the compressed pubKey, as
+// signed by the keys of tm2.
+func verifySecp256k1(m *gno.Machine, pubKey, msg, sig []byte) bool {
+	var pk secp256k1.PubKeySecp256k1
+	consumeSigVerifyGas(m, pk)
+	if len(pubKey) != len(pk) {
+		return false
+	}
+	copy(pk[:], pubKey)
+	return pk.VerifyBytes(msg, sig)
+}
+
+// recoverSecp256k1 returns the compressed public key which signed hash with
+// sig, in the 65 bytes R || S || V form with V in 0, 1, 27 or 28.
+func recoverSecp256k1(m *gno.Machine, hash, sig []byte) ([]byte, bool) {
+	consumeSigVerifyGas(m, secp256k1.PubKeySecp256k1{})
+	if len(hash) != 32 || len(sig) != 65 {
+		return nil, false
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, false
+	}
+	// btcec expects 27 + V + 4 (for a compressed key) || R || S.
+	compact := make([]byte, 65)
+	compact[0] = 27 + 4 + v
+	copy(compact[1:], sig[:64])
+	pub, _, err := btcec.RecoverCompact(btcec.S256(), compact, hash)
+	if err != nil {
+		return nil, false
+	}
+	return pub.SerializeCompressed(), true
+}
+
+// verifySignature reports whether sig is the signature of msg by the bech32
+// pubKey, of any type of tm2.
+func verifySignature(m *gno.Machine, pubKey string, msg, sig []byte) bool {
+	pk, err := crypto.PubKeyFromBech32(pubKey)
+	if err != nil {
+		return false
+	}
+	consumeSigVerifyGas(m, pk)
+	return pk.VerifyBytes(msg, sig)
+}
@@ -0,0 +1,61 @@
+package stdlibs
+
+import (
+	"fmt"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
+
+// reservedEventTypes are the event types realms may not emit; the tx
+// indexer reserves their attributes, e.g. "tx.height" and "tx.hash".
+var reservedEventTypes = map[string]struct{}{
+	"tx": {},
+}
+
+// GnoEvent is the event emitted by realms and packages through std.Emit.
+// Its attributes are indexed by the tx indexer as "<Type>.<Key>".
+type GnoEvent struct {
+	Type       string                `json:"type"`
+	PkgPath    string                `json:"pkg_path"` // emitter
+	Attributes []abci.EventAttribute `json:"attrs"`
+}
+
+var _ abci.AttributedEvent = GnoEvent{}
+
+func (GnoEvent) AssertABCIEvent() {}
+
+func (ev GnoEvent) EventType() string {
+	return ev.Type
+}
+
+func (ev GnoEvent) EventAttributes() []abci.EventAttribute {
+	return ev.Attributes
+}
+
+// NewGnoEvent returns a new GnoEvent from a flat list of key/value pairs.
+func NewGnoEvent(pkgPath, typ string, attrs []string) GnoEvent {
+	if typ == "" {
+		panic("event type cannot be empty")
+	}
+	if _, ok := reservedEventTypes[typ]; ok {
+		panic(fmt.Sprintf("event type %q is reserved", typ))
+	}
+	if len(attrs)%2 != 0 {
+		panic("event attributes must be key/value pairs")
+	}
+	ev := GnoEvent{
+		Type:       typ,
+		PkgPath:    pkgPath,
+		Attributes: make([]abci.EventAttribute, 0, len(attrs)/2),
+	}
+	for i := 0; i < len(attrs); i += 2 {
+		if attrs[i] == "" {
+			panic("event attribute key cannot be empty")
+		}
+		ev.Attributes = append(ev.Attributes, abci.EventAttribute{
+			Key:   attrs[i],
+			Value: attrs[i+1],
+		})
+	}
+	return ev
+}
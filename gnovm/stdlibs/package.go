@@ -0,0 +1,16 @@
+package stdlibs
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/gnovm/stdlibs",
+	"stdlibs",
+	amino.GetCallersDirname(),
+).WithDependencies(
+	abci.Package,
+).WithTypes(
+	GnoEvent{}, "GnoEvent",
+))
@@ -0,0 +1,380 @@
+package stdlibs
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// The reflect natives pass types and values to and from Gno as pointers,
+// which are ordinary (and persistable) Gno values: a type T is passed as
+// a nil *T, and a value of type T as a *T pointing to it.
+
+// Kinds of reflect/type.gno.
+const (
+	reflectInvalid = iota
+	reflectBool
+	reflectInt
+	reflectInt8
+	reflectInt16
+	reflectInt32
+	reflectInt64
+	reflectUint
+	reflectUint8
+	reflectUint16
+	reflectUint32
+	reflectUint64
+	reflectFloat32
+	reflectFloat64
+	reflectArray
+	reflectChan
+	reflectFunc
+	reflectInterface
+	reflectMap
+	reflectPointer
+	reflectSlice
+	reflectString
+	reflectStruct
+)
+
+// reflectKind returns the reflect kind of t.
+func reflectKind(t gno.Type) int {
+	switch t.Kind() {
+	case gno.BoolKind:
+		return reflectBool
+	case gno.IntKind:
+		return reflectInt
+	case gno.Int8Kind:
+		return reflectInt8
+	case gno.Int16Kind:
+		return reflectInt16
+	case gno.Int32Kind:
+		return reflectInt32
+	case gno.Int64Kind:
+		return reflectInt64
+	case gno.UintKind:
+		return reflectUint
+	case gno.Uint8Kind:
+		return reflectUint8
+	case gno.Uint16Kind:
+		return reflectUint16
+	case gno.Uint32Kind:
+		return reflectUint32
+	case gno.Uint64Kind:
+		return reflectUint64
+	case gno.Float32Kind:
+		return reflectFloat32
+	case gno.Float64Kind:
+		return reflectFloat64
+	case gno.ArrayKind:
+		return reflectArray
+	case gno.ChanKind:
+		return reflectChan
+	case gno.FuncKind:
+		return reflectFunc
+	case gno.InterfaceKind:
+		return reflectInterface
+	case gno.MapKind:
+		return reflectMap
+	case gno.PointerKind:
+		return reflectPointer
+	case gno.SliceKind:
+		return reflectSlice
+	case gno.StringKind:
+		return reflectString
+	case gno.StructKind:
+		return reflectStruct
+	default:
+		return reflectInvalid
+	}
+}
+
+// reflectPointerType returns the type *t. Native types are not
+// supported, as they are not deterministic.
+func reflectPointerType(m *gno.Machine, t gno.Type) gno.Type {
+	if _, ok := gno.BaseOf(t).(*gno.NativeType); ok {
+		panic(fmt.Sprintf("reflect: native type %s is not supported", t.String()))
+	}
+	return m.Alloc.NewType(&gno.PointerType{Elt: t})
+}
+
+// reflectTypeOf returns the nil *t passed for the type t, or the nil
+// interface if t is nil.
+func reflectTypeOf(m *gno.Machine, t gno.Type) gno.TypedValue {
+	if t == nil {
+		return gno.TypedValue{}
+	}
+	return typedNil(reflectPointerType(m, t))
+}
+
+// reflectType returns the type t of tv, a *t passed for a type or value.
+func reflectType(tv *gno.TypedValue) gno.Type {
+	return tv.T.(*gno.PointerType).Elt
+}
+
+// reflectNew returns a *t pointing to a new variable holding tv.
+func reflectNew(m *gno.Machine, t gno.Type, tv gno.TypedValue) gno.TypedValue {
+	av := m.Alloc.NewListArray(1)
+	av.List[0] = tv
+	return gno.TypedValue{
+		T: reflectPointerType(m, t),
+		V: gno.PointerValue{TV: &av.List[0], Base: av, Index: 0},
+	}
+}
+
+// reflectDeref returns the value tv, a *t passed for a value, points to.
+func reflectDeref(tv *gno.TypedValue) gno.TypedValue {
+	return tv.V.(gno.PointerValue).Deref()
+}
+
+// reflectName returns the name of t, or "" if t is not named.
+func reflectName(t gno.Type) string {
+	switch t := t.(type) {
+	case *gno.DeclaredType:
+		return string(t.Name)
+	case gno.PrimitiveType:
+		return t.String()
+	default:
+		return ""
+	}
+}
+
+// reflectPredeclared reports whether t is a predeclared type declared in
+// the (hidden) uverse package, like error.
+func reflectPredeclared(t gno.Type) bool {
+	dt, ok := t.(*gno.DeclaredType)
+	return ok && dt.PkgPath == ".uverse"
+}
+
+// reflectPkgPath returns the package path of t, or "" if t is not a
+// declared type or is predeclared.
+func reflectPkgPath(t gno.Type) string {
+	if dt, ok := t.(*gno.DeclaredType); ok && !reflectPredeclared(dt) {
+		return dt.PkgPath
+	}
+	return ""
+}
+
+// reflectTypeString returns the string representation of t.
+func reflectTypeString(t gno.Type) string {
+	// predeclared types are qualified with ".uverse" by the VM.
+	return strings.ReplaceAll(t.String(), ".uverse.", "")
+}
+
+// reflectElem returns the element type of t.
+func reflectElem(t gno.Type) gno.Type {
+	switch bt := gno.BaseOf(t).(type) {
+	case *gno.ArrayType:
+		return bt.Elt
+	case *gno.ChanType:
+		return bt.Elt
+	case *gno.MapType:
+		return bt.Value
+	case *gno.PointerType:
+		return bt.Elt
+	case *gno.SliceType:
+		return bt.Elt
+	default:
+		panic(fmt.Sprintf("reflect: Elem of invalid type %s", t.String()))
+	}
+}
+
+// reflectStructType returns the base of t, which must be a struct type.
+func reflectStructType(t gno.Type, method string) *gno.StructType {
+	st, ok := gno.BaseOf(t).(*gno.StructType)
+	if !ok {
+		panic(fmt.Sprintf("reflect: %s of non-struct type %s", method, t.String()))
+	}
+	return st
+}
+
+// reflectFieldPkgPath returns the package path of the field ft of st, or
+// "" if ft is exported.
+func reflectFieldPkgPath(st *gno.StructType, ft gno.FieldType) string {
+	r, _ := utf8.DecodeRuneInString(string(ft.Name))
+	if unicode.IsUpper(r) {
+		return ""
+	}
+	return st.PkgPath
+}
+
+// reflectMapType returns the base of t, which must be a map type.
+func reflectMapType(t gno.Type, method string) *gno.MapType {
+	mt, ok := gno.BaseOf(t).(*gno.MapType)
+	if !ok {
+		panic(fmt.Sprintf("reflect: %s of non-map type %s", method, t.String()))
+	}
+	return mt
+}
+
+// reflectIsNamed reports whether t is a named type.
+func reflectIsNamed(t gno.Type) bool {
+	switch t.(type) {
+	case *gno.DeclaredType, gno.PrimitiveType:
+		return true
+	default:
+		return false
+	}
+}
+
+// reflectAssignable reports whether a value of type xt is assignable to
+// type dt.
+func reflectAssignable(xt, dt gno.Type) bool {
+	if xt.TypeID() == dt.TypeID() {
+		return true
+	}
+	if dt.Kind() == gno.InterfaceKind {
+		return gno.IsImplementedBy(dt, xt)
+	}
+	if reflectIsNamed(xt) && reflectIsNamed(dt) {
+		return false
+	}
+	return gno.BaseOf(xt).TypeID() == gno.BaseOf(dt).TypeID()
+}
+
+// reflectAssignValue returns a copy of the value x points to, to be
+// assigned to a variable of type dt.
+func reflectAssignValue(m *gno.Machine, method string, x *gno.TypedValue, dt gno.Type) gno.TypedValue {
+	xt := reflectType(x)
+	if !reflectAssignable(xt, dt) {
+		panic(fmt.Sprintf("reflect.%s: value of type %s is not assignable to type %s",
+			method, xt.String(), dt.String()))
+	}
+	tv := reflectDeref(x).Copy(m.Alloc)
+	if dt.Kind() != gno.InterfaceKind {
+		tv.T = dt
+	}
+	return tv
+}
+
+// reflectCheckUpdate panics if po may not be updated, by the same
+// readonly and realm ownership rules as assignments.
+func reflectCheckUpdate(m *gno.Machine, po gno.Object) {
+	// XXX HACK (until value persistence impl'd), as in doOpAssign.
+	if m.ReadOnly && po.GetIsReal() {
+		panic("readonly violation")
+	}
+	m.Realm.DidUpdate(po, nil, nil)
+}
+
+// reflectAssign assigns tv to the variable pv points to. The update is
+// checked first, as Assign2 sets data bytes without a realm update.
+func reflectAssign(m *gno.Machine, pv gno.PointerValue, tv gno.TypedValue) {
+	if pv.Base != nil {
+		reflectCheckUpdate(m, pv.Base.(gno.Object))
+	}
+	pv.Assign2(m.Alloc, m.Store, m.Realm, tv, true)
+}
+
+// reflectConvert returns the value pointed to by tv converted to type t.
+func reflectConvert(m *gno.Machine, tv *gno.TypedValue, t gno.Type) gno.TypedValue {
+	val := reflectDeref(tv)
+	gno.ConvertTo(m.Alloc, m.Store, &val, t)
+	return val
+}
+
+// reflectSetConverted sets the value pointed to by tv, a *t passed for a
+// value, to x converted to type t.
+func reflectSetConverted(m *gno.Machine, tv *gno.TypedValue, x gno.TypedValue) {
+	gno.ConvertTo(m.Alloc, m.Store, &x, reflectType(tv))
+	reflectAssign(m, tv.V.(gno.PointerValue), x)
+}
+
+// reflectIndex returns the i'th element of the value tv, a *t passed
+// for an array, slice or string value.
+func reflectIndex(m *gno.Machine, tv *gno.TypedValue, i int) gno.TypedValue {
+	t := reflectType(tv)
+	val := reflectDeref(tv)
+	if i < 0 || i >= val.GetLength() {
+		panic("reflect: index out of range")
+	}
+	switch bt := gno.BaseOf(t).(type) {
+	case *gno.ArrayType:
+		av := val.V.(*gno.ArrayValue)
+		return gno.TypedValue{
+			T: reflectPointerType(m, bt.Elt),
+			V: av.GetPointerAtIndexInt2(m.Store, i, bt.Elt),
+		}
+	case *gno.SliceType:
+		sv := val.V.(*gno.SliceValue)
+		return gno.TypedValue{
+			T: reflectPointerType(m, bt.Elt),
+			V: sv.GetPointerAtIndexInt2(m.Store, i, bt.Elt),
+		}
+	case gno.PrimitiveType:
+		if bt.Kind() == gno.StringKind {
+			b := gno.TypedValue{T: gno.Uint8Type}
+			b.SetUint8(val.GetString()[i])
+			return reflectNew(m, gno.Uint8Type, b)
+		}
+	}
+	panic(fmt.Sprintf("reflect: Index of invalid type %s", t.String()))
+}
+
+// reflectMapKeys returns the keys of the value tv, a *t passed for a map
+// value, in the order of iteration of range.
+func reflectMapKeys(m *gno.Machine, tv *gno.TypedValue) gno.TypedValue {
+	mt := reflectMapType(reflectType(tv), "MapKeys")
+	val := reflectDeref(tv)
+	var keys []gno.TypedValue
+	if val.V != nil {
+		mv := val.V.(*gno.MapValue)
+		keys = make([]gno.TypedValue, 0, mv.GetLength())
+		for item := mv.List.Head; item != nil; item = item.Next {
+			key := gno.FillValueTV(m.Store, &item.Key).Copy(m.Alloc)
+			keys = append(keys, reflectNew(m, mt.Key, key))
+		}
+	}
+	return gno.TypedValue{
+		T: m.Alloc.NewType(&gno.SliceType{Elt: &gno.InterfaceType{}}),
+		V: m.Alloc.NewSliceFromList(keys),
+	}
+}
+
+// reflectMapIndex returns the value of the key k in the value tv, a *t
+// passed for a map value, or the nil interface if there is none.
+func reflectMapIndex(m *gno.Machine, tv, k *gno.TypedValue) gno.TypedValue {
+	mt := reflectMapType(reflectType(tv), "MapIndex")
+	key := reflectAssignValue(m, "Value.MapIndex", k, mt.Key)
+	val := reflectDeref(tv)
+	if val.V == nil {
+		return gno.TypedValue{}
+	}
+	elem, ok := val.V.(*gno.MapValue).GetValueForKey(m.Store, &key)
+	if !ok {
+		return gno.TypedValue{}
+	}
+	return reflectNew(m, mt.Value, elem.Copy(m.Alloc))
+}
+
+// reflectSetMapIndex sets the value of the key k in the value tv, a *t
+// passed for a map value, to the value e points to, or deletes the key
+// if e is nil.
+func reflectSetMapIndex(m *gno.Machine, tv, k, e *gno.TypedValue) {
+	mt := reflectMapType(reflectType(tv), "SetMapIndex")
+	key := reflectAssignValue(m, "Value.SetMapIndex", k, mt.Key)
+	val := reflectDeref(tv)
+	if val.V == nil {
+		if e.IsUndefined() {
+			return
+		}
+		panic("assignment to entry in nil map")
+	}
+	mv := val.V.(*gno.MapValue)
+	reflectCheckUpdate(m, mv)
+	if e.IsUndefined() {
+		elem, ok := mv.GetValueForKey(m.Store, &key)
+		if !ok {
+			return
+		}
+		m.Realm.DidUpdate(mv, elem.GetFirstObject(m.Store), nil)
+		mv.DeleteForKey(m.Store, &key)
+		return
+	}
+	elem := reflectAssignValue(m, "Value.SetMapIndex", e, mt.Value)
+	pv := mv.GetPointerForKey(m.Alloc, m.Store, &key)
+	pv.Assign2(m.Alloc, m.Store, m.Realm, elem, true)
+}
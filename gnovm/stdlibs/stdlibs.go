@@ -2,6 +2,7 @@ package stdlibs
 
 import (
 	"crypto/sha256"
+	"fmt"
 	"math"
 	"reflect"
 	"strconv"
@@ -11,6 +12,8 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bech32"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck
+	"golang.org/x/crypto/sha3"
 )
 
 func InjectNativeMappings(store gno.Store) {
@@ -48,6 +51,105 @@ func InjectPackage(store gno.Store, pn *gno.PackageNode) {
 				m.PushValue(res0)
 			},
 		)
+	case "internal/crypto/ed25519":
+		pn.DefineNative("Verify",
+			gno.Flds( // params
+				"publicKey", "[]byte",
+				"message", "[]byte",
+				"sig", "[]byte",
+			),
+			gno.Flds( // results
+				"", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0, arg1, arg2 := m.LastBlock().GetParams3()
+				ok := verifyEd25519(m,
+					getBytes(m, arg0.TV), getBytes(m, arg1.TV), getBytes(m, arg2.TV))
+				m.PushValue(typedBool(ok))
+			},
+		)
+	case "internal/crypto/secp256k1":
+		pn.DefineNative("Verify",
+			gno.Flds( // params
+				"publicKey", "[]byte",
+				"message", "[]byte",
+				"sig", "[]byte",
+			),
+			gno.Flds( // results
+				"", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0, arg1, arg2 := m.LastBlock().GetParams3()
+				ok := verifySecp256k1(m,
+					getBytes(m, arg0.TV), getBytes(m, arg1.TV), getBytes(m, arg2.TV))
+				m.PushValue(typedBool(ok))
+			},
+		)
+		pn.DefineNative("RecoverPubKey",
+			gno.Flds( // params
+				"hash", "[]byte",
+				"sig", "[]byte",
+			),
+			gno.Flds( // results
+				"publicKey", "[]byte",
+				"ok", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				pubKey, ok := recoverSecp256k1(m, getBytes(m, arg0.TV), getBytes(m, arg1.TV))
+				res0 := gno.Go2GnoValue(
+					m.Alloc,
+					m.Store,
+					reflect.ValueOf(pubKey),
+				)
+				m.PushValue(res0)
+				m.PushValue(typedBool(ok))
+			},
+		)
+	case "internal/crypto/keccak256":
+		pn.DefineNative("Sum256",
+			gno.Flds( // params
+				"data", "[]byte",
+			),
+			gno.Flds( // results
+				"bz", "[32]byte",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				hasher := sha3.NewLegacyKeccak256()
+				hasher.Write(getBytes(m, arg0))
+				var hash [32]byte
+				copy(hash[:], hasher.Sum(nil))
+				res0 := gno.Go2GnoValue(
+					m.Alloc,
+					m.Store,
+					reflect.ValueOf(hash),
+				)
+				m.PushValue(res0)
+			},
+		)
+	case "internal/crypto/ripemd160":
+		pn.DefineNative("Sum160",
+			gno.Flds( // params
+				"data", "[]byte",
+			),
+			gno.Flds( // results
+				"bz", "[20]byte",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				hasher := ripemd160.New() //nolint:staticcheck
+				hasher.Write(getBytes(m, arg0))
+				var hash [20]byte
+				copy(hash[:], hasher.Sum(nil))
+				res0 := gno.Go2GnoValue(
+					m.Alloc,
+					m.Store,
+					reflect.ValueOf(hash),
+				)
+				m.PushValue(res0)
+			},
+		)
 	case "internal/math":
 		pn.DefineNative("Float32bits",
 			gno.Flds( // params
@@ -131,16 +233,541 @@ func InjectPackage(store gno.Store, pn *gno.PackageNode) {
 		)
 	// case "internal/os_test":
 	// XXX defined in tests/imports.go
+	case "internal/testing":
+		pn.DefineNative("MachineStats",
+			gno.Flds( // params
+			),
+			gno.Flds( // results
+				"cycles", "int64",
+				"allocBytes", "int64",
+			),
+			func(m *gno.Machine) {
+				// allocBytes is cumulative, and zero if
+				// allocations are not tracked.
+				var allocBytes int64
+				if m.Alloc != nil {
+					_, allocBytes = m.Alloc.Status()
+				}
+				res0 := typedInt64(m.Cycles)
+				res1 := typedInt64(allocBytes)
+				m.PushValue(res0)
+				m.PushValue(res1)
+			},
+		)
+	case "internal/reflect":
+		pn.DefineNative("TypeOf",
+			gno.Flds( // params
+				"x", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := reflectTypeOf(m, arg0.T)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypeKind",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "int",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := gno.TypedValue{T: gno.IntType}
+				res0.SetInt(reflectKind(reflectType(arg0)))
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypeName",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "string",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := typedString(gno.StringValue(reflectName(reflectType(arg0))))
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypePkgPath",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "string",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := typedString(gno.StringValue(reflectPkgPath(reflectType(arg0))))
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypeString",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "string",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := typedString(gno.StringValue(reflectTypeString(reflectType(arg0))))
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypeElem",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := reflectTypeOf(m, reflectElem(reflectType(arg0)))
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypeKey",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				mt := reflectMapType(reflectType(arg0), "Key")
+				res0 := reflectTypeOf(m, mt.Key)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypeLen",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "int",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				at, ok := gno.BaseOf(reflectType(arg0)).(*gno.ArrayType)
+				if !ok {
+					panic("reflect: Len of non-array type")
+				}
+				res0 := gno.TypedValue{T: gno.IntType}
+				res0.SetInt(at.Len)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypeNumField",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "int",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				st := reflectStructType(reflectType(arg0), "NumField")
+				res0 := gno.TypedValue{T: gno.IntType}
+				res0.SetInt(len(st.Fields))
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("TypeField",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+				"i", "int",
+			),
+			gno.Flds( // results
+				"name", "string",
+				"pkgPath", "string",
+				"typ", gno.AnyT(),
+				"tag", "string",
+				"embedded", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				st := reflectStructType(reflectType(arg0.TV), "Field")
+				ft := st.Fields[arg1.TV.GetInt()]
+				m.PushValue(typedString(gno.StringValue(ft.Name)))
+				m.PushValue(typedString(gno.StringValue(reflectFieldPkgPath(st, ft))))
+				m.PushValue(reflectTypeOf(m, ft.Type))
+				m.PushValue(typedString(gno.StringValue(ft.Tag)))
+				m.PushValue(typedBool(ft.Embedded))
+			},
+		)
+		pn.DefineNative("TypeAssignableTo",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+				"u", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				ok := reflectAssignable(reflectType(arg0.TV), reflectType(arg1.TV))
+				m.PushValue(typedBool(ok))
+			},
+		)
+		pn.DefineNative("TypeImplements",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+				"u", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				ok := gno.IsImplementedBy(reflectType(arg1.TV), reflectType(arg0.TV))
+				m.PushValue(typedBool(ok))
+			},
+		)
+		pn.DefineNative("ValueOf",
+			gno.Flds( // params
+				"x", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				if arg0.T == nil {
+					m.PushValue(gno.TypedValue{})
+					return
+				}
+				res0 := reflectNew(m, arg0.T, arg0.Copy(m.Alloc))
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("Zero",
+			gno.Flds( // params
+				"t", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				t := reflectType(arg0)
+				res0 := reflectNew(m, t, gno.DefaultTypedValue(m.Alloc, t))
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("Interface",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := reflectDeref(arg0).Copy(m.Alloc)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("Elem",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				t := reflectType(arg0)
+				val := reflectDeref(arg0)
+				switch bt := gno.BaseOf(t).(type) {
+				case *gno.InterfaceType:
+					if val.T == nil {
+						m.PushValue(gno.TypedValue{})
+						return
+					}
+					m.PushValue(reflectNew(m, val.T, val.Copy(m.Alloc)))
+				case *gno.PointerType:
+					if val.V == nil {
+						m.PushValue(gno.TypedValue{})
+						return
+					}
+					m.PushValue(gno.TypedValue{T: reflectPointerType(m, bt.Elt), V: val.V})
+				default:
+					panic(fmt.Sprintf("reflect: Elem of invalid type %s", t.String()))
+				}
+			},
+		)
+		pn.DefineNative("Field",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"i", "int",
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				st := reflectStructType(reflectType(arg0.TV), "Field")
+				i := arg1.TV.GetInt()
+				sv := reflectDeref(arg0.TV).V.(*gno.StructValue)
+				res0 := gno.TypedValue{
+					T: reflectPointerType(m, st.Fields[i].Type),
+					V: sv.GetPointerToInt(m.Store, i),
+				}
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("Index",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"i", "int",
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				res0 := reflectIndex(m, arg0.TV, arg1.TV.GetInt())
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("Len",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "int",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				val := reflectDeref(arg0)
+				res0 := gno.TypedValue{T: gno.IntType}
+				if val.T.Kind() != gno.MapKind || val.V != nil {
+					res0.SetInt(val.GetLength())
+				}
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("IsNil",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				val := reflectDeref(arg0)
+				if reflectType(arg0).Kind() == gno.InterfaceKind {
+					m.PushValue(typedBool(val.T == nil))
+				} else {
+					m.PushValue(typedBool(val.V == nil))
+				}
+			},
+		)
+		pn.DefineNative("MapKeys",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.SliceT(gno.AnyT()),
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := reflectMapKeys(m, arg0)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("MapIndex",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"key", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", gno.AnyT(),
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				res0 := reflectMapIndex(m, arg0.TV, arg1.TV)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("SetMapIndex",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"key", gno.AnyT(),
+				"elem", gno.AnyT(),
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				arg0, arg1, arg2 := m.LastBlock().GetParams3()
+				reflectSetMapIndex(m, arg0.TV, arg1.TV, arg2.TV)
+			},
+		)
+		pn.DefineNative("Set",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"x", gno.AnyT(),
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				tv := reflectAssignValue(m, "Set", arg1.TV, reflectType(arg0.TV))
+				reflectAssign(m, arg0.TV.V.(gno.PointerValue), tv)
+			},
+		)
+		pn.DefineNative("Bool",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				val := reflectDeref(arg0)
+				m.PushValue(typedBool(val.GetBool()))
+			},
+		)
+		pn.DefineNative("Int",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "int64",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := reflectConvert(m, arg0, gno.Int64Type)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("Uint",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "uint64",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := reflectConvert(m, arg0, gno.Uint64Type)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("Float",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "float64",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := reflectConvert(m, arg0, gno.Float64Type)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("String",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+			),
+			gno.Flds( // results
+				"", "string",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				res0 := reflectConvert(m, arg0, gno.StringType)
+				m.PushValue(res0)
+			},
+		)
+		pn.DefineNative("SetBool",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"x", "bool",
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				reflectSetConverted(m, arg0.TV, *arg1.TV)
+			},
+		)
+		pn.DefineNative("SetInt",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"x", "int64",
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				reflectSetConverted(m, arg0.TV, *arg1.TV)
+			},
+		)
+		pn.DefineNative("SetUint",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"x", "uint64",
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				reflectSetConverted(m, arg0.TV, *arg1.TV)
+			},
+		)
+		pn.DefineNative("SetFloat",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"x", "float64",
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				reflectSetConverted(m, arg0.TV, *arg1.TV)
+			},
+		)
+		pn.DefineNative("SetString",
+			gno.Flds( // params
+				"v", gno.AnyT(),
+				"x", "string",
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				reflectSetConverted(m, arg0.TV, *arg1.TV)
+			},
+		)
 	case "strconv":
 		pn.DefineGoNativeValue("Itoa", strconv.Itoa)
 		pn.DefineGoNativeValue("Atoi", strconv.Atoi)
 		pn.DefineGoNativeValue("FormatInt", strconv.FormatInt)
 		pn.DefineGoNativeValue("FormatUint", strconv.FormatUint)
 		pn.DefineGoNativeValue("Quote", strconv.Quote)
+		pn.DefineGoNativeValue("Unquote", strconv.Unquote)
 		pn.DefineGoNativeValue("QuoteToASCII", strconv.QuoteToASCII)
 		pn.DefineGoNativeValue("CanBackquote", strconv.CanBackquote)
 		pn.DefineGoNativeValue("IntSize", strconv.IntSize)
 		pn.DefineGoNativeValue("AppendUint", strconv.AppendUint)
+		pn.DefineGoNativeValue("FormatFloat", strconv.FormatFloat)
+		pn.DefineGoNativeValue("FormatBool", strconv.FormatBool)
+		pn.DefineGoNativeValue("ParseInt", strconv.ParseInt)
+		pn.DefineGoNativeValue("ParseUint", strconv.ParseUint)
+		pn.DefineGoNativeValue("ParseFloat", strconv.ParseFloat)
+		pn.DefineGoNativeValue("ParseBool", strconv.ParseBool)
 	case "std":
 		// NOTE: some of these are overridden in tests/imports_test.go
 		// Also see stdlibs/InjectPackage.
@@ -360,7 +987,12 @@ func InjectPackage(store gno.Store, pn *gno.PackageNode) {
 				case BankerTypeRealmSend:
 					banker = NewRealmSendBanker(banker, ctx.OrigPkgAddr)
 				case BankerTypeRealmIssue:
-					banker = banker
+					if m.Realm == nil || !gno.IsRealmPath(m.Realm.Path) {
+						panic("RealmIssueBanker can only be used by a realm")
+					}
+					pkgPath := m.Realm.Path
+					pkgAddr := gno.DerivePkgAddr(pkgPath).Bech32()
+					banker = NewRealmIssueBanker(banker, pkgAddr, pkgPath)
 				default:
 					panic("should not happen") // defensive
 				}
@@ -462,6 +1094,72 @@ func InjectPackage(store gno.Store, pn *gno.PackageNode) {
 				}
 			},
 		)
+		pn.DefineNative("Emit",
+			gno.Flds( // params
+				"typ", "string",
+				"attrs", gno.Vrd("string"),
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				arg0, arg1 := m.LastBlock().GetParams2()
+				typ := arg0.TV.GetString()
+				var attrs []string
+				if arg1.TV.V != nil {
+					slice := arg1.TV.V.(*gno.SliceValue)
+					av := slice.GetBase(m.Store)
+					for i := 0; i < slice.Length; i++ {
+						attrs = append(attrs, av.List[slice.Offset+i].GetString())
+					}
+				}
+				// the package calling std.Emit.
+				pkgPath := m.LastCallFrame(1).LastPackage.PkgPath
+				ev := NewGnoEvent(pkgPath, typ, attrs)
+				if ctx, ok := m.Context.(ExecContext); ok && ctx.EventLogger != nil {
+					ctx.EventLogger.EmitEvent(ev)
+				}
+			},
+		)
+		pn.DefineNative("VerifySignature",
+			gno.Flds( // params
+				"pubKey", "string",
+				"msg", "[]byte",
+				"sig", "[]byte",
+			),
+			gno.Flds( // results
+				"", "bool",
+			),
+			func(m *gno.Machine) {
+				arg0, arg1, arg2 := m.LastBlock().GetParams3()
+				ok := verifySignature(m,
+					arg0.TV.GetString(), getBytes(m, arg1.TV), getBytes(m, arg2.TV))
+				m.PushValue(typedBool(ok))
+			},
+		)
+		pn.DefineNative("PubKeyToAddress",
+			gno.Flds( // params
+				"pubKey", "string",
+			),
+			gno.Flds( // results
+				"addr", "Address",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				pubKey, err := crypto.PubKeyFromBech32(arg0.GetString())
+				if err != nil {
+					panic(err)
+				}
+				addr := pubKey.Address().Bech32()
+				res0 := gno.Go2GnoValue(
+					m.Alloc,
+					m.Store,
+					reflect.ValueOf(addr),
+				)
+				addrT := store.GetType(gno.DeclaredTypeID("std", "Address"))
+				res0.T = addrT
+				m.PushValue(res0)
+			},
+		)
 		pn.DefineNative("DerivePkgAddr",
 			gno.Flds( // params
 				"pkgPath", "string",
@@ -64,6 +64,7 @@ type runFileTestOptions struct {
 	nativeLibs bool
 	logger     loggerFunc
 	syncWanted bool
+	coverage   *gno.Coverage
 }
 
 // RunFileTestOptions specify changing options in [RunFileTest], deviating
@@ -88,6 +89,11 @@ func WithSyncWanted(v bool) RunFileTestOption {
 	return func(r *runFileTestOptions) { r.syncWanted = v }
 }
 
+// WithCoverage records the statements executed by the test in c.
+func WithCoverage(c *gno.Coverage) RunFileTestOption {
+	return func(r *runFileTestOptions) { r.coverage = c }
+}
+
 // RunFileTest executes the filetest at the given path, using rootDir as
 // the directory where to find the "stdlibs" directory.
 func RunFileTest(rootDir string, path string, opts ...RunFileTestOption) error {
@@ -111,6 +117,7 @@ func RunFileTest(rootDir string, path string, opts ...RunFileTestOption) error {
 	store := TestStore(rootDir, "./files", stdin, stdout, stderr, mode)
 	store.SetLogStoreOps(true)
 	m := testMachineCustom(store, pkgPath, stdout, maxAlloc, send)
+	m.Coverage = f.coverage
 
 	// TODO support stdlib groups, but make testing safe;
 	// e.g. not be able to make network connections.
@@ -506,7 +513,11 @@ func (tb *testBanker) SendCoins(from, to crypto.Bech32Address, amt std.Coins) {
 }
 
 func (tb *testBanker) TotalCoin(denom string) int64 {
-	panic("not yet implemented")
+	var total int64
+	for _, coins := range tb.coinTable {
+		total += coins.AmountOf(denom)
+	}
+	return total
 }
 
 func (tb *testBanker) IssueCoin(addr crypto.Bech32Address, denom string, amt int64) {
@@ -520,3 +531,7 @@ func (tb *testBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amt in
 	rest := coins.Sub(std.Coins{{denom, amt}})
 	tb.coinTable[addr] = rest
 }
+
+func (tb *testBanker) SetDenomMetadata(denom string, symbol string, decimals uint8) {
+	// metadata is not queryable in tests.
+}
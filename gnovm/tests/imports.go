@@ -459,10 +459,9 @@ func testPackageInjector(store gno.Store, pn *gno.PackageNode) {
 	// Test specific injections:
 	switch pn.PkgPath {
 	case "strconv":
-		// NOTE: Itoa and Atoi are already injected
+		// NOTE: Itoa, Atoi, ParseInt, etc. are already injected
 		// from stdlibs.InjectNatives.
 		pn.DefineGoNativeType(reflect.TypeOf(strconv.NumError{}))
-		pn.DefineGoNativeValue("ParseInt", strconv.ParseInt)
 	case "std":
 		// NOTE: some of these are overrides.
 		// Also see stdlibs/InjectPackage.
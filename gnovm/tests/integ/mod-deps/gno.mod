@@ -0,0 +1,12 @@
+module gno.land/r/demo/moddeps
+
+require (
+	gno.land/p/demo/dep1 v0.0.1
+	gno.land/p/demo/unused v0.0.0
+)
+
+replace (
+	gno.land/p/demo/dep1 => ./deps/dep1
+	gno.land/p/demo/dep2 => ./deps/dep2
+	gno.land/p/demo/unused => ./deps/unused
+)
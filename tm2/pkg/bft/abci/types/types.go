@@ -196,6 +196,21 @@ type Event interface {
 	AssertABCIEvent()
 }
 
+// AttributedEvent is an Event that also exposes typed key/value
+// attributes. Only attributed events can be searched through the tx
+// indexer, where each attribute is indexed as "<type>.<key>".
+type AttributedEvent interface {
+	Event
+	EventType() string
+	EventAttributes() []EventAttribute
+}
+
+// EventAttribute is a single key/value pair of an AttributedEvent.
+type EventAttribute struct {
+	Key   string
+	Value string
+}
+
 type Header interface {
 	GetChainID() string
 	GetHeight() int64
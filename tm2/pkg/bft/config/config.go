@@ -9,6 +9,7 @@ import (
 	cns "github.com/gnolang/gno/tm2/pkg/bft/consensus/config"
 	mem "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	rpc "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
+	txindex "github.com/gnolang/gno/tm2/pkg/bft/state/txindex/config"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
 	p2p "github.com/gnolang/gno/tm2/pkg/p2p/config"
@@ -20,10 +21,11 @@ type Config struct {
 	BaseConfig `toml:",squash"`
 
 	// Options for services
-	RPC       *rpc.RPCConfig       `toml:"rpc"`
-	P2P       *p2p.P2PConfig       `toml:"p2p"`
-	Mempool   *mem.MempoolConfig   `toml:"mempool"`
-	Consensus *cns.ConsensusConfig `toml:"consensus"`
+	RPC       *rpc.RPCConfig         `toml:"rpc"`
+	P2P       *p2p.P2PConfig         `toml:"p2p"`
+	Mempool   *mem.MempoolConfig     `toml:"mempool"`
+	Consensus *cns.ConsensusConfig   `toml:"consensus"`
+	TxIndex   *txindex.TxIndexConfig `toml:"tx_index"`
 }
 
 // DefaultConfig returns a default configuration for a Tendermint node
@@ -34,6 +36,7 @@ func DefaultConfig() *Config {
 		P2P:        p2p.DefaultP2PConfig(),
 		Mempool:    mem.DefaultMempoolConfig(),
 		Consensus:  cns.DefaultConsensusConfig(),
+		TxIndex:    txindex.DefaultTxIndexConfig(),
 	}
 }
 
@@ -73,6 +76,7 @@ func TestConfig() *Config {
 		P2P:        p2p.TestP2PConfig(),
 		Mempool:    mem.TestMempoolConfig(),
 		Consensus:  cns.TestConsensusConfig(),
+		TxIndex:    txindex.TestTxIndexConfig(),
 	}
 }
 
@@ -118,6 +122,11 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Consensus.ValidateBasic(); err != nil {
 		return errors.Wrap(err, "Error in [consensus] section")
 	}
+	if cfg.TxIndex != nil {
+		if err := cfg.TxIndex.ValidateBasic(); err != nil {
+			return errors.Wrap(err, "Error in [tx_index] section")
+		}
+	}
 	return nil
 }
 
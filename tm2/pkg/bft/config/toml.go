@@ -288,6 +288,25 @@ create_empty_blocks_interval = "{{ .Consensus.CreateEmptyBlocksInterval }}"
 # Reactor sleep duration parameters
 peer_gossip_sleep_duration = "{{ .Consensus.PeerGossipSleepDuration }}"
 peer_query_maj23_sleep_duration = "{{ .Consensus.PeerQueryMaj23SleepDuration }}"
+
+##### transactions indexer configuration options #####
+[tx_index]
+
+# What indexer to use for transactions
+#
+# Options:
+#   1) "null"
+#   2) "kv" (default) - the simplest possible indexer, backed by key-value storage (defaults to levelDB; see DBBackend).
+indexer = "{{ .TxIndex.Indexer }}"
+
+# Comma-separated list of event attributes to index, each of the form
+# "<event type>.<attribute key>" (e.g. "transfer.from,transfer.to").
+# Transactions are always indexed by hash and height.
+index_attrs = "{{ .TxIndex.IndexAttrs }}"
+
+# When set to true, tells the indexer to index all event attributes.
+# Note this may make the index considerably larger.
+index_all_attrs = {{ .TxIndex.IndexAllAttrs }}
 `
 
 /****** these are for test settings ***********/
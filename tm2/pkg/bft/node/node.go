@@ -26,9 +26,8 @@ import (
 	rpcserver "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/server"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex"
-	"github.com/gnolang/gno/tm2/pkg/events"
-
-	//"github.com/gnolang/gno/tm2/pkg/bft/state/txindex/kv"
+	txindexcfg "github.com/gnolang/gno/tm2/pkg/bft/state/txindex/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex/kv"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex/null"
 	"github.com/gnolang/gno/tm2/pkg/bft/store"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
@@ -37,6 +36,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/log"
 	"github.com/gnolang/gno/tm2/pkg/p2p"
 	"github.com/gnolang/gno/tm2/pkg/service"
@@ -199,25 +199,20 @@ func createAndStartIndexerService(config *cfg.Config, dbProvider DBProvider,
 	evsw events.EventSwitch, logger log.Logger,
 ) (*txindex.IndexerService, txindex.TxIndexer, error) {
 	var txIndexer txindex.TxIndexer = &null.TxIndex{}
-	/*
-		switch config.TxIndex.Indexer {
-		case "kv":
-			store, err := dbProvider(&DBContext{"tx_index", config})
-			if err != nil {
-				return nil, nil, err
-			}
-			switch {
-			case config.TxIndex.IndexTags != "":
-				txIndexer = kv.NewTxIndex(store, kv.IndexTags(splitAndTrimEmpty(config.TxIndex.IndexTags, ",", " ")))
-			case config.TxIndex.IndexAllTags:
-				txIndexer = kv.NewTxIndex(store, kv.IndexAllTags())
-			default:
-				txIndexer = kv.NewTxIndex(store)
-			}
+	if config.TxIndex != nil && config.TxIndex.Indexer == txindexcfg.IndexerKV {
+		store, err := dbProvider(&DBContext{"tx_index", config})
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case config.TxIndex.IndexAttrs != "":
+			txIndexer = kv.NewTxIndex(store, kv.IndexAttrs(splitAndTrimEmpty(config.TxIndex.IndexAttrs, ",", " ")))
+		case config.TxIndex.IndexAllAttrs:
+			txIndexer = kv.NewTxIndex(store, kv.IndexAllAttrs())
 		default:
-			txIndexer = &null.TxIndex{}
+			txIndexer = kv.NewTxIndex(store)
 		}
-	*/
+	}
 
 	indexerService := txindex.NewIndexerService(txIndexer, evsw)
 	indexerService.SetLogger(logger.With("module", "txindex"))
@@ -137,7 +137,6 @@ func (c *Local) Validators(height *int64) (*ctypes.ResultValidators, error) {
 	return core.Validators(c.ctx, height)
 }
 
-/*
 func (c *Local) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 	return core.Tx(c.ctx, hash, prove)
 }
@@ -145,4 +144,3 @@ func (c *Local) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 func (c *Local) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
 	return core.TxSearch(c.ctx, query, prove, page, perPage)
 }
-*/
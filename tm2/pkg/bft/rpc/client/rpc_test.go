@@ -1,6 +1,7 @@
 package client_test
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 	"sync"
@@ -342,7 +343,6 @@ func TestNumUnconfirmedTxs(t *testing.T) {
 	mempool.Flush()
 }
 
-/*
 func TestTx(t *testing.T) {
 	// first we broadcast a tx
 	c := getHTTPClient()
@@ -414,7 +414,7 @@ func TestTxSearch(t *testing.T) {
 
 		// now we query for the tx.
 		// since there's only one tx, we know index=0.
-		result, err := c.TxSearch(fmt.Sprintf("tx.hash='%v'", txHash), true, 1, 30)
+		result, err := c.TxSearch(fmt.Sprintf("tx.hash='%X'", txHash), true, 1, 30)
 		require.Nil(t, err, "%+v", err)
 		require.Len(t, result.Txs, 1)
 
@@ -441,27 +441,17 @@ func TestTxSearch(t *testing.T) {
 		require.Nil(t, err, "%+v", err)
 		require.Len(t, result.Txs, 0)
 
-		// query using a tag (see kvstore application)
-		result, err = c.TxSearch("app.creator='Cosmoshi Netowoko'", false, 1, 30)
+		// query by height range
+		result, err = c.TxSearch(fmt.Sprintf("tx.height>=%d AND tx.height<10000", txHeight), false, 1, 30)
 		require.Nil(t, err, "%+v", err)
-		if len(result.Txs) == 0 {
-			t.Fatal("expected a lot of transactions")
-		}
-
-		// query using a tag (see kvstore application) and height
-		result, err = c.TxSearch("app.creator='Cosmoshi Netowoko' AND tx.height<10000", true, 1, 30)
-		require.Nil(t, err, "%+v", err)
-		if len(result.Txs) == 0 {
-			t.Fatal("expected a lot of transactions")
-		}
+		require.Len(t, result.Txs, 1)
 
-		// query a non existing tx with page 1 and txsPerPage 1
-		result, err = c.TxSearch("app.creator='Cosmoshi Neetowoko'", true, 1, 1)
+		// query a non existing height with page 1 and txsPerPage 1
+		result, err = c.TxSearch("tx.height=0", true, 1, 1)
 		require.Nil(t, err, "%+v", err)
 		require.Len(t, result.Txs, 0)
 	}
 }
-*/
 
 func TestBatchedJSONRPCCalls(t *testing.T) {
 	c := getHTTPClient()
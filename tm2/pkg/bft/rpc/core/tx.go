@@ -1,17 +1,14 @@
 package core
 
-/*
-
 import (
 	"fmt"
 
-	cmn "github.com/gnolang/gno/tm2/pkg/common"
-
-	tmquery "github.com/gnolang/gno/tm2/pkg/pubsub/query"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex/null"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/maths"
 )
 
 // Tx allows you to query the transaction results. `nil` could mean the
@@ -109,7 +106,7 @@ func Tx(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error
 		Hash:     hash,
 		Height:   height,
 		Index:    index,
-		TxResult: r.Result,
+		TxResult: r.Response,
 		Tx:       r.Tx,
 		Proof:    proof,
 	}, nil
@@ -119,7 +116,7 @@ func Tx(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error
 // list of transactions (maximum ?per_page entries) and the total count.
 //
 // ```shell
-// curl "localhost:26657/tx_search?query=\"account.owner='Ivan'\"&prove=true"
+// curl "localhost:26657/tx_search?query=\"transfer.from='g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5'\"&prove=true"
 // ```
 //
 // ```go
@@ -129,8 +126,7 @@ func Tx(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error
 //   // handle error
 // }
 // defer client.Stop()
-// q, err := tmquery.New("account.owner='Ivan'")
-// tx, err := client.TxSearch(q, true)
+// txs, err := client.TxSearch("tx.height>=10 AND tx.height<20", true, 1, 30)
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -192,7 +188,7 @@ func TxSearch(ctx *rpctypes.Context, query string, prove bool, page, perPage int
 		return nil, fmt.Errorf("Transaction indexing is disabled")
 	}
 
-	q, err := tmquery.New(query)
+	q, err := txindex.ParseQuery(query)
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +206,7 @@ func TxSearch(ctx *rpctypes.Context, query string, prove bool, page, perPage int
 	}
 	skipCount := validateSkipCount(page, perPage)
 
-	apiResults := make([]*ctypes.ResultTx, cmn.MinInt(perPage, totalCount-skipCount))
+	apiResults := make([]*ctypes.ResultTx, maths.MinInt(perPage, totalCount-skipCount))
 	var proof types.TxProof
 	// if there's no tx in the results array, we don't need to loop through the apiResults array
 	for i := 0; i < len(apiResults); i++ {
@@ -227,7 +223,7 @@ func TxSearch(ctx *rpctypes.Context, query string, prove bool, page, perPage int
 			Hash:     r.Tx.Hash(),
 			Height:   height,
 			Index:    index,
-			TxResult: r.Result,
+			TxResult: r.Response,
 			Tx:       r.Tx,
 			Proof:    proof,
 		}
@@ -235,4 +231,3 @@ func TxSearch(ctx *rpctypes.Context, query string, prove bool, page, perPage int
 
 	return &ctypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount}, nil
 }
-*/
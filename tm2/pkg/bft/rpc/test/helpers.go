@@ -68,7 +68,7 @@ func createConfig() *cfg.Config {
 	c.P2P.ListenAddress = "tcp://127.0.0.1:0"
 	c.RPC.ListenAddress = "tcp://127.0.0.1:0"
 	c.RPC.CORSAllowedOrigins = []string{"https://tendermint.com/"}
-	// c.TxIndex.IndexTags = "app.creator,tx.height" // see kvstore application
+	// c.TxIndex.IndexAttrs = "app.creator" // see kvstore application
 	return c
 }
 
@@ -0,0 +1,59 @@
+package config
+
+import "github.com/gnolang/gno/tm2/pkg/errors"
+
+//-----------------------------------------------------------------------------
+// TxIndexConfig
+
+// Supported indexer backends.
+const (
+	IndexerNull = "null"
+	IndexerKV   = "kv"
+)
+
+// TxIndexConfig defines the configuration for the transaction indexer,
+// including event attributes to index.
+type TxIndexConfig struct {
+	// What indexer to use for transactions
+	//
+	// Options:
+	//   1) "null"
+	//   2) "kv" (default) - the simplest possible indexer, backed by
+	//   key-value storage (defaults to levelDB; see DBBackend).
+	Indexer string `toml:"indexer"`
+
+	// Comma-separated list of event attributes to index, each of the
+	// form "<event type>.<attribute key>". Transactions are always
+	// indexed by hash and height.
+	IndexAttrs string `toml:"index_attrs"`
+
+	// When set to true, tells the indexer to index all event attributes
+	// (predefined attributes: "tx.hash", "tx.height"). Note this may
+	// make the index considerably larger.
+	IndexAllAttrs bool `toml:"index_all_attrs"`
+}
+
+// DefaultTxIndexConfig returns a default configuration for the transaction indexer.
+func DefaultTxIndexConfig() *TxIndexConfig {
+	return &TxIndexConfig{
+		Indexer:       IndexerKV,
+		IndexAttrs:    "",
+		IndexAllAttrs: false,
+	}
+}
+
+// TestTxIndexConfig returns a default configuration for the transaction indexer.
+func TestTxIndexConfig() *TxIndexConfig {
+	return DefaultTxIndexConfig()
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *TxIndexConfig) ValidateBasic() error {
+	switch cfg.Indexer {
+	case IndexerNull, IndexerKV:
+		return nil
+	default:
+		return errors.New("unknown indexer %q", cfg.Indexer)
+	}
+}
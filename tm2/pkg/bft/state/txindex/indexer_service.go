@@ -1,10 +1,13 @@
 package txindex
 
 import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/service"
 )
 
+const listenerID = "txindex.IndexerService"
+
 // IndexerService connects event bus and transaction indexer together in order
 // to index transactions coming from event bus.
 type IndexerService struct {
@@ -12,6 +15,11 @@ type IndexerService struct {
 
 	idr  TxIndexer
 	evsw events.EventSwitch
+
+	// batch of the block being indexed; EventTx are fired right after the
+	// EventNewBlockHeader of their block, see state.fireEvents.
+	batch *Batch
+	added int64
 }
 
 // NewIndexerService returns a new service instance.
@@ -21,11 +29,65 @@ func NewIndexerService(idr TxIndexer, evsw events.EventSwitch) *IndexerService {
 	return is
 }
 
+// OnStart implements service.Service by subscribing for all transactions
+// and indexing them by events.
 func (is *IndexerService) OnStart() error {
-	// TODO
+	is.evsw.AddListener(listenerID, is.onEvent)
 	return nil
 }
 
+// OnStop implements service.Service by unsubscribing from all transactions.
 func (is *IndexerService) OnStop() {
-	// TODO
+	is.evsw.RemoveListener(listenerID)
+}
+
+func (is *IndexerService) onEvent(event events.Event) {
+	switch ev := event.(type) {
+	case types.EventNewBlockHeader:
+		// a new block starts; index what was received of the previous
+		// one, so none of its txs leak into this block's batch.
+		is.flushIncomplete()
+		is.batch = nil
+		is.added = 0
+		if ev.Header.NumTxs > 0 {
+			is.batch = NewBatch(ev.Header.NumTxs)
+		}
+	case types.EventTx:
+		result := ev.Result
+		if is.batch == nil || int64(result.Index) >= int64(is.batch.Size()) {
+			// not part of a known block (e.g. the service started
+			// mid-block); index it on its own.
+			if err := is.idr.Index(&result); err != nil {
+				is.Logger.Error("Failed to index tx", "height", result.Height, "index", result.Index, "err", err)
+			}
+			return
+		}
+		is.batch.Add(&result)
+		is.added++
+		if is.added < int64(is.batch.Size()) {
+			return
+		}
+		if err := is.idr.AddBatch(is.batch); err != nil {
+			is.Logger.Error("Failed to index block", "height", result.Height, "err", err)
+		} else {
+			is.Logger.Info("Indexed block", "height", result.Height)
+		}
+		is.batch = nil
+	}
+}
+
+// flushIncomplete indexes the txs of a batch that did not receive all
+// the txs of its block.
+func (is *IndexerService) flushIncomplete() {
+	if is.batch == nil || is.added == 0 {
+		return
+	}
+	for _, result := range is.batch.Ops {
+		if result == nil {
+			continue
+		}
+		if err := is.idr.Index(result); err != nil {
+			is.Logger.Error("Failed to index tx", "height", result.Height, "index", result.Index, "err", err)
+		}
+	}
 }
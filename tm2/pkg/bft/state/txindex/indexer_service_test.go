@@ -0,0 +1,87 @@
+package txindex_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex/kv"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/events"
+)
+
+func TestIndexerServiceIndexesBlock(t *testing.T) {
+	t.Parallel()
+
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	service := txindex.NewIndexerService(txIndexer, evsw)
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	txResult1 := types.TxResult{Height: 1, Index: 0, Tx: types.Tx("foo")}
+	txResult2 := types.TxResult{Height: 1, Index: 1, Tx: types.Tx("bar")}
+
+	evsw.FireEvent(types.EventNewBlockHeader{Header: types.Header{Height: 1, NumTxs: 2}})
+	evsw.FireEvent(types.EventTx{Result: txResult1})
+
+	// the block is only written once all its txs were received.
+	res, err := txIndexer.Get(types.Tx("foo").Hash())
+	require.NoError(t, err)
+	assert.Nil(t, res)
+
+	evsw.FireEvent(types.EventTx{Result: txResult2})
+
+	res, err = txIndexer.Get(types.Tx("foo").Hash())
+	require.NoError(t, err)
+	assert.Equal(t, &txResult1, res)
+
+	res, err = txIndexer.Get(types.Tx("bar").Hash())
+	require.NoError(t, err)
+	assert.Equal(t, &txResult2, res)
+}
+
+func TestIndexerServiceResetsIncompleteBatch(t *testing.T) {
+	t.Parallel()
+
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	service := txindex.NewIndexerService(txIndexer, evsw)
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	txResult1 := types.TxResult{Height: 1, Index: 0, Tx: types.Tx("foo")}
+	txResult2 := types.TxResult{Height: 3, Index: 0, Tx: types.Tx("bar")}
+
+	// block 1 announces two txs but only one is received.
+	evsw.FireEvent(types.EventNewBlockHeader{Header: types.Header{Height: 1, NumTxs: 2}})
+	evsw.FireEvent(types.EventTx{Result: txResult1})
+
+	// the received tx is indexed once the next block starts.
+	evsw.FireEvent(types.EventNewBlockHeader{Header: types.Header{Height: 2, NumTxs: 0}})
+
+	res, err := txIndexer.Get(types.Tx("foo").Hash())
+	require.NoError(t, err)
+	assert.Equal(t, &txResult1, res)
+
+	// block 3 gets a batch of its own.
+	evsw.FireEvent(types.EventNewBlockHeader{Header: types.Header{Height: 3, NumTxs: 1}})
+	evsw.FireEvent(types.EventTx{Result: txResult2})
+
+	res, err = txIndexer.Get(types.Tx("bar").Hash())
+	require.NoError(t, err)
+	assert.Equal(t, &txResult2, res)
+
+	results, err := txIndexer.Search(txindex.MustParseQuery("tx.height = 3"))
+	require.NoError(t, err)
+	assert.Equal(t, []*types.TxResult{&txResult2}, results)
+}
@@ -0,0 +1,287 @@
+// Package kv implements a transaction indexer backed by a tm2/pkg/db
+// key-value store.
+//
+// Two kinds of records are kept:
+//
+//	tx/<hash>                                  -> amino(types.TxResult)
+//	attr/<key>/<value>/<height>/<index>        -> <hash>
+//
+// where <key> is either "tx.height" (always indexed) or "<type>.<key>" of
+// an abci.AttributedEvent attribute. Attributes whose key starts with
+// "tx." are reserved and never indexed.
+package kv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+var _ txindex.TxIndexer = (*TxIndex)(nil)
+
+const (
+	txPrefix   = "tx/"
+	attrPrefix = "attr/"
+
+	// txReservedPrefix is the prefix of the query keys reserved to the
+	// indexer, see txindex.TxHeightKey and txindex.TxHashKey.
+	txReservedPrefix = "tx."
+)
+
+// TxIndex is the simplest possible indexer, backed by key-value storage.
+type TxIndex struct {
+	store        dbm.DB
+	attrsToIndex map[string]struct{}
+	indexAll     bool
+}
+
+// NewTxIndex creates a new KV indexer. By default only "tx.height" is
+// indexed besides the hash; use IndexAttrs or IndexAllAttrs to index
+// event attributes too.
+func NewTxIndex(store dbm.DB, options ...func(*TxIndex)) *TxIndex {
+	txi := &TxIndex{store: store, attrsToIndex: make(map[string]struct{})}
+	for _, o := range options {
+		o(txi)
+	}
+	return txi
+}
+
+// IndexAttrs is an option for setting which event attributes to index,
+// each of the form "<type>.<key>".
+func IndexAttrs(keys []string) func(*TxIndex) {
+	return func(txi *TxIndex) {
+		for _, key := range keys {
+			txi.attrsToIndex[key] = struct{}{}
+		}
+	}
+}
+
+// IndexAllAttrs is an option for indexing all event attributes.
+func IndexAllAttrs() func(*TxIndex) {
+	return func(txi *TxIndex) {
+		txi.indexAll = true
+	}
+}
+
+// Get gets transaction from the TxIndex storage and returns it or nil if the
+// transaction is not found.
+func (txi *TxIndex) Get(hash []byte) (*types.TxResult, error) {
+	if len(hash) == 0 {
+		return nil, txindex.ErrorEmptyHash
+	}
+
+	rawBytes := txi.store.Get(txKey(hash))
+	if rawBytes == nil {
+		return nil, nil
+	}
+
+	txResult := new(types.TxResult)
+	err := amino.Unmarshal(rawBytes, txResult)
+	if err != nil {
+		return nil, fmt.Errorf("error reading TxResult: %w", err)
+	}
+
+	return txResult, nil
+}
+
+// AddBatch indexes a batch of transactions using the given list of attributes.
+func (txi *TxIndex) AddBatch(b *txindex.Batch) error {
+	storeBatch := txi.store.NewBatch()
+	defer storeBatch.Close()
+
+	for _, result := range b.Ops {
+		if result == nil {
+			continue
+		}
+		if err := txi.indexTo(storeBatch, result); err != nil {
+			return err
+		}
+	}
+
+	storeBatch.WriteSync()
+	return nil
+}
+
+// Index indexes a single transaction using the given list of attributes.
+func (txi *TxIndex) Index(result *types.TxResult) error {
+	storeBatch := txi.store.NewBatch()
+	defer storeBatch.Close()
+
+	if err := txi.indexTo(storeBatch, result); err != nil {
+		return err
+	}
+
+	storeBatch.WriteSync()
+	return nil
+}
+
+func (txi *TxIndex) indexTo(sd dbm.SetDeleter, result *types.TxResult) error {
+	hash := result.Tx.Hash()
+
+	rawBytes, err := amino.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	// index tx by height
+	sd.Set(attrKey(txindex.TxHeightKey, strconv.FormatInt(result.Height, 10), result), hash)
+
+	// index tx by event attributes
+	for _, ev := range result.Response.Events {
+		aev, ok := ev.(abci.AttributedEvent)
+		if !ok {
+			continue
+		}
+		typ := aev.EventType()
+		for _, attr := range aev.EventAttributes() {
+			key := typ + "." + attr.Key
+			if strings.HasPrefix(key, txReservedPrefix) {
+				// never let events write the reserved tx.* keys.
+				continue
+			}
+			if !txi.shouldIndex(key) {
+				continue
+			}
+			sd.Set(attrKey(key, attr.Value, result), hash)
+		}
+	}
+
+	// index tx by hash
+	sd.Set(txKey(hash), rawBytes)
+	return nil
+}
+
+func (txi *TxIndex) shouldIndex(key string) bool {
+	if txi.indexAll {
+		return true
+	}
+	_, ok := txi.attrsToIndex[key]
+	return ok
+}
+
+// Search performs a search using the given query. Results are the
+// intersection of all conditions, sorted by height and index within the
+// block. A query on "tx.hash" returns at most one result.
+func (txi *TxIndex) Search(q *txindex.Query) ([]*types.TxResult, error) {
+	var (
+		hashes  map[string]struct{}
+		results []*types.TxResult
+	)
+
+	for _, c := range q.Conditions {
+		var matches map[string]struct{}
+		if c.Key == txindex.TxHashKey {
+			hash, err := hex.DecodeString(c.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tx.hash %q: %w", c.Value, err)
+			}
+			matches = make(map[string]struct{})
+			if txi.store.Has(txKey(hash)) {
+				matches[string(hash)] = struct{}{}
+			}
+		} else {
+			matches = txi.match(c)
+		}
+
+		if hashes == nil {
+			hashes = matches
+		} else {
+			for h := range hashes {
+				if _, ok := matches[h]; !ok {
+					delete(hashes, h)
+				}
+			}
+		}
+		if len(hashes) == 0 {
+			return []*types.TxResult{}, nil
+		}
+	}
+
+	results = make([]*types.TxResult, 0, len(hashes))
+	for h := range hashes {
+		res, err := txi.Get([]byte(h))
+		if err != nil {
+			return nil, err
+		}
+		if res != nil {
+			results = append(results, res)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Height != results[j].Height {
+			return results[i].Height < results[j].Height
+		}
+		return results[i].Index < results[j].Index
+	})
+	return results, nil
+}
+
+// match returns the set of hashes satisfying a single condition. Equality
+// on strings and on tx.height narrows the iteration to the key/value
+// prefix; everything else scans all values of the key.
+func (txi *TxIndex) match(c txindex.Condition) map[string]struct{} {
+	matches := make(map[string]struct{})
+
+	prefix := attrKeyPrefix(c.Key)
+	switch {
+	case !c.IsNumber:
+		prefix = append(prefix, []byte(c.Value+"/")...)
+	case c.Op == txindex.OpEqual && c.Key == txindex.TxHeightKey:
+		// heights are always written in canonical form, see indexTo.
+		prefix = append(prefix, []byte(strconv.FormatInt(c.Number, 10)+"/")...)
+	}
+
+	it := dbm.IteratePrefix(txi.store, prefix)
+	defer it.Close()
+
+	keyPrefixLen := len(attrKeyPrefix(c.Key))
+	for ; it.Valid(); it.Next() {
+		value, ok := extractValue(it.Key()[keyPrefixLen:])
+		if !ok || !c.Match(value) {
+			continue
+		}
+		matches[string(it.Value())] = struct{}{}
+	}
+	return matches
+}
+
+// extractValue extracts the value from "<value>/<height>/<index>". The
+// value itself may contain slashes.
+func extractValue(rest []byte) (string, bool) {
+	i := bytes.LastIndexByte(rest, '/')
+	if i < 0 {
+		return "", false
+	}
+	j := bytes.LastIndexByte(rest[:i], '/')
+	if j < 0 {
+		return "", false
+	}
+	return string(rest[:j]), true
+}
+
+func txKey(hash []byte) []byte {
+	return append([]byte(txPrefix), hash...)
+}
+
+func attrKeyPrefix(key string) []byte {
+	return []byte(attrPrefix + key + "/")
+}
+
+func attrKey(key, value string, result *types.TxResult) []byte {
+	return []byte(strings.Join([]string{
+		attrPrefix + key,
+		value,
+		strconv.FormatInt(result.Height, 10),
+		strconv.FormatUint(uint64(result.Index), 10),
+	}, "/"))
+}
@@ -0,0 +1,202 @@
+package kv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+type testEvent struct {
+	Type  string
+	Attrs []abci.EventAttribute
+}
+
+func (testEvent) AssertABCIEvent()                          {}
+func (ev testEvent) EventType() string                      { return ev.Type }
+func (ev testEvent) EventAttributes() []abci.EventAttribute { return ev.Attrs }
+
+var testPackage = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex/kv",
+	"kv",
+	amino.GetCallersDirname(),
+).
+	WithDependencies(
+		abci.Package,
+	).
+	WithTypes(
+		testEvent{},
+	))
+
+func txResultWithEvents(height int64, index uint32, tx string, events ...abci.Event) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     types.Tx(tx),
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Data:   []byte{0},
+				Events: events,
+			},
+		},
+	}
+}
+
+func transfer(from, to string) abci.Event {
+	return testEvent{
+		Type: "transfer",
+		Attrs: []abci.EventAttribute{
+			{Key: "from", Value: from},
+			{Key: "to", Value: to},
+		},
+	}
+}
+
+func TestTxIndex(t *testing.T) {
+	t.Parallel()
+
+	indexer := NewTxIndex(dbm.NewMemDB())
+
+	txResult := txResultWithEvents(1, 0, "HELLO WORLD")
+	hash := txResult.Tx.Hash()
+
+	batch := txindex.NewBatch(1)
+	require.NoError(t, batch.Add(txResult))
+	require.NoError(t, indexer.AddBatch(batch))
+
+	loadedTxResult, err := indexer.Get(hash)
+	require.NoError(t, err)
+	assert.Equal(t, txResult, loadedTxResult)
+
+	txResult2 := txResultWithEvents(1, 0, "BYE BYE WORLD")
+	hash2 := txResult2.Tx.Hash()
+
+	require.NoError(t, indexer.Index(txResult2))
+
+	loadedTxResult2, err := indexer.Get(hash2)
+	require.NoError(t, err)
+	assert.Equal(t, txResult2, loadedTxResult2)
+
+	missing, err := indexer.Get(types.Tx("missing").Hash())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	_, err = indexer.Get(nil)
+	assert.Equal(t, txindex.ErrorEmptyHash, err)
+}
+
+func TestTxSearch(t *testing.T) {
+	t.Parallel()
+
+	indexer := NewTxIndex(dbm.NewMemDB(), IndexAllAttrs())
+
+	results := []*types.TxResult{
+		txResultWithEvents(1, 0, "tx1", transfer("alice", "bob")),
+		txResultWithEvents(1, 1, "tx2", transfer("bob", "carol")),
+		txResultWithEvents(2, 0, "tx3", transfer("alice", "gno.land/r/demo/foo")),
+		txResultWithEvents(3, 0, "tx4", abci.EventString("not indexed")),
+	}
+	for _, res := range results {
+		require.NoError(t, indexer.Index(res))
+	}
+
+	testCases := []struct {
+		q        string
+		expected []*types.TxResult
+	}{
+		// search by hash
+		{fmt.Sprintf("tx.hash='%X'", results[0].Tx.Hash()), results[:1]},
+		// search by exact match (one attribute)
+		{"transfer.from='alice'", []*types.TxResult{results[0], results[2]}},
+		// values may contain slashes
+		{"transfer.to='gno.land/r/demo/foo'", results[2:3]},
+		// search by height
+		{"tx.height=1", results[:2]},
+		// search by height range
+		{"tx.height>1", results[2:]},
+		{"tx.height>=1 AND tx.height<3", results[:3]},
+		// conjunction of attribute and height
+		{"transfer.from='alice' AND tx.height=2", results[2:3]},
+		// search by non-existing attribute value
+		{"transfer.from='dave'", []*types.TxResult{}},
+		// search by non-existing attribute key
+		{"account.number=1", []*types.TxResult{}},
+		// conjunction with no intersection
+		{"transfer.from='bob' AND tx.height=2", []*types.TxResult{}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.q, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := indexer.Search(txindex.MustParseQuery(tc.q))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestIndexAttrs(t *testing.T) {
+	t.Parallel()
+
+	indexer := NewTxIndex(dbm.NewMemDB(), IndexAttrs([]string{"transfer.to"}))
+
+	txResult := txResultWithEvents(1, 0, "tx1", transfer("alice", "bob"))
+	require.NoError(t, indexer.Index(txResult))
+
+	res, err := indexer.Search(txindex.MustParseQuery("transfer.to='bob'"))
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+
+	res, err = indexer.Search(txindex.MustParseQuery("transfer.from='alice'"))
+	require.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	// height is always indexed
+	res, err = indexer.Search(txindex.MustParseQuery("tx.height=1"))
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+}
+
+func TestReservedAttrs(t *testing.T) {
+	t.Parallel()
+
+	indexer := NewTxIndex(dbm.NewMemDB(), IndexAllAttrs())
+
+	fake := testEvent{
+		Type: "tx",
+		Attrs: []abci.EventAttribute{
+			{Key: "height", Value: "5"},
+			{Key: "hash", Value: "00"},
+		},
+	}
+	results := []*types.TxResult{
+		txResultWithEvents(1, 0, "tx1", fake),
+		txResultWithEvents(10, 0, "tx2"),
+	}
+	for _, res := range results {
+		require.NoError(t, indexer.Index(res))
+	}
+
+	// events can't write to the reserved tx.* keys.
+	res, err := indexer.Search(txindex.MustParseQuery("tx.height=5"))
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	// height equality matches the exact height only.
+	res, err = indexer.Search(txindex.MustParseQuery("tx.height=1"))
+	require.NoError(t, err)
+	assert.Equal(t, results[:1], res)
+
+	res, err = indexer.Search(txindex.MustParseQuery("tx.height=10"))
+	require.NoError(t, err)
+	assert.Equal(t, results[1:], res)
+}
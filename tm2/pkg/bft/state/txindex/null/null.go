@@ -1,7 +1,10 @@
 package null
 
 import (
+	"errors"
+
 	"github.com/gnolang/gno/tm2/pkg/bft/state/txindex"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
 )
 
 var _ txindex.TxIndexer = (*TxIndex)(nil)
@@ -9,10 +12,9 @@ var _ txindex.TxIndexer = (*TxIndex)(nil)
 // TxIndex acts as a /dev/null.
 type TxIndex struct{}
 
-/*
-// Get on a TxIndex is disabled and panics when invoked.
+// Get on a TxIndex is disabled and always returns an error.
 func (txi *TxIndex) Get(hash []byte) (*types.TxResult, error) {
-	return nil, errors.New(`Indexing is disabled (set 'tx_index = "kv"' in config)`)
+	return nil, errors.New(`Indexing is disabled (set 'indexer = "kv"' in the [tx_index] config)`)
 }
 
 // AddBatch is a noop and always returns nil.
@@ -25,7 +27,6 @@ func (txi *TxIndex) Index(result *types.TxResult) error {
 	return nil
 }
 
-func (txi *TxIndex) Search(q *query.Query) ([]*types.TxResult, error) {
+func (txi *TxIndex) Search(q *txindex.Query) ([]*types.TxResult, error) {
 	return []*types.TxResult{}, nil
 }
-*/
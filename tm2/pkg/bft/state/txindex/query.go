@@ -0,0 +1,186 @@
+package txindex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Reserved query keys, always available regardless of the events
+// emitted by the application.
+const (
+	TxHashKey   = "tx.hash"
+	TxHeightKey = "tx.height"
+)
+
+// Operator is a comparison operator used in a query Condition.
+type Operator int
+
+const (
+	OpEqual Operator = iota
+	OpLess
+	OpLessEqual
+	OpGreater
+	OpGreaterEqual
+)
+
+func (op Operator) String() string {
+	switch op {
+	case OpEqual:
+		return "="
+	case OpLess:
+		return "<"
+	case OpLessEqual:
+		return "<="
+	case OpGreater:
+		return ">"
+	case OpGreaterEqual:
+		return ">="
+	default:
+		panic(fmt.Sprintf("unknown operator %d", int(op)))
+	}
+}
+
+// Condition is a single "key op value" clause of a Query.
+type Condition struct {
+	Key      string
+	Op       Operator
+	Value    string
+	IsNumber bool  // whether Value was given as an unquoted integer
+	Number   int64 // set iff IsNumber
+}
+
+// Match returns whether the given value satisfies the condition. Numeric
+// conditions compare the value as an integer; string conditions only
+// support equality.
+func (c Condition) Match(value string) bool {
+	if !c.IsNumber {
+		return c.Op == OpEqual && value == c.Value
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	switch c.Op {
+	case OpEqual:
+		return n == c.Number
+	case OpLess:
+		return n < c.Number
+	case OpLessEqual:
+		return n <= c.Number
+	case OpGreater:
+		return n > c.Number
+	case OpGreaterEqual:
+		return n >= c.Number
+	default:
+		return false
+	}
+}
+
+func (c Condition) String() string {
+	if c.IsNumber {
+		return fmt.Sprintf("%s%s%d", c.Key, c.Op, c.Number)
+	}
+	return fmt.Sprintf("%s%s'%s'", c.Key, c.Op, c.Value)
+}
+
+// Query is a conjunction of conditions, e.g.
+//
+//	tx.height>=5 AND transfer.from='g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5'
+//
+// String values must be single-quoted; integer values are unquoted and
+// may be compared with <, <=, >, >= and =.
+type Query struct {
+	str        string
+	Conditions []Condition
+}
+
+// ParseQuery parses a query string; see Query.
+func ParseQuery(s string) (*Query, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty query")
+	}
+	q := &Query{str: s}
+	for _, clause := range splitAnd(s) {
+		cond, err := parseCondition(clause)
+		if err != nil {
+			return nil, err
+		}
+		q.Conditions = append(q.Conditions, cond)
+	}
+	return q, nil
+}
+
+// MustParseQuery is like ParseQuery but panics on error.
+func MustParseQuery(s string) *Query {
+	q, err := ParseQuery(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func (q *Query) String() string {
+	return q.str
+}
+
+// splitAnd splits s on the AND keyword, ignoring any AND that appears
+// within a quoted value.
+func splitAnd(s string) []string {
+	var (
+		clauses []string
+		start   int
+		quoted  bool
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\'':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(s[i:], " AND "):
+			clauses = append(clauses, s[start:i])
+			i += len(" AND ") - 1
+			start = i + 1
+		}
+	}
+	return append(clauses, s[start:])
+}
+
+func parseCondition(clause string) (Condition, error) {
+	clause = strings.TrimSpace(clause)
+	idx := strings.IndexAny(clause, "=<>")
+	if idx <= 0 {
+		return Condition{}, fmt.Errorf("invalid condition %q: expected key, operator and value", clause)
+	}
+	cond := Condition{Key: strings.TrimSpace(clause[:idx])}
+	rest := clause[idx:]
+	switch {
+	case strings.HasPrefix(rest, "<="):
+		cond.Op, rest = OpLessEqual, rest[2:]
+	case strings.HasPrefix(rest, ">="):
+		cond.Op, rest = OpGreaterEqual, rest[2:]
+	case strings.HasPrefix(rest, "<"):
+		cond.Op, rest = OpLess, rest[1:]
+	case strings.HasPrefix(rest, ">"):
+		cond.Op, rest = OpGreater, rest[1:]
+	default:
+		cond.Op, rest = OpEqual, rest[1:]
+	}
+	if cond.Key == "" || strings.ContainsAny(cond.Key, " '") {
+		return Condition{}, fmt.Errorf("invalid condition %q: invalid key", clause)
+	}
+	rest = strings.TrimSpace(rest)
+	if len(rest) >= 2 && rest[0] == '\'' && rest[len(rest)-1] == '\'' {
+		if cond.Op != OpEqual {
+			return Condition{}, fmt.Errorf("invalid condition %q: operator %s requires an integer value", clause, cond.Op)
+		}
+		cond.Value = rest[1 : len(rest)-1]
+		return cond, nil
+	}
+	n, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil {
+		return Condition{}, fmt.Errorf("invalid condition %q: value must be a quoted string or an integer", clause)
+	}
+	cond.Value, cond.IsNumber, cond.Number = rest, true, n
+	return cond, nil
+}
@@ -0,0 +1,71 @@
+package txindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		s        string
+		expected []Condition
+		err      bool
+	}{
+		{s: "tx.height=5", expected: []Condition{
+			{Key: "tx.height", Op: OpEqual, Value: "5", IsNumber: true, Number: 5},
+		}},
+		{s: "transfer.from='g1abc' AND tx.height>=10", expected: []Condition{
+			{Key: "transfer.from", Op: OpEqual, Value: "g1abc"},
+			{Key: "tx.height", Op: OpGreaterEqual, Value: "10", IsNumber: true, Number: 10},
+		}},
+		{s: "a.b < 3 AND a.c <= 4 AND a.d > -1", expected: []Condition{
+			{Key: "a.b", Op: OpLess, Value: "3", IsNumber: true, Number: 3},
+			{Key: "a.c", Op: OpLessEqual, Value: "4", IsNumber: true, Number: 4},
+			{Key: "a.d", Op: OpGreater, Value: "-1", IsNumber: true, Number: -1},
+		}},
+		// AND within quotes is part of the value
+		{s: "post.title='this AND that'", expected: []Condition{
+			{Key: "post.title", Op: OpEqual, Value: "this AND that"},
+		}},
+		{s: "", err: true},
+		{s: "tx.height", err: true},
+		{s: "=5", err: true},
+		{s: "tx.height=five", err: true},
+		{s: "transfer.from>'g1abc'", err: true},
+		{s: "tx.height=5 AND", err: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.s, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := ParseQuery(tc.s)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, q.Conditions)
+			assert.Equal(t, tc.s, q.String())
+		})
+	}
+}
+
+func TestConditionMatch(t *testing.T) {
+	t.Parallel()
+
+	q := MustParseQuery("tx.height>=10 AND transfer.to='bob'")
+
+	assert.True(t, q.Conditions[0].Match("10"))
+	assert.True(t, q.Conditions[0].Match("11"))
+	assert.False(t, q.Conditions[0].Match("9"))
+	assert.False(t, q.Conditions[0].Match("bob"))
+
+	assert.True(t, q.Conditions[1].Match("bob"))
+	assert.False(t, q.Conditions[1].Match("bobby"))
+}
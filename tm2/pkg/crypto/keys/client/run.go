@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type runCfg struct {
+	rootCfg *makeTxCfg
+
+	send string
+}
+
+func newRunCmd(rootCfg *makeTxCfg) *commands.Command {
+	cfg := &runCfg{
+		rootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "run",
+			ShortUsage: "run [flags] <key-name or address> <file or - or dir>",
+			ShortHelp:  "Runs Gno code by invoking main() in a package",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execRun(cfg, args, commands.NewDefaultIO())
+		},
+	)
+}
+
+func (c *runCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.send,
+		"send",
+		"",
+		"send amount",
+	)
+}
+
+func execRun(cfg *runCfg, args []string, io *commands.IO) error {
+	if len(args) != 2 {
+		return flag.ErrHelp
+	}
+	if cfg.rootCfg.gasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.rootCfg.gasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := keys.NewKeyBaseFromDir(cfg.rootCfg.rootCfg.Home)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	caller := info.GetAddress()
+
+	// Parse send amount.
+	send, err := std.ParseCoins(cfg.send)
+	if err != nil {
+		return errors.Wrap(err, "parsing send coins")
+	}
+
+	// read files.
+	files, err := readRunFiles(args[1], io.In)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if filepath.Ext(file.Name) != ".gno" {
+			continue
+		}
+		fn, err := gno.ParseFile(file.Name, file.Body)
+		if err != nil {
+			return errors.Wrap(err, "parsing file %s", file.Name)
+		}
+		if fn.PkgName != "main" {
+			return errors.New("file %s: expected package main, got %s", file.Name, fn.PkgName)
+		}
+	}
+	msg := vm.NewMsgRun(caller, send, files)
+
+	// precompile and validate syntax
+	err = gno.PrecompileAndCheckMempkg(msg.Package)
+	if err != nil {
+		return errors.Wrap(err, "precompile package")
+	}
+
+	// parse gas wanted & fee.
+	gaswanted := cfg.rootCfg.gasWanted
+	gasfee, err := std.ParseCoin(cfg.rootCfg.gasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+
+	// construct msg & tx and marshal.
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        std.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       cfg.rootCfg.memo,
+	}
+
+	if cfg.rootCfg.broadcast {
+		err := signAndBroadcast(cfg.rootCfg, args, tx, io)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
+
+// readRunFiles reads the .gno files of the run package from a single file,
+// a directory, or stdin if source is "-".
+func readRunFiles(source string, stdin io.Reader) ([]*std.MemFile, error) {
+	if source == "-" {
+		bz, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading stdin")
+		}
+		return []*std.MemFile{{Name: "main.gno", Body: string(bz)}}, nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading %s", source)
+	}
+	if !info.IsDir() {
+		bz, err := os.ReadFile(source)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading %s", source)
+		}
+		return []*std.MemFile{{Name: info.Name(), Body: string(bz)}}, nil
+	}
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading %s", source)
+	}
+	var files []*std.MemFile
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".gno" {
+			continue
+		}
+		bz, err := os.ReadFile(filepath.Join(source, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrap(err, "reading %s", entry.Name())
+		}
+		files = append(files, &std.MemFile{Name: entry.Name(), Body: string(bz)})
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no .gno files found in %s", source)
+	}
+	return files, nil
+}
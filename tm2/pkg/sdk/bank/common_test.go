@@ -34,7 +34,7 @@ func setupTestEnv() testEnv {
 		authCapKey, std.ProtoBaseAccount,
 	)
 
-	bank := NewBankKeeper(acck)
+	bank := NewBankKeeper(authCapKey, acck)
 
 	return testEnv{ctx: ctx, bank: bank, acck: acck}
 }
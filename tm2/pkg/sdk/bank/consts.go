@@ -2,4 +2,22 @@ package bank
 
 const (
 	ModuleName = "bank"
+
+	// SupplyStoreKeyPrefix prefix for supply-by-denom store
+	SupplyStoreKeyPrefix = "/supply/"
+
+	// DenomStoreKeyPrefix prefix for metadata-by-denom store
+	DenomStoreKeyPrefix = "/denom/"
 )
+
+// SupplyStoreKey turns a denom to the key used to get its supply from the
+// store.
+func SupplyStoreKey(denom string) []byte {
+	return append([]byte(SupplyStoreKeyPrefix), denom...)
+}
+
+// DenomStoreKey turns a denom to the key used to get its metadata from the
+// store.
+func DenomStoreKey(denom string) []byte {
+	return append([]byte(DenomStoreKeyPrefix), denom...)
+}
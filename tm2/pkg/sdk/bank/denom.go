@@ -0,0 +1,40 @@
+package bank
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// DenomMetadata describes a denom, as set by its issuer.
+type DenomMetadata struct {
+	Denom    string `json:"denom"`
+	Issuer   string `json:"issuer"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// NewDenomMetadata returns the metadata of denom, issued by the package
+// path prefixing it.
+func NewDenomMetadata(denom string, symbol string, decimals uint8) DenomMetadata {
+	return DenomMetadata{
+		Denom:    denom,
+		Issuer:   std.DenomIssuer(denom),
+		Symbol:   symbol,
+		Decimals: decimals,
+	}
+}
+
+// ValidateBasic returns an error if the metadata is invalid.
+func (dm DenomMetadata) ValidateBasic() error {
+	if err := std.ValidateDenom(dm.Denom); err != nil {
+		return std.ErrInvalidCoins(err.Error())
+	}
+	if dm.Issuer != std.DenomIssuer(dm.Denom) {
+		return std.ErrUnauthorized(fmt.Sprintf("%s cannot describe the denom %s", dm.Issuer, dm.Denom))
+	}
+	if dm.Symbol == "" || len(dm.Symbol) > 16 {
+		return std.ErrInvalidCoins(fmt.Sprintf("invalid symbol %q of denom %s", dm.Symbol, dm.Denom))
+	}
+	return nil
+}
@@ -98,13 +98,21 @@ func (bh bankHandler) handleMsgMultiSend(ctx sdk.Context, msg MsgMultiSend) sdk.
 //----------------------------------------
 // Query
 
-// query balance path
-const QueryBalance = "balances"
+// query paths
+const (
+	QueryBalance = "balances"
+	QuerySupply  = "supply"
+	QueryDenom   = "denom"
+)
 
 func (bh bankHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
 	switch secondPart(req.Path) {
 	case QueryBalance:
 		return bh.queryBalance(ctx, req)
+	case QuerySupply:
+		return bh.querySupply(ctx, req)
+	case QueryDenom:
+		return bh.queryDenom(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("unknown bank query endpoint"))
@@ -135,6 +143,48 @@ func (bh bankHandler) queryBalance(ctx sdk.Context, req abci.RequestQuery) (res
 	return
 }
 
+// querySupply fetches the total supply of a denom, passed as path
+// component, or of all the denoms if none.
+func (bh bankHandler) querySupply(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	var supply interface{}
+	if denom := denomPart(req.Path); denom != "" {
+		supply = std.Coin{Denom: denom, Amount: bh.bank.TotalCoin(ctx, denom)}
+	} else {
+		supply = bh.bank.TotalSupply(ctx)
+	}
+
+	bz, err := amino.MarshalJSONIndent(supply, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
+// queryDenom fetches the metadata of a denom, passed as path component.
+func (bh bankHandler) queryDenom(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	denom := denomPart(req.Path)
+	dm, ok := bh.bank.GetDenomMetadata(ctx, denom)
+	if !ok {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrUnknownRequest(fmt.Sprintf("no metadata for denom %q", denom)))
+		return
+	}
+
+	bz, err := amino.MarshalJSONIndent(dm, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
 //----------------------------------------
 // misc
 
@@ -161,3 +211,15 @@ func thirdPart(path string) string {
 		return parts[2]
 	}
 }
+
+// returns the components of a path after the second one, which form the
+// denom of bank/supply/<denom> and bank/denom/<denom>; the denoms issued by
+// realms contain slashes.
+func denomPart(path string) string {
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) < 3 {
+		return ""
+	} else {
+		return parts[2]
+	}
+}
@@ -50,6 +50,52 @@ func TestBalances(t *testing.T) {
 	require.True(t, coins.AmountOf("foo") == 10)
 }
 
+func TestSupply(t *testing.T) {
+	env := setupTestEnv()
+	h := NewHandler(env.bank)
+	_, _, addr := tu.KeyTestPubAddr()
+	_, err := env.bank.AddCoins(env.ctx, addr, std.NewCoins(std.NewCoin("bar", 3), std.NewCoin("foo", 10)))
+	require.NoError(t, err)
+
+	req := abci.RequestQuery{
+		Path: fmt.Sprintf("bank/%s/%s", QuerySupply, "foo"),
+		Data: []byte{},
+	}
+	res := h.Query(env.ctx, req)
+	require.Nil(t, res.Error)
+	var coin std.Coin
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &coin))
+	require.Equal(t, std.NewCoin("foo", 10), coin)
+
+	req.Path = "bank/" + QuerySupply
+	res = h.Query(env.ctx, req)
+	require.Nil(t, res.Error)
+	var coins std.Coins
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &coins))
+	require.Equal(t, "3bar,10foo", coins.String())
+}
+
+func TestDenom(t *testing.T) {
+	env := setupTestEnv()
+	h := NewHandler(env.bank)
+	dm := NewDenomMetadata("gno.land/r/foo:bar", "BAR", 6)
+	require.NoError(t, env.bank.SetDenomMetadata(env.ctx, dm))
+
+	req := abci.RequestQuery{
+		Path: fmt.Sprintf("bank/%s/%s", QueryDenom, "gno.land/r/foo:bar"),
+		Data: []byte{},
+	}
+	res := h.Query(env.ctx, req)
+	require.Nil(t, res.Error)
+	var got DenomMetadata
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &got))
+	require.Equal(t, dm, got)
+
+	req.Path = fmt.Sprintf("bank/%s/%s", QueryDenom, "foo")
+	res = h.Query(env.ctx, req)
+	require.Error(t, res.Error)
+}
+
 func TestQuerierRouteNotFound(t *testing.T) {
 	env := setupTestEnv()
 	h := NewHandler(env.bank)
@@ -5,12 +5,15 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 // RegisterInvariants registers the bank module invariants
-func RegisterInvariants(ir sdk.InvariantRegistry, acck auth.AccountKeeper) {
+func RegisterInvariants(ir sdk.InvariantRegistry, bank BankKeeper) {
 	ir.RegisterRoute(ModuleName, "nonnegative-outstanding",
-		NonnegativeBalanceInvariant(acck))
+		NonnegativeBalanceInvariant(bank.acck))
+	ir.RegisterRoute(ModuleName, "total-supply",
+		TotalSupplyInvariant(bank))
 }
 
 // NonnegativeBalanceInvariant checks that all accounts in the application have non-negative balances
@@ -35,3 +38,20 @@ func NonnegativeBalanceInvariant(acck auth.AccountKeeper) sdk.Invariant {
 			fmt.Sprintf("amount of negative accounts found %d\n%s", count, msg)), broken
 	}
 }
+
+// TotalSupplyInvariant checks that the total supply of each denom is the sum
+// of its amounts in all accounts
+func TotalSupplyInvariant(bank BankKeeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		expected := std.Coins{}
+		for _, acc := range bank.acck.GetAllAccounts(ctx) {
+			expected = expected.AddUnsafe(acc.GetCoins())
+		}
+		supply := bank.TotalSupply(ctx)
+		// the denoms of the coins may differ, which IsEqual does not allow.
+		broken := expected.String() != supply.String()
+
+		return sdk.FormatInvariant(ModuleName, "total-supply",
+			fmt.Sprintf("\tsum of accounts coins: %s\n\tsupply tracked: %s\n", expected, supply)), broken
+	}
+}
@@ -3,11 +3,14 @@ package bank
 import (
 	"fmt"
 
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/log"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/overflow"
 )
 
 // bank.Keeper defines a module interface that facilitates the transfer of
@@ -21,22 +24,26 @@ type BankKeeperI interface {
 	SubtractCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error)
 	AddCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error)
 	SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) error
+	SetDenomMetadata(ctx sdk.Context, dm DenomMetadata) error
 }
 
 var _ BankKeeperI = BankKeeper{}
 
 // BBankKeeper only allows transfers between accounts without the possibility of
 // creating coins. It implements the BankKeeper interface.
+// It keeps the total supply of each denom in the store of key.
 type BankKeeper struct {
 	ViewKeeper
 
+	key  store.StoreKey
 	acck auth.AccountKeeper
 }
 
 // NewBankKeeper returns a new BankKeeper.
-func NewBankKeeper(acck auth.AccountKeeper) BankKeeper {
+func NewBankKeeper(key store.StoreKey, acck auth.AccountKeeper) BankKeeper {
 	return BankKeeper{
-		ViewKeeper: NewViewKeeper(acck),
+		ViewKeeper: NewViewKeeper(key, acck),
+		key:        key,
 		acck:       acck,
 	}
 }
@@ -158,15 +165,19 @@ func (bank BankKeeper) AddCoins(ctx sdk.Context, addr crypto.Address, amt std.Co
 	return newCoins, err
 }
 
-// SetCoins sets the coins at the addr.
+// SetCoins sets the coins at the addr, and updates the supply of their
+// denoms by the difference with the previous coins at the addr.
 func (bank BankKeeper) SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) error {
 	if !amt.IsValid() {
 		return std.ErrInvalidCoins(amt.String())
 	}
 
+	oldCoins := std.NewCoins()
 	acc := bank.acck.GetAccount(ctx, addr)
 	if acc == nil {
 		acc = bank.acck.NewAccountWithAddress(ctx, addr)
+	} else {
+		oldCoins = acc.GetCoins()
 	}
 
 	err := acc.SetCoins(amt)
@@ -175,6 +186,51 @@ func (bank BankKeeper) SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Co
 	}
 
 	bank.acck.SetAccount(ctx, acc)
+	bank.updateSupply(ctx, oldCoins, amt)
+	return nil
+}
+
+// updateSupply adds the difference between newCoins and oldCoins to the
+// supply of their denoms.
+func (bank BankKeeper) updateSupply(ctx sdk.Context, oldCoins, newCoins std.Coins) {
+	for _, coin := range oldCoins {
+		if newCoins.AmountOf(coin.Denom) == 0 {
+			bank.addSupply(ctx, coin.Denom, -coin.Amount)
+		}
+	}
+	for _, coin := range newCoins {
+		diff, ok := overflow.Sub64(coin.Amount, oldCoins.AmountOf(coin.Denom))
+		if !ok {
+			panic(fmt.Sprintf("supply of %s overflows", coin.Denom))
+		}
+		bank.addSupply(ctx, coin.Denom, diff)
+	}
+}
+
+// addSupply adds amount, which may be negative, to the supply of denom.
+func (bank BankKeeper) addSupply(ctx sdk.Context, denom string, amount int64) {
+	if amount == 0 {
+		return
+	}
+	supply, ok := overflow.Add64(bank.TotalCoin(ctx, denom), amount)
+	if !ok {
+		panic(fmt.Sprintf("supply of %s overflows", denom))
+	}
+	stor := ctx.Store(bank.key)
+	if supply == 0 {
+		stor.Delete(SupplyStoreKey(denom))
+		return
+	}
+	stor.Set(SupplyStoreKey(denom), amino.MustMarshal(supply))
+}
+
+// SetDenomMetadata sets the metadata of its denom.
+func (bank BankKeeper) SetDenomMetadata(ctx sdk.Context, dm DenomMetadata) error {
+	if err := dm.ValidateBasic(); err != nil {
+		return err
+	}
+	stor := ctx.Store(bank.key)
+	stor.Set(DenomStoreKey(dm.Denom), amino.MustMarshal(dm))
 	return nil
 }
 
@@ -186,18 +242,22 @@ func (bank BankKeeper) SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Co
 type ViewKeeperI interface {
 	GetCoins(ctx sdk.Context, addr crypto.Address) std.Coins
 	HasCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) bool
+	TotalCoin(ctx sdk.Context, denom string) int64
+	TotalSupply(ctx sdk.Context) std.Coins
+	GetDenomMetadata(ctx sdk.Context, denom string) (DenomMetadata, bool)
 }
 
 var _ ViewKeeperI = ViewKeeper{}
 
 // ViewKeeper implements a read only keeper implementation of ViewKeeperI.
 type ViewKeeper struct {
+	key  store.StoreKey
 	acck auth.AccountKeeper
 }
 
 // NewViewKeeper returns a new ViewKeeper.
-func NewViewKeeper(acck auth.AccountKeeper) ViewKeeper {
-	return ViewKeeper{acck: acck}
+func NewViewKeeper(key store.StoreKey, acck auth.AccountKeeper) ViewKeeper {
+	return ViewKeeper{key: key, acck: acck}
 }
 
 // Logger returns a module-specific logger.
@@ -218,3 +278,44 @@ func (view ViewKeeper) GetCoins(ctx sdk.Context, addr crypto.Address) std.Coins
 func (view ViewKeeper) HasCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) bool {
 	return view.GetCoins(ctx, addr).IsAllGTE(amt)
 }
+
+// TotalCoin returns the total supply of denom, i.e. the sum of its amounts
+// in all the accounts.
+func (view ViewKeeper) TotalCoin(ctx sdk.Context, denom string) int64 {
+	stor := ctx.Store(view.key)
+	bz := stor.Get(SupplyStoreKey(denom))
+	if bz == nil {
+		return 0
+	}
+	var supply int64
+	amino.MustUnmarshal(bz, &supply)
+	return supply
+}
+
+// TotalSupply returns the total supply of all the denoms.
+func (view ViewKeeper) TotalSupply(ctx sdk.Context) std.Coins {
+	stor := ctx.Store(view.key)
+	iter := store.PrefixIterator(stor, []byte(SupplyStoreKeyPrefix))
+	defer iter.Close()
+
+	supply := std.Coins{}
+	for ; iter.Valid(); iter.Next() {
+		var amount int64
+		amino.MustUnmarshal(iter.Value(), &amount)
+		denom := string(iter.Key()[len(SupplyStoreKeyPrefix):])
+		supply = append(supply, std.Coin{Denom: denom, Amount: amount})
+	}
+	return supply
+}
+
+// GetDenomMetadata returns the metadata of denom, and whether it was set.
+func (view ViewKeeper) GetDenomMetadata(ctx sdk.Context, denom string) (DenomMetadata, bool) {
+	stor := ctx.Store(view.key)
+	bz := stor.Get(DenomStoreKey(denom))
+	if bz == nil {
+		return DenomMetadata{}, false
+	}
+	var dm DenomMetadata
+	amino.MustUnmarshal(bz, &dm)
+	return dm, true
+}
@@ -91,7 +91,7 @@ func TestBankKeeper(t *testing.T) {
 	env := setupTestEnv()
 	ctx := env.ctx
 
-	bank := NewBankKeeper(env.acck)
+	bank := NewBankKeeper(env.bank.key, env.acck)
 
 	addr := crypto.AddressFromPreimage([]byte("addr1"))
 	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
@@ -133,10 +133,74 @@ func TestBankKeeper(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestKeeperSupply(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+	invariant := TotalSupplyInvariant(env.bank)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+
+	// genesis balances, issues and burns change the supply.
+	require.NoError(t, env.bank.SetCoins(ctx, addr, std.NewCoins(std.NewCoin("foocoin", 10))))
+	_, err := env.bank.AddCoins(ctx, addr2, std.NewCoins(std.NewCoin("barcoin", 5), std.NewCoin("foocoin", 5)))
+	require.NoError(t, err)
+	require.Equal(t, int64(15), env.bank.TotalCoin(ctx, "foocoin"))
+	require.Equal(t, int64(5), env.bank.TotalCoin(ctx, "barcoin"))
+
+	_, err = env.bank.SubtractCoins(ctx, addr2, std.NewCoins(std.NewCoin("barcoin", 5)))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), env.bank.TotalCoin(ctx, "barcoin"))
+	require.NoError(t, env.bank.SetCoins(ctx, addr, std.NewCoins(std.NewCoin("bazcoin", 1))))
+	require.Equal(t, int64(5), env.bank.TotalCoin(ctx, "foocoin"))
+
+	// transfers do not.
+	require.NoError(t, env.bank.SendCoins(ctx, addr2, addr, std.NewCoins(std.NewCoin("foocoin", 2))))
+	require.NoError(t, env.bank.InputOutputCoins(ctx,
+		[]Input{NewInput(addr, std.NewCoins(std.NewCoin("foocoin", 1)))},
+		[]Output{NewOutput(addr2, std.NewCoins(std.NewCoin("foocoin", 1)))}))
+	// failed subtractions do not either.
+	_, err = env.bank.SubtractCoins(ctx, addr, std.NewCoins(std.NewCoin("foocoin", 100)))
+	require.Error(t, err)
+	require.Equal(t, "1bazcoin,5foocoin", env.bank.TotalSupply(ctx).String())
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	// coins set outside of the bank keeper break the invariant.
+	acc := env.acck.GetAccount(ctx, addr)
+	require.NoError(t, acc.SetCoins(std.NewCoins(std.NewCoin("foocoin", 100))))
+	env.acck.SetAccount(ctx, acc)
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "sum of accounts coins: 104foocoin")
+}
+
+func TestKeeperDenomMetadata(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	_, ok := env.bank.GetDenomMetadata(ctx, "gno.land/r/foo:foocoin")
+	require.False(t, ok)
+
+	dm := NewDenomMetadata("gno.land/r/foo:foocoin", "FOO", 6)
+	require.Equal(t, "gno.land/r/foo", dm.Issuer)
+	require.NoError(t, env.bank.SetDenomMetadata(ctx, dm))
+	got, ok := env.bank.GetDenomMetadata(ctx, "gno.land/r/foo:foocoin")
+	require.True(t, ok)
+	require.Equal(t, dm, got)
+
+	// the issuer must be the prefix of the denom.
+	dm.Issuer = "gno.land/r/bar"
+	require.Error(t, env.bank.SetDenomMetadata(ctx, dm))
+	require.Error(t, env.bank.SetDenomMetadata(ctx, NewDenomMetadata("gno.land/r/foo:foocoin", "", 6)))
+	require.Error(t, env.bank.SetDenomMetadata(ctx, NewDenomMetadata("gno.land/r/foo:", "FOO", 6)))
+	require.NoError(t, env.bank.SetDenomMetadata(ctx, NewDenomMetadata("foocoin", "FOO", 0)))
+}
+
 func TestViewKeeper(t *testing.T) {
 	env := setupTestEnv()
 	ctx := env.ctx
-	view := NewViewKeeper(env.acck)
+	view := NewViewKeeper(env.bank.key, env.acck)
 
 	addr := crypto.AddressFromPreimage([]byte("addr1"))
 	acc := env.acck.NewAccountWithAddress(ctx, addr)
@@ -9,6 +9,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
@@ -107,15 +108,24 @@ func (bnk *SDKBanker) SendCoins(b32from, b32to crypto.Bech32Address, amt std.Coi
 }
 
 func (bnk *SDKBanker) TotalCoin(denom string) int64 {
-	panic("not yet implemented")
+	return bnk.vmk.bank.TotalCoin(bnk.ctx, denom)
 }
 
+// IssueCoin issues amount of denom to addr. The first issuance of a denom
+// with an issuer records its default metadata, with its name as symbol.
 func (bnk *SDKBanker) IssueCoin(b32addr crypto.Bech32Address, denom string, amount int64) {
 	addr := crypto.MustAddressFromString(string(b32addr))
 	_, err := bnk.vmk.bank.AddCoins(bnk.ctx, addr, std.Coins{std.Coin{denom, amount}})
 	if err != nil {
 		panic(err)
 	}
+	issuer := std.DenomIssuer(denom)
+	if issuer == "" {
+		return
+	}
+	if _, ok := bnk.vmk.bank.GetDenomMetadata(bnk.ctx, denom); !ok {
+		bnk.SetDenomMetadata(denom, denom[len(issuer)+1:], 0)
+	}
 }
 
 func (bnk *SDKBanker) RemoveCoin(b32addr crypto.Bech32Address, denom string, amount int64) {
@@ -125,3 +135,10 @@ func (bnk *SDKBanker) RemoveCoin(b32addr crypto.Bech32Address, denom string, amo
 		panic(err)
 	}
 }
+
+func (bnk *SDKBanker) SetDenomMetadata(denom string, symbol string, decimals uint8) {
+	err := bnk.vmk.bank.SetDenomMetadata(bnk.ctx, bank.NewDenomMetadata(denom, symbol, decimals))
+	if err != nil {
+		panic(err)
+	}
+}
@@ -14,11 +14,13 @@ type InvalidPkgPathError struct{ abciError }
 type (
 	InvalidStmtError struct{ abciError }
 	InvalidExprError struct{ abciError }
+	PkgExistError    struct{ abciError }
 )
 
 func (e InvalidPkgPathError) Error() string { return "invalid package path" }
 func (e InvalidStmtError) Error() string    { return "invalid statement" }
 func (e InvalidExprError) Error() string    { return "invalid expression" }
+func (e PkgExistError) Error() string       { return "package already exists" }
 
 func ErrInvalidPkgPath(msg string) error {
 	return errors.Wrap(InvalidPkgPathError{}, msg)
@@ -31,3 +33,7 @@ func ErrInvalidStmt(msg string) error {
 func ErrInvalidExpr(msg string) error {
 	return errors.Wrap(InvalidExprError{}, msg)
 }
+
+func ErrPkgAlreadyExists(msg string) error {
+	return errors.Wrap(PkgExistError{}, msg)
+}
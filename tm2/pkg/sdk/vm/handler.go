@@ -27,6 +27,8 @@ func (vh vmHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 		return vh.handleMsgAddPackage(ctx, msg)
 	case MsgCall:
 		return vh.handleMsgCall(ctx, msg)
+	case MsgRun:
+		return vh.handleMsgRun(ctx, msg)
 	default:
 		errMsg := fmt.Sprintf("unrecognized vm message type: %T", msg)
 		return abciResult(std.ErrUnknownRequest(errMsg))
@@ -47,7 +49,9 @@ func (vh vmHandler) handleMsgAddPackage(ctx sdk.Context, msg MsgAddPackage) sdk.
 	if err != nil {
 		return abciResult(err)
 	}
-	return sdk.Result{}
+	res := sdk.Result{}
+	res.Events = ctx.EventLogger().Events()
+	return res
 }
 
 // Handle MsgCall.
@@ -66,15 +70,28 @@ func (vh vmHandler) handleMsgCall(ctx sdk.Context, msg MsgCall) (res sdk.Result)
 		return abciResult(err)
 	}
 	res.Data = []byte(resstr)
+	res.Events = ctx.EventLogger().Events()
+	return
+}
+
+// Handle MsgRun.
+func (vh vmHandler) handleMsgRun(ctx sdk.Context, msg MsgRun) (res sdk.Result) {
+	amount, err := std.ParseCoins("1000000ugnot") // XXX calculate
+	if err != nil {
+		return abciResult(err)
+	}
+	err = vh.vm.bank.SendCoins(ctx, msg.Caller, auth.FeeCollectorAddress(), amount)
+	if err != nil {
+		return abciResult(err)
+	}
+	resstr := ""
+	resstr, err = vh.vm.Run(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	res.Data = []byte(resstr)
+	res.Events = ctx.EventLogger().Events()
 	return
-	/* TODO handle events.
-	ctx.EventManager().EmitEvent(
-		sdk.NewEvent(
-			sdk.EventTypeMessage,
-			sdk.NewAttribute(sdk.AttributeKeyXXX, types.AttributeValueXXX),
-		),
-	)
-	*/
 }
 
 //----------------------------------------
@@ -82,12 +99,15 @@ func (vh vmHandler) handleMsgCall(ctx sdk.Context, msg MsgCall) (res sdk.Result)
 
 // query paths
 const (
-	QueryPackage = "package"
-	QueryStore   = "store"
-	QueryRender  = "qrender"
-	QueryFuncs   = "qfuncs"
-	QueryEval    = "qeval"
-	QueryFile    = "qfile"
+	QueryPackage  = "package"
+	QueryStore    = "store"
+	QueryRender   = "qrender"
+	QueryFuncs    = "qfuncs"
+	QueryEval     = "qeval"
+	QueryEvalJSON = "qeval_json"
+	QueryFile     = "qfile"
+	QueryHistory  = "qhistory"
+	QueryObject   = "qobject"
 )
 
 func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
@@ -102,8 +122,14 @@ func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Resp
 		return vh.queryFuncs(ctx, req)
 	case QueryEval:
 		return vh.queryEval(ctx, req)
+	case QueryEvalJSON:
+		return vh.queryEvalJSON(ctx, req)
 	case QueryFile:
 		return vh.queryFile(ctx, req)
+	case QueryHistory:
+		return vh.queryHistory(ctx, req)
+	case QueryObject:
+		return vh.queryObject(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest(fmt.Sprintf(
@@ -179,6 +205,25 @@ func (vh vmHandler) queryEval(ctx sdk.Context, req abci.RequestQuery) (res abci.
 	return
 }
 
+// queryEvalJSON evaluates any expression in readonly mode and returns the
+// results as JSON.
+func (vh vmHandler) queryEvalJSON(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	reqData := string(req.Data)
+	reqParts := strings.Split(reqData, "\n")
+	if len(reqParts) != 2 {
+		panic("expected two lines in query input data")
+	}
+	pkgPath := reqParts[0]
+	expr := reqParts[1]
+	result, err := vh.vm.QueryEvalJSON(ctx, pkgPath, expr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = result
+	return
+}
+
 // queryFile returns the file bytes, or list of files if directory.
 // if file, res.Value is []byte("file").
 // if dir, res.Value is []byte("dir").
@@ -193,6 +238,42 @@ func (vh vmHandler) queryFile(ctx sdk.Context, req abci.RequestQuery) (res abci.
 	return
 }
 
+// queryHistory returns the published versions of a package as JSON.
+func (vh vmHandler) queryHistory(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath := string(req.Data)
+	hist := vh.vm.GetPackageHistory(ctx, pkgPath)
+	if len(hist) == 0 {
+		res = sdk.ABCIResponseQueryFromError(ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath)))
+		return
+	}
+	res.Data = []byte(hist.JSON())
+	return
+}
+
+// queryObject returns a value of the realm state, with the metadata of its
+// object, as JSON. The first line of the input is an object ID or a package
+// path, and the optional second line a selector.
+func (vh vmHandler) queryObject(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	reqData := string(req.Data)
+	reqParts := strings.Split(reqData, "\n")
+	if len(reqParts) > 2 {
+		panic("expected one or two lines in query input data")
+	}
+	target := reqParts[0]
+	selector := ""
+	if len(reqParts) == 2 {
+		selector = reqParts[1]
+	}
+	result, err := vh.vm.QueryObject(ctx, target, selector)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = result
+	return
+}
+
 //----------------------------------------
 // misc
 
@@ -3,6 +3,8 @@ package vm
 // TODO: move most of the logic in ROOT/gno.land/...
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -15,6 +17,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
 )
 
 const (
@@ -27,6 +30,7 @@ const (
 type VMKeeperI interface {
 	AddPackage(ctx sdk.Context, msg MsgAddPackage) error
 	Call(ctx sdk.Context, msg MsgCall) (res string, err error)
+	Run(ctx sdk.Context, msg MsgRun) (res string, err error)
 }
 
 var _ VMKeeperI = &VMKeeper{}
@@ -137,52 +141,61 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) error {
 		return ErrInvalidPkgPath(err.Error())
 	}
 	if pv := store.GetPackage(pkgPath, false); pv != nil {
-		// TODO: return error instead of panicking?
-		panic("package already exists: " + pkgPath)
+		return ErrPkgAlreadyExists(pkgPath)
+	}
+	if IsRunPkgPath(pkgPath) {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package path %s is reserved for MsgRun", pkgPath))
+	}
+	if err := vm.checkPackageVersion(ctx, creator, pkgPath); err != nil {
+		return err
+	}
+	if err := vm.checkNamespacePerm(ctx, creator, pkgPath); err != nil {
+		return err
 	}
 	// Pay deposit from creator.
 	pkgAddr := gno.DerivePkgAddr(pkgPath)
-
-	// TODO: ACLs.
-	// - if r/system/names does not exists -> skip validation.
-	// - loads r/system/names data state.
-	// - lookup r/system/names.namespaces for `{r,p}/NAMES`.
-	// - check if caller is in Admins or Editors.
-	// - check if namespace is not in pause.
-
 	err := vm.bank.SendCoins(ctx, creator, pkgAddr, deposit)
 	if err != nil {
 		return err
 	}
 	// Parse and run the files, construct *PV.
+	authParams := getAuthParams(ctx)
 	msgCtx := stdlibs.ExecContext{
-		ChainID:       ctx.ChainID(),
-		Height:        ctx.BlockHeight(),
-		Timestamp:     ctx.BlockTime().Unix(),
-		Msg:           msg,
-		OrigCaller:    creator.Bech32(),
-		OrigSend:      deposit,
-		OrigSendSpent: new(std.Coins),
-		OrigPkgAddr:   pkgAddr.Bech32(),
-		Banker:        NewSDKBanker(vm, ctx),
+		ChainID:                ctx.ChainID(),
+		Height:                 ctx.BlockHeight(),
+		Timestamp:              ctx.BlockTime().Unix(),
+		Msg:                    msg,
+		OrigCaller:             creator.Bech32(),
+		OrigSend:               deposit,
+		OrigSendSpent:          new(std.Coins),
+		OrigPkgAddr:            pkgAddr.Bech32(),
+		Banker:                 NewSDKBanker(vm, ctx),
+		EventLogger:            ctx.EventLogger(),
+		SigVerifyCostED25519:   authParams.SigVerifyCostED25519,
+		SigVerifyCostSecp256k1: authParams.SigVerifyCostSecp256k1,
 	}
 	// Parse and run the files, construct *PV.
+	params := vm.GetParams(ctx)
 	m2 := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:   "",
-			Output:    os.Stdout, // XXX
-			Store:     store,
-			Alloc:     store.GetAllocator(),
-			Context:   msgCtx,
-			MaxCycles: 10 * 1000 * 1000, // 10M cycles // XXX
+			PkgPath:     "",
+			Output:      os.Stdout, // XXX
+			Store:       store,
+			Alloc:       store.GetAllocator(),
+			Context:     msgCtx,
+			MaxCycles:   params.MaxCycles,
+			GasMeter:    ctx.GasMeter(),
+			GasPerCycle: params.GasPerCycle,
 		})
 	defer m2.Release()
 	m2.RunMemPackage(memPkg, true)
-	fmt.Println("CPUCYCLES addpkg", m2.Cycles)
+	vm.addPackageVersion(ctx, creator, pkgPath)
 	return nil
 }
 
 // Calls calls a public Gno function (for delivertx).
+// The JSON encoding of the results, if any, is emitted as a CallResultEvent.
 func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	pkgPath := msg.PkgPath // to import
 	fnc := msg.Func
@@ -229,30 +242,42 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	// Make context.
 	// NOTE: if this is too expensive,
 	// could it be safely partially memoized?
+	authParams := getAuthParams(ctx)
 	msgCtx := stdlibs.ExecContext{
-		ChainID:       ctx.ChainID(),
-		Height:        ctx.BlockHeight(),
-		Timestamp:     ctx.BlockTime().Unix(),
-		Msg:           msg,
-		OrigCaller:    caller.Bech32(),
-		OrigSend:      send,
-		OrigSendSpent: new(std.Coins),
-		OrigPkgAddr:   pkgAddr.Bech32(),
-		Banker:        NewSDKBanker(vm, ctx),
+		ChainID:                ctx.ChainID(),
+		Height:                 ctx.BlockHeight(),
+		Timestamp:              ctx.BlockTime().Unix(),
+		Msg:                    msg,
+		OrigCaller:             caller.Bech32(),
+		OrigSend:               send,
+		OrigSendSpent:          new(std.Coins),
+		OrigPkgAddr:            pkgAddr.Bech32(),
+		Banker:                 NewSDKBanker(vm, ctx),
+		EventLogger:            ctx.EventLogger(),
+		SigVerifyCostED25519:   authParams.SigVerifyCostED25519,
+		SigVerifyCostSecp256k1: authParams.SigVerifyCostSecp256k1,
 	}
 	// Construct machine and evaluate.
+	params := vm.GetParams(ctx)
 	m := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:   "",
-			Output:    os.Stdout, // XXX
-			Store:     store,
-			Context:   msgCtx,
-			Alloc:     store.GetAllocator(),
-			MaxCycles: 10 * 1000 * 1000, // 10M cycles // XXX
+			PkgPath:     "",
+			Output:      os.Stdout, // XXX
+			Store:       store,
+			Context:     msgCtx,
+			Alloc:       store.GetAllocator(),
+			MaxCycles:   params.MaxCycles,
+			GasMeter:    ctx.GasMeter(),
+			GasPerCycle: params.GasPerCycle,
 		})
 	m.SetActivePackage(mpv)
 	defer func() {
 		if r := recover(); r != nil {
+			if _, ok := r.(storetypes.OutOfGasException); ok {
+				// let the baseapp report std.ErrOutOfGas with the
+				// gas used so far.
+				panic(r)
+			}
 			err = errors.Wrap(fmt.Errorf("%v", r), "VM call panic: %v\n%s\n",
 				r, m.String())
 			return
@@ -260,15 +285,105 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 		m.Release()
 	}()
 	rtvs := m.Eval(xn)
-	fmt.Println("CPUCYCLES call", m.Cycles)
 	for i, rtv := range rtvs {
 		res = res + rtv.String()
 		if i < len(rtvs)-1 {
 			res += "\n"
 		}
 	}
+	if len(rtvs) > 0 {
+		// The JSON is best-effort: values that can't be encoded (e.g.
+		// cyclic ones) must not revert a call whose state changes are
+		// done, so the event is left out instead.
+		jres, err := gno.TypedValuesJSON(store, rtvs)
+		if err != nil {
+			ctx.Logger().Error("Failed to encode call results",
+				"pkgpath", pkgPath, "func", fnc, "err", err)
+		} else {
+			ctx.EventLogger().EmitEvent(CallResultEvent{
+				PkgPath: pkgPath,
+				Func:    fnc,
+				JSON:    string(jres),
+			})
+		}
+	}
 	return res, nil
-	// TODO pay for gas? TODO see context?
+}
+
+// Run executes the main function of a package sent by the caller (for
+// delivertx). The package is not persisted, and its output is returned.
+func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
+	caller := msg.Caller
+	memPkg := msg.Package
+	pkgPath := memPkg.Path
+	send := msg.Send
+	store := vm.getGnoStore(ctx)
+
+	// Validate arguments.
+	callerAcc := vm.acck.GetAccount(ctx, caller)
+	if callerAcc == nil {
+		return "", std.ErrUnknownAddress(fmt.Sprintf("account %s does not exist", caller))
+	}
+	if err := memPkg.Validate(); err != nil {
+		return "", ErrInvalidPkgPath(err.Error())
+	}
+	// Send send-coins to pkg from caller.
+	pkgAddr := gno.DerivePkgAddr(pkgPath)
+	err = vm.bank.SendCoins(ctx, caller, pkgAddr, send)
+	if err != nil {
+		return "", err
+	}
+	// Make context.
+	authParams := getAuthParams(ctx)
+	msgCtx := stdlibs.ExecContext{
+		ChainID:                ctx.ChainID(),
+		Height:                 ctx.BlockHeight(),
+		Timestamp:              ctx.BlockTime().Unix(),
+		Msg:                    msg,
+		OrigCaller:             caller.Bech32(),
+		OrigSend:               send,
+		OrigSendSpent:          new(std.Coins),
+		OrigPkgAddr:            pkgAddr.Bech32(),
+		Banker:                 NewSDKBanker(vm, ctx),
+		EventLogger:            ctx.EventLogger(),
+		SigVerifyCostED25519:   authParams.SigVerifyCostED25519,
+		SigVerifyCostSecp256k1: authParams.SigVerifyCostSecp256k1,
+	}
+	// Parse and run the files, construct *PV, then run main().
+	params := vm.GetParams(ctx)
+	buf := new(bytes.Buffer)
+	m := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:     "",
+			Output:      buf,
+			Store:       store,
+			Context:     msgCtx,
+			Alloc:       store.GetAllocator(),
+			MaxCycles:   params.MaxCycles,
+			GasMeter:    ctx.GasMeter(),
+			GasPerCycle: params.GasPerCycle,
+		})
+	// The run package is never saved; drop its nodes from the store
+	// caches, which outlive the transaction.
+	defer store.DelCachePackage(pkgPath)
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(storetypes.OutOfGasException); ok {
+				panic(r)
+			}
+			err = errors.Wrap(fmt.Errorf("%v", r), "VM run panic: %v\n%s\n",
+				r, m.String())
+			return
+		}
+		m.Release()
+	}()
+	m.RunMemPackage(memPkg, false)
+	// The run package is not a realm, but main still runs in an ephemeral
+	// realm that is never finalized, so that objects of other realms can
+	// not be modified directly and nothing is persisted for the package.
+	m.Realm = gno.NewRealm(pkgPath)
+	m.RunMain()
+	return buf.String(), nil
 }
 
 // QueryFuncs returns public facing function signatures.
@@ -369,7 +484,7 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 			Store:     store,
 			Context:   msgCtx,
 			Alloc:     alloc,
-			MaxCycles: 10 * 1000 * 1000, // 10M cycles // XXX
+			MaxCycles: vm.GetParams(ctx).MaxCycles,
 		})
 	defer func() {
 		if r := recover(); r != nil {
@@ -390,6 +505,53 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 	return res, nil
 }
 
+// QueryEvalJSON evaluates a gno expression (readonly, for ABCI queries).
+// The results are encoded as a JSON list, see gno.TypedValuesJSON.
+func (vm *VMKeeper) QueryEvalJSON(ctx sdk.Context, pkgPath string, expr string) (res []byte, err error) {
+	alloc := gno.NewAllocator(maxAllocQuery)
+	store := vm.getGnoStore(ctx)
+	pkgAddr := gno.DerivePkgAddr(pkgPath)
+	// Get Package.
+	pv := store.GetPackage(pkgPath, false)
+	if pv == nil {
+		err = ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+		return nil, err
+	}
+	// Parse expression.
+	xx, err := gno.ParseExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	// Construct new machine.
+	msgCtx := stdlibs.ExecContext{
+		ChainID:     ctx.ChainID(),
+		Height:      ctx.BlockHeight(),
+		Timestamp:   ctx.BlockTime().Unix(),
+		OrigPkgAddr: pkgAddr.Bech32(),
+		Banker:      NewSDKBanker(vm, ctx), // safe as long as ctx is a fork to be discarded.
+	}
+	m := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:   pkgPath,
+			Output:    os.Stdout, // XXX
+			Store:     store,
+			Context:   msgCtx,
+			Alloc:     alloc,
+			MaxCycles: vm.GetParams(ctx).MaxCycles,
+		})
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Wrap(fmt.Errorf("%v", r), "VM query eval json panic: %v\n%s\n",
+				r, m.String())
+			return
+		}
+		m.Release()
+	}()
+	rtvs := m.Eval(xx)
+	return gno.TypedValuesJSON(store, rtvs)
+}
+
 // QueryEvalString evaluates a gno expression (readonly, for ABCI queries).
 // The result is expected to be a single string (not a tuple).
 // TODO: modify query protocol to allow MsgEval.
@@ -429,7 +591,7 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 			Store:     store,
 			Context:   msgCtx,
 			Alloc:     alloc,
-			MaxCycles: 10 * 1000 * 1000, // 10M cycles // XXX
+			MaxCycles: vm.GetParams(ctx).MaxCycles,
 		})
 	defer func() {
 		if r := recover(); r != nil {
@@ -449,6 +611,34 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 	return res, nil
 }
 
+// QueryObject returns the value at selector, as a JSON gno.BrowseResult.
+// The target is either an object ID, and selector starts with a field or
+// an index, as in `.title` or `[3]`; or a package path, and selector
+// starts with a package level name, as in `board.threads[3].title`.
+func (vm *VMKeeper) QueryObject(ctx sdk.Context, target string, selector string) (res []byte, err error) {
+	store := vm.getGnoStore(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Wrap(fmt.Errorf("%v", r), "VM query object panic: %v", r)
+		}
+	}()
+	var br *gno.BrowseResult
+	var oid gno.ObjectID
+	if oid.UnmarshalAmino(target) == nil {
+		br, err = gno.BrowseObject(store, oid, selector)
+	} else {
+		if store.GetPackage(target, false) == nil {
+			return nil, ErrInvalidPkgPath(fmt.Sprintf(
+				"package not found: %s", target))
+		}
+		br, err = gno.BrowsePackage(store, target, selector)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(br)
+}
+
 func (vm *VMKeeper) QueryFile(ctx sdk.Context, filepath string) (res string, err error) {
 	store := vm.getGnoStore(ctx)
 	dirpath, filename := std.SplitFilepath(filepath)
@@ -3,14 +3,27 @@ package vm
 // TODO: move most of the logic in ROOT/gno.land/...
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/jaekwon/testify/assert"
 
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/stdlibs"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 )
 
 // Sending total send amount succeeds.
@@ -244,6 +257,109 @@ func Echo(msg string) string {
 	assert.Error(t, err)
 }
 
+// The total supply of a denom is the sum of the balances.
+func TestVMKeeperTotalCoin(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" and "addr2" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	env.bank.SetCoins(ctx, addr2, std.MustParseCoins("5000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+import "std"
+
+func Total(denom string) int64 {
+	banker := std.GetBanker(std.BankerTypeReadonly)
+	return banker.TotalCoin(denom)
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	msg2 := NewMsgCall(addr, std.MustParseCoins("1000ugnot"), pkgPath, "Total", []string{"ugnot"})
+	res, err := env.vmk.Call(ctx, msg2)
+	assert.NoError(t, err)
+	assert.Equal(t, `(10005000 int64)`, res)
+	msg2 = NewMsgCall(addr, nil, pkgPath, "Total", []string{"foo"})
+	res, err = env.vmk.Call(ctx, msg2)
+	assert.NoError(t, err)
+	assert.Equal(t, `(0 int64)`, res)
+}
+
+func TestVMKeeperRealmIssue(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+import "std"
+
+func Issue(denom string, amount int64) {
+	banker := std.GetBanker(std.BankerTypeRealmIssue)
+	banker.IssueCoin(std.GetOrigCaller(), denom, amount)
+}
+
+func Remove(denom string, amount int64) {
+	banker := std.GetBanker(std.BankerTypeRealmIssue)
+	banker.RemoveCoin(std.GetOrigCaller(), denom, amount)
+}
+
+func SetMetadata(denom string, symbol string, decimals uint8) {
+	banker := std.GetBanker(std.BankerTypeRealmIssue)
+	banker.SetDenomMetadata(denom, symbol, decimals)
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	// the realm can issue and remove its own denoms.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Issue", []string{"gno.land/r/test:tok", "100"}))
+	assert.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Remove", []string{"gno.land/r/test:tok", "40"}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(60), env.bank.GetCoins(ctx, addr).AmountOf("gno.land/r/test:tok"))
+	dm, ok := env.bank.GetDenomMetadata(ctx, "gno.land/r/test:tok")
+	assert.True(t, ok)
+	assert.Equal(t, bank.NewDenomMetadata("gno.land/r/test:tok", "tok", 0), dm)
+
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "SetMetadata", []string{"gno.land/r/test:tok", "TOK", "6"}))
+	assert.NoError(t, err)
+	dm, _ = env.bank.GetDenomMetadata(ctx, "gno.land/r/test:tok")
+	assert.Equal(t, bank.NewDenomMetadata("gno.land/r/test:tok", "TOK", 6), dm)
+
+	// but not the native or other denoms.
+	for _, denom := range []string{"gno.land/r/other:tok", "gno.land/r/test/sub:tok"} {
+		_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Issue", []string{denom, "100"}))
+		assert.Error(t, err, denom)
+		assert.Contains(t, err.Error(), `can only use the denoms of the realm "gno.land/r/test"`, denom)
+		assert.Equal(t, int64(0), env.bank.TotalCoin(ctx, denom), denom)
+	}
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Issue", []string{"ugnot", "100"}))
+	assert.Error(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Remove", []string{"ugnot", "100"}))
+	assert.Error(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "SetMetadata", []string{"ugnot", "GNOT", "6"}))
+	assert.Error(t, err)
+	assert.Equal(t, int64(10000000), env.bank.TotalCoin(ctx, "ugnot"))
+}
+
 // Assign admin as OrigCaller on deploying the package.
 func TestVMKeeperOrigCallerInit(t *testing.T) {
 	env := setupTestEnv()
@@ -258,7 +374,7 @@ func TestVMKeeperOrigCallerInit(t *testing.T) {
 
 	// Create test package.
 	files := []*std.MemFile{
-		{"init.gno", `
+		{Name: "init.gno", Body: `
 package test
 
 import "std"
@@ -297,3 +413,904 @@ func GetAdmin() string {
 	assert.NoError(t, err)
 	assert.Equal(t, res, addrString)
 }
+
+// VM execution consumes gas from the context's gas meter.
+func TestVMKeeperCallGas(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+func Loop(n int) int {
+	sum := 0
+	for i := 0; i < n; i++ {
+		sum += i
+	}
+	return sum
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	// More iterations consume more gas.
+	gasOf := func(arg string) int64 {
+		gctx := ctx.WithGasMeter(store.NewInfiniteGasMeter())
+		msg2 := NewMsgCall(addr, nil, pkgPath, "Loop", []string{arg})
+		_, err := env.vmk.Call(gctx, msg2)
+		assert.NoError(t, err)
+		return gctx.GasMeter().GasConsumed()
+	}
+	gas10, gas100 := gasOf("10"), gasOf("100")
+	assert.True(t, gas10 > 0)
+	assert.True(t, gas100 > gas10)
+
+	// The rate is taken from the stored params.
+	params := NewParams(DefaultGasPerCycle*2, DefaultMaxCycles)
+	env.vmk.SetParams(ctx, params)
+	gas100x2 := gasOf("100")
+	assert.True(t, gas100x2 > gas100)
+}
+
+// Realms verify signatures with std.VerifySignature, for the gas of the ante
+// handler.
+func TestVMKeeperVerifySignature(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+import (
+	"encoding/hex"
+	"std"
+)
+
+func Verify(pubKey string, msg string, sig string) bool {
+	bz, err := hex.DecodeString(sig)
+	if err != nil {
+		panic(err)
+	}
+	return std.VerifySignature(pubKey, []byte(msg), bz)
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	priv := secp256k1.GenPrivKey()
+	sig, err := priv.Sign([]byte("hello"))
+	assert.NoError(t, err)
+	pubKey := crypto.PubKeyToBech32(priv.PubKey())
+	verify := func(ctx sdk.Context, msg string) (string, int64) {
+		gctx := ctx.WithGasMeter(store.NewInfiniteGasMeter())
+		msg2 := NewMsgCall(addr, nil, pkgPath, "Verify", []string{pubKey, msg, hex.EncodeToString(sig)})
+		res, err := env.vmk.Call(gctx, msg2)
+		assert.NoError(t, err)
+		return res, gctx.GasMeter().GasConsumed()
+	}
+	res, gas := verify(ctx, "hello")
+	assert.Equal(t, `(true bool)`, res)
+	res, _ = verify(ctx, "hullo")
+	assert.Equal(t, `(false bool)`, res)
+
+	// The cost is taken from the auth params of the context.
+	params := auth.DefaultParams()
+	params.SigVerifyCostSecp256k1 *= 2
+	_, gas2 := verify(ctx.WithValue(auth.AuthParamsContextKey{}, params), "hello")
+	assert.Equal(t, auth.DefaultSigVerifyCostSecp256k1, gas2-gas)
+}
+
+// Running out of gas panics with store.OutOfGasException, to be turned into
+// std.ErrOutOfGas by the baseapp.
+func TestVMKeeperCallOutOfGas(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+func Loop() {
+	for {
+	}
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	ctx = ctx.WithGasMeter(store.NewGasMeter(100000))
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Loop", []string{})
+	defer func() {
+		r := recover()
+		_, ok := r.(store.OutOfGasException)
+		assert.True(t, ok, "expected out of gas, got %v", r)
+	}()
+	env.vmk.Call(ctx, msg2)
+	t.Fatal("expected panic")
+}
+
+// Realms emit events with std.Emit.
+func TestVMKeeperEmit(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+import "std"
+
+func init() {
+	std.Emit("deployed")
+}
+
+func Transfer(to string) {
+	std.Emit("transfer", "from", std.GetOrigCaller().String(), "to", to)
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+	assert.Equal(t, []abci.Event{
+		stdlibs.GnoEvent{Type: "deployed", PkgPath: pkgPath, Attributes: []abci.EventAttribute{}},
+	}, ctx.EventLogger().Events())
+
+	ctx = ctx.WithEventLogger(sdk.NewEventLogger())
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Transfer", []string{"bob"})
+	_, err = env.vmk.Call(ctx, msg2)
+	assert.NoError(t, err)
+	events := ctx.EventLogger().Events()
+	assert.Equal(t, []abci.Event{
+		stdlibs.GnoEvent{Type: "transfer", PkgPath: pkgPath, Attributes: []abci.EventAttribute{
+			{Key: "from", Value: addr.String()},
+			{Key: "to", Value: "bob"},
+		}},
+	}, events)
+
+	// Events are amino encodable, as part of abci results.
+	res := abci.ResponseDeliverTx{}
+	res.Events = events
+	bz := amino.MustMarshal(res)
+	var res2 abci.ResponseDeliverTx
+	amino.MustUnmarshal(bz, &res2)
+	assert.Equal(t, res, res2)
+}
+
+// std.Emit requires key/value pairs.
+func TestVMKeeperEmitInvalid(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+import "std"
+
+func Emit() {
+	std.Emit("transfer", "from")
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Emit", []string{})
+	_, err = env.vmk.Call(ctx, msg2)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "key/value pairs"))
+	assert.Empty(t, ctx.EventLogger().Events())
+}
+
+// Run a main package calling a realm with non-primitive arguments.
+func TestVMKeeperRun(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+import "std"
+
+type Point struct {
+	X, Y int
+}
+
+var total int
+
+func Add(pts ...Point) int {
+	for _, pt := range pts {
+		total += pt.X + pt.Y
+	}
+	return total
+}
+
+func Caller() string {
+	return std.GetOrigCaller().String()
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	// Run a script calling the realm several times.
+	files = []*std.MemFile{
+		{Name: "main.gno", Body: `
+package main
+
+import "gno.land/r/test"
+
+func main() {
+	println(test.Add(test.Point{1, 2}, test.Point{3, 4}))
+	println(test.Add(test.Point{X: 5}))
+	println(test.Caller())
+}`},
+	}
+	msg2 := NewMsgRun(addr, nil, files)
+	assert.NoError(t, msg2.ValidateBasic())
+	numPkgs := env.vmk.getGnoStore(ctx).NumMemPackages()
+	res, err := env.vmk.Run(ctx, msg2)
+	assert.NoError(t, err)
+	assert.Equal(t, "10\n15\n"+addr.String()+"\n", res)
+
+	// The realm state was updated, the run package was not persisted.
+	res, err = env.vmk.QueryEval(ctx, pkgPath, "Add()")
+	assert.NoError(t, err)
+	assert.Equal(t, "(15 int)", res)
+	assert.Equal(t, numPkgs, env.vmk.getGnoStore(ctx).NumMemPackages())
+	// Nor its nodes kept in the store cache.
+	loc := gno.PackageNodeLocation(msg2.Package.Path)
+	assert.Nil(t, env.vmk.getGnoStore(ctx).GetBlockNodeSafe(loc))
+
+	// MsgRun is charged the same fee as MsgCall.
+	before := env.bank.GetCoins(ctx, addr)
+	rres := NewHandler(env.vmk).Process(ctx, msg2)
+	assert.True(t, rres.IsOK(), rres.Log)
+	assert.Equal(t, "25\n30\n"+addr.String()+"\n", string(rres.Data))
+	assert.Equal(t, before.Sub(std.MustParseCoins("1000000ugnot")), env.bank.GetCoins(ctx, addr))
+}
+
+// The run package is ephemeral: it can not modify realms directly, nor
+// be added as a package.
+func TestVMKeeperRunEphemeral(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+type Counter struct {
+	N int
+}
+
+var Count = &Counter{}
+
+func Inc() int {
+	Count.N++
+	return Count.N
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	// Package variables of the run package are not persisted.
+	files = []*std.MemFile{
+		{Name: "main.gno", Body: `
+package main
+
+import "gno.land/r/test"
+
+var local = &test.Counter{}
+
+func main() {
+	local.N = test.Inc()
+	println(local.N)
+}`},
+	}
+	res, err := env.vmk.Run(ctx, NewMsgRun(addr, nil, files))
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n", res)
+
+	// Objects of a realm can not be modified directly.
+	files = []*std.MemFile{
+		{Name: "main.gno", Body: `
+package main
+
+import "gno.land/r/test"
+
+func main() {
+	test.Count.N = 10
+}`},
+	}
+	_, err = env.vmk.Run(ctx, NewMsgRun(addr, nil, files))
+	assert.Error(t, err)
+	res, err = env.vmk.QueryEval(ctx, pkgPath, "Count.N")
+	assert.NoError(t, err)
+	assert.Equal(t, "(1 int)", res)
+
+	// The run package can not issue coins.
+	files = []*std.MemFile{
+		{Name: "main.gno", Body: `
+package main
+
+import "std"
+
+func main() {
+	std.GetBanker(std.BankerTypeRealmIssue)
+}`},
+	}
+	_, err = env.vmk.Run(ctx, NewMsgRun(addr, nil, files))
+	assert.Error(t, err)
+
+	// The run path is reserved.
+	files = []*std.MemFile{
+		{Name: "run.gno", Body: "package run\n"},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, RunPkgPath(addr), files))
+	assert.Error(t, err)
+}
+
+// MsgRun only accepts a main package at the caller's run path.
+func TestMsgRunValidateBasic(t *testing.T) {
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	files := []*std.MemFile{
+		{Name: "main.gno", Body: "package main\n\nfunc main() {}\n"},
+	}
+
+	msg := NewMsgRun(addr, nil, files)
+	assert.NoError(t, msg.ValidateBasic())
+
+	msg = NewMsgRun(addr, nil, files)
+	msg.Package.Path = "gno.land/r/test"
+	assert.Error(t, msg.ValidateBasic())
+
+	msg = NewMsgRun(addr, nil, files)
+	msg.Package.Name = "test"
+	assert.Error(t, msg.ValidateBasic())
+
+	msg = NewMsgRun(crypto.Address{}, nil, files)
+	assert.Error(t, msg.ValidateBasic())
+}
+
+// Results are also available as JSON.
+func TestVMKeeperEvalJSON(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+import "std"
+
+type Point struct {
+	X, Y int
+	Tags []string
+}
+
+type Node struct {
+	Next *Node
+}
+
+var (
+	pt    = &Point{X: 1, Y: 2, Tags: []string{"a"}}
+	coins = std.Coins{std.Coin{"ugnot", 10}}
+	owner = std.Address("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+	loop  *Node
+)
+
+func init() {
+	loop = &Node{}
+	loop.Next = loop
+}
+
+func Multi() (*Point, std.Coins, std.Address, map[string]int8, []byte, error) {
+	ages := map[string]int8{"bob": 3}
+	return pt, coins, owner, ages, []byte("hi"), nil
+}
+
+func Loop() *Node {
+	return loop
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	res, err := env.vmk.QueryEvalJSON(ctx, pkgPath, "Multi()")
+	assert.NoError(t, err)
+	assert.Equal(t, `[`+
+		`{"T":"*gno.land/r/test.Point","V":{"T":"gno.land/r/test.Point","V":{`+
+		`"Tags":{"T":"[]string","V":[{"T":"string","V":"a"}]},`+
+		`"X":{"T":"int","V":"1"},"Y":{"T":"int","V":"2"}}}},`+
+		`{"T":"std.Coins","V":[{"T":"std.Coin","V":{"Amount":{"T":"int64","V":"10"},"Denom":{"T":"string","V":"ugnot"}}}]},`+
+		`{"T":"std.Address","V":"g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"},`+
+		`{"T":"map[string]int8","V":[{"Key":{"T":"string","V":"bob"},"Value":{"T":"int8","V":3}}]},`+
+		`{"T":"[]uint8","V":"aGk="},`+
+		`{"T":null,"V":null}]`, string(res))
+
+	_, err = env.vmk.QueryEvalJSON(ctx, pkgPath, "Loop()")
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "cyclic value"))
+
+	// MsgCall emits the same JSON as an event, its Data is unchanged.
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Multi", []string{})
+	cres := NewHandler(env.vmk).Process(ctx, msg2)
+	assert.True(t, cres.IsOK(), cres.Log)
+	assert.True(t, strings.HasPrefix(string(cres.Data), "(&"))
+	assert.Equal(t, CallResultEvent{
+		PkgPath: pkgPath,
+		Func:    "Multi",
+		JSON:    string(res),
+	}, cres.Events[len(cres.Events)-1])
+
+	// A call whose results cannot be encoded succeeds, without the event.
+	numEvents := len(cres.Events)
+	msg3 := NewMsgCall(addr, nil, pkgPath, "Loop", []string{})
+	cres = NewHandler(env.vmk).Process(ctx, msg3)
+	assert.True(t, cres.IsOK(), cres.Log)
+	assert.Len(t, cres.Events, numEvents)
+}
+
+// Realm state can be browsed with vm/qobject.
+func TestVMKeeperQueryObject(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+type Thread struct {
+	Title string
+	Likes map[string]int
+}
+
+type Board struct {
+	Threads []*Thread
+}
+
+var board = &Board{}
+
+func init() {
+	for _, title := range []string{"a", "b"} {
+		board.Threads = append(board.Threads, &Thread{
+			Title: title,
+			Likes: map[string]int{"bob": 1},
+		})
+	}
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	query := func(data string) (gno.BrowseResult, abci.ResponseQuery) {
+		res := NewHandler(env.vmk).Query(ctx, abci.RequestQuery{
+			Path: "vm/qobject",
+			Data: []byte(data),
+		})
+		var br gno.BrowseResult
+		if res.IsOK() {
+			assert.NoError(t, json.Unmarshal(res.Data, &br))
+		}
+		return br, res
+	}
+
+	br, res := query(pkgPath + "\nboard.Threads[1].Title")
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, "string", br.Type)
+	assert.Equal(t, map[string]interface{}{"T": "string", "V": "b"}, br.Value)
+	// the title is held by the second thread.
+	thread := br.ObjectID
+	assert.NotEmpty(t, br.OwnerID)
+	assert.NotEmpty(t, br.Hash)
+	assert.Equal(t, 1, br.RefCount)
+
+	br, res = query(pkgPath + "\nboard.Threads[1].Likes[\"bob\"]")
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, map[string]interface{}{"T": "int", "V": "1"}, br.Value)
+
+	// the same value, from the thread object.
+	br, res = query(thread + "\n.Title")
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, map[string]interface{}{"T": "string", "V": "b"}, br.Value)
+	assert.Equal(t, thread, br.ObjectID)
+
+	br, res = query(thread)
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, "gno.land/r/test.Thread", br.Type)
+
+	// the package block.
+	br, res = query(pkgPath)
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, "block", br.Type)
+	assert.Contains(t, br.Value, "board")
+
+	for _, data := range []string{
+		pkgPath + "\nboard.Threads[2]",
+		pkgPath + "\nboard.Posts",
+		pkgPath + "\nnothing",
+		pkgPath + "\nboard.Threads[0].Likes[\"alice\"]",
+		"gno.land/r/nothing\nboard",
+		"0000000000000000000000000000000000000000:1",
+	} {
+		_, res = query(data)
+		assert.False(t, res.IsOK(), data)
+	}
+}
+
+// Packages are upgraded by publishing versioned paths.
+func TestVMKeeperAddPackageVersions(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" and "addr2" some gnots.
+	addr1 := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	for _, addr := range []crypto.Address{addr1, addr2} {
+		acc := env.acck.NewAccountWithAddress(ctx, addr)
+		env.acck.SetAccount(ctx, acc)
+		env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+	}
+
+	addPkg := func(ctx sdk.Context, creator crypto.Address, pkgPath string) error {
+		files := []*std.MemFile{
+			{Name: "foo.gno", Body: "package foo\n\nfunc Version() string { return \"" + pkgPath + "\" }\n"},
+		}
+		return env.vmk.AddPackage(ctx, NewMsgAddPackage(creator, pkgPath, files))
+	}
+
+	err := addPkg(ctx, addr1, "gno.land/p/demo/foo")
+	assert.NoError(t, err)
+
+	// Paths cannot be reused.
+	err = addPkg(ctx, addr1, "gno.land/p/demo/foo")
+	assert.Error(t, err)
+	assert.IsType(t, PkgExistError{}, errors.Cause(err))
+
+	// Only the creator may publish new versions.
+	err = addPkg(ctx, addr2, "gno.land/p/demo/foo/v2")
+	assert.Error(t, err)
+	assert.IsType(t, std.UnauthorizedError{}, errors.Cause(err))
+	err = addPkg(ctx, addr1, "gno.land/p/demo/foo/v2")
+	assert.NoError(t, err)
+
+	// Versions must increase.
+	err = addPkg(ctx, addr1, "gno.land/p/demo/foo/v1")
+	assert.Error(t, err)
+	assert.IsType(t, InvalidPkgPathError{}, errors.Cause(err))
+
+	// The upgrade authority may publish new versions too.
+	params := env.vmk.GetParams(ctx)
+	assert.Equal(t, DefaultParams(), params)
+	params.UpgradeAuthority = addr2
+	env.vmk.SetParams(ctx, params)
+	err = addPkg(ctx, addr2, "gno.land/p/demo/foo/v3")
+	assert.NoError(t, err)
+
+	res := NewHandler(env.vmk).Query(ctx, abci.RequestQuery{
+		Path: "vm/qhistory",
+		Data: []byte("gno.land/p/demo/foo/v2"),
+	})
+	assert.True(t, res.IsOK())
+	var hist PackageHistory
+	amino.MustUnmarshalJSON(res.Data, &hist)
+	assert.Equal(t, PackageHistory{
+		{Path: "gno.land/p/demo/foo", Version: 1, Creator: addr1},
+		{Path: "gno.land/p/demo/foo/v2", Version: 2, Creator: addr1},
+		{Path: "gno.land/p/demo/foo/v3", Version: 3, Creator: addr2},
+	}, hist)
+
+	res = NewHandler(env.vmk).Query(ctx, abci.RequestQuery{
+		Path: "vm/qhistory",
+		Data: []byte("gno.land/p/demo/bar"),
+	})
+	assert.False(t, res.IsOK())
+
+	// Only the owner of the namespace may first publish at a /vN path,
+	// which would take over the base path.
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 1})
+	err = addPkg(ctx, addr2, "gno.land/p/demo/bar/v2")
+	assert.Error(t, err)
+	assert.IsType(t, std.UnauthorizedError{}, errors.Cause(err))
+	err = addPkg(ctx, addr1, "gno.land/p/demo/bar")
+	assert.NoError(t, err)
+	err = addPkg(ctx, addr2, "gno.land/p/"+addr2.String()+"/bar/v2")
+	assert.NoError(t, err)
+}
+
+func TestSplitPkgVersion(t *testing.T) {
+	cases := []struct {
+		pkgPath string
+		base    string
+		version int
+	}{
+		{"gno.land/p/demo/foo", "gno.land/p/demo/foo", 1},
+		{"gno.land/p/demo/foo/v1", "gno.land/p/demo/foo", 1},
+		{"gno.land/p/demo/foo/v12", "gno.land/p/demo/foo", 12},
+		{"gno.land/r/demo/foo/v0", "gno.land/r/demo/foo/v0", 1},
+		{"gno.land/r/demo/foo/v02", "gno.land/r/demo/foo/v02", 1},
+		{"gno.land/r/demo/foo/vx", "gno.land/r/demo/foo/vx", 1},
+	}
+	for _, c := range cases {
+		base, version := SplitPkgVersion(c.pkgPath)
+		assert.Equal(t, c.base, base, c.pkgPath)
+		assert.Equal(t, c.version, version, c.pkgPath)
+	}
+}
+
+func TestVMKeeperAddPackageNamespaces(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" and "addr2" some gnots.
+	addr1 := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	for _, addr := range []crypto.Address{addr1, addr2} {
+		acc := env.acck.NewAccountWithAddress(ctx, addr)
+		env.acck.SetAccount(ctx, acc)
+		env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+	}
+
+	addPkg := func(ctx sdk.Context, creator crypto.Address, pkgPath string) error {
+		files := []*std.MemFile{
+			{Name: "foo.gno", Body: "package foo\n\nfunc Path() string { return \"" + pkgPath + "\" }\n"},
+		}
+		return env.vmk.AddPackage(ctx, NewMsgAddPackage(creator, pkgPath, files))
+	}
+
+	// Without the registry, any namespace can be used.
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 1})
+	err := addPkg(ctx, addr2, "gno.land/p/bar/foo")
+	assert.NoError(t, err)
+
+	// Deploy a registry at genesis, where only addr1 may use "demo".
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id"})
+	files := []*std.MemFile{
+		{Name: "names.gno", Body: `package names
+
+import "std"
+
+func HasPerm(namespace string, addr std.Address) bool {
+	return namespace == "demo" && addr == "` + addr1.String() + `"
+}
+`},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr1, NamesPkgPath, files))
+	assert.NoError(t, err)
+
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 1})
+	err = addPkg(ctx, addr2, "gno.land/p/demo/foo")
+	assert.Error(t, err)
+	assert.IsType(t, std.UnauthorizedError{}, errors.Cause(err))
+	err = addPkg(ctx, addr1, "gno.land/p/demo/foo")
+	assert.NoError(t, err)
+	err = addPkg(ctx, addr1, "gno.land/r/baz/foo")
+	assert.Error(t, err)
+
+	// An address may always use its own namespace.
+	err = addPkg(ctx, addr2, "gno.land/r/"+addr2.String()+"/foo")
+	assert.NoError(t, err)
+}
+
+// The registry is evaluated in readonly mode, it can't write state.
+func TestVMKeeperNamespacesReadOnly(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Deploy a registry at genesis, which counts its calls.
+	files := []*std.MemFile{
+		{Name: "names.gno", Body: `package names
+
+import "std"
+
+var calls int
+
+func HasPerm(namespace string, addr std.Address) bool {
+	calls = calls + 1
+	return true
+}
+
+func Calls() int {
+	return calls
+}
+`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, NamesPkgPath, files))
+	assert.NoError(t, err)
+
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 1})
+	files = []*std.MemFile{
+		{Name: "foo.gno", Body: "package foo\n"},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/p/demo/foo", files))
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "readonly"), err.Error())
+
+	res, err := env.vmk.QueryEval(ctx, NamesPkgPath, "Calls()")
+	assert.NoError(t, err)
+	assert.Equal(t, "(0 int)", res)
+}
+
+func TestPkgNamespace(t *testing.T) {
+	for pkgPath, namespace := range map[string]string{
+		"gno.land/p/demo/avl":     "demo",
+		"gno.land/r/demo/boards":  "demo",
+		"gno.land/r/system/names": "system",
+		"gno.land/x/demo/foo":     "",
+		"gno.land/p":              "",
+	} {
+		assert.Equal(t, namespace, PkgNamespace(pkgPath), pkgPath)
+	}
+}
+
+// Instances of generics are re-created when a new keeper is initialized
+// on the same store, e.g. upon restart.
+func TestVMKeeperGenericsReload(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create a generic package and a realm using it.
+	files := []*std.MemFile{
+		{Name: "box.gno", Body: `package box
+
+type Box[T any] struct {
+	v T
+}
+
+func New[T any](v T) *Box[T] {
+	return &Box[T]{v: v}
+}
+
+func (b *Box[T]) Get() T {
+	return b.v
+}
+
+func (b *Box[T]) Set(v T) {
+	b.v = v
+}
+`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/p/demo/box", files))
+	assert.NoError(t, err)
+
+	files = []*std.MemFile{
+		{Name: "test.gno", Body: `package test
+
+import "gno.land/p/demo/box"
+
+type Pair[K comparable, V any] struct {
+	Key K
+	Val V
+}
+
+func (p Pair[K, V]) String() string {
+	return string(p.Key)
+}
+
+var (
+	b    = box.New[int](1)
+	s    = box.New("a") // inferred
+	newB = box.New[int]
+	p    = Pair[string, int]{Key: "k", Val: 1}
+)
+
+func Inc() int {
+	b.Set(b.Get() + 1)
+	return b.Get()
+}
+
+func Other() string {
+	return s.Get() + p.String() + string(rune('0'+newB(2).Get()))
+}
+`},
+	}
+	pkgPath := "gno.land/r/test"
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	assert.NoError(t, err)
+
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", []string{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "(2 int)", res)
+
+	// Initialize a new keeper on the same store.
+	vmk := NewVMKeeper(env.vmk.baseKey, env.vmk.iavlKey, env.acck, env.bank, env.vmk.stdlibsDir)
+	assert.NotPanics(t, func() { vmk.Initialize(ctx.MultiStore()) })
+
+	res, err = vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", []string{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "(3 int)", res)
+	res, err = vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Other", []string{}))
+	assert.NoError(t, err)
+	assert.Equal(t, `("ak2" string)`, res)
+}
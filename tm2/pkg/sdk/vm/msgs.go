@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"fmt"
 	"strings"
 
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
@@ -135,3 +136,92 @@ func (msg MsgCall) GetSigners() []crypto.Address {
 func (msg MsgCall) GetReceived() std.Coins {
 	return msg.Send
 }
+
+//----------------------------------------
+// MsgRun
+
+// MsgRun - executes arbitrary Gno code.
+type MsgRun struct {
+	Caller  crypto.Address  `json:"caller" yaml:"caller"`
+	Send    std.Coins       `json:"send" yaml:"send"`
+	Package *std.MemPackage `json:"package" yaml:"package"`
+}
+
+var _ std.Msg = MsgRun{}
+
+// NewMsgRun - run a main package with files. The package is not persisted,
+// and its path is derived from the caller, see RunPkgPath.
+func NewMsgRun(caller crypto.Address, send std.Coins, files []*std.MemFile) MsgRun {
+	return MsgRun{
+		Caller: caller,
+		Send:   send,
+		Package: &std.MemPackage{
+			Name:  "main",
+			Path:  RunPkgPath(caller),
+			Files: files,
+		},
+	}
+}
+
+// RunPkgPath returns the path of the ephemeral package run by caller.
+// It is a pure package path, so that the package is never finalized as
+// a realm, and it can not be added with MsgAddPackage.
+func RunPkgPath(caller crypto.Address) string {
+	return "gno.land/p/" + caller.String() + "/run"
+}
+
+// IsRunPkgPath returns true if pkgPath is the path of the ephemeral
+// package of some caller.
+func IsRunPkgPath(pkgPath string) bool {
+	parts := strings.Split(pkgPath, "/")
+	if len(parts) != 4 || parts[0] != "gno.land" || parts[1] != "p" || parts[3] != "run" {
+		return false
+	}
+	_, err := crypto.AddressFromBech32(parts[2])
+	return err == nil
+}
+
+// Implements Msg.
+func (msg MsgRun) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgRun) Type() string { return "run" }
+
+// Implements Msg.
+func (msg MsgRun) ValidateBasic() error {
+	if msg.Caller.IsZero() {
+		return std.ErrInvalidAddress("missing caller address")
+	}
+	if msg.Package == nil {
+		return ErrInvalidPkgPath("missing package")
+	}
+	if msg.Package.Path != RunPkgPath(msg.Caller) {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"invalid run package path %q, expected %q",
+			msg.Package.Path, RunPkgPath(msg.Caller)))
+	}
+	if msg.Package.Name != "main" {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"invalid run package name %q, expected \"main\"",
+			msg.Package.Name))
+	}
+	if !msg.Send.IsValid() {
+		return std.ErrTxDecode("invalid send")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgRun) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgRun) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Caller}
+}
+
+// Implements ReceiveMsg.
+func (msg MsgRun) GetReceived() std.Coins {
+	return msg.Send
+}
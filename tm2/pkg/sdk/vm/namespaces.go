@@ -0,0 +1,101 @@
+package vm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/stdlibs"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// NamesPkgPath is the path of the namespace registry realm. Once
+// deployed, packages can only be added to a namespace by the addresses
+// it allows, see checkNamespacePerm.
+const NamesPkgPath = "gno.land/r/system/names"
+
+// PkgNamespace returns the namespace of a package path, e.g. "demo" for
+// "gno.land/p/demo/avl", or "" if the path has none.
+func PkgNamespace(pkgPath string) string {
+	parts := strings.Split(pkgPath, "/")
+	if len(parts) < 3 || (parts[1] != "p" && parts[1] != "r") {
+		return ""
+	}
+	return parts[2]
+}
+
+// checkNamespacePerm returns an error if creator may not add pkgPath,
+// according to HasPerm(namespace, creator) of the registry realm.
+// The check is skipped for genesis transactions, which bootstrap the
+// registry and its reserved namespaces, and while the registry is not
+// deployed. An address may always publish under its own namespace.
+func (vm *VMKeeper) checkNamespacePerm(ctx sdk.Context, creator crypto.Address, pkgPath string) error {
+	if ctx.BlockHeight() == 0 {
+		return nil
+	}
+	store := vm.getGnoStore(ctx)
+	if pkgPath == NamesPkgPath || store.GetPackage(NamesPkgPath, false) == nil {
+		return nil
+	}
+	return vm.checkNamespaceOwner(ctx, creator, PkgNamespace(pkgPath))
+}
+
+// checkNamespaceOwner returns an error unless namespace is the address of
+// creator, or HasPerm(namespace, creator) of the registry realm is true.
+func (vm *VMKeeper) checkNamespaceOwner(ctx sdk.Context, creator crypto.Address, namespace string) (err error) {
+	if namespace == creator.String() {
+		return nil
+	}
+	store := vm.getGnoStore(ctx)
+	if store.GetPackage(NamesPkgPath, false) == nil {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"%s does not own namespace %q", creator, namespace))
+	}
+	// Evaluate HasPerm in readonly mode.
+	xx := gno.MustParseExpr(fmt.Sprintf("HasPerm(%q, %q)", namespace, creator.String()))
+	msgCtx := stdlibs.ExecContext{
+		ChainID:     ctx.ChainID(),
+		Height:      ctx.BlockHeight(),
+		Timestamp:   ctx.BlockTime().Unix(),
+		OrigPkgAddr: gno.DerivePkgAddr(NamesPkgPath).Bech32(),
+		Banker:      NewSDKBanker(vm, ctx),
+	}
+	params := vm.GetParams(ctx)
+	m := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:     NamesPkgPath,
+			Output:      os.Stdout, // XXX
+			Store:       store,
+			Context:     msgCtx,
+			Alloc:       store.GetAllocator(),
+			MaxCycles:   params.MaxCycles,
+			GasMeter:    ctx.GasMeter(),
+			GasPerCycle: params.GasPerCycle,
+			ReadOnly:    true,
+		})
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(storetypes.OutOfGasException); ok {
+				panic(r)
+			}
+			err = errors.Wrap(fmt.Errorf("%v", r), "VM namespace check panic: %v\n%s\n",
+				r, m.String())
+			return
+		}
+		m.Release()
+	}()
+	rtvs := m.Eval(xx)
+	if len(rtvs) != 1 || rtvs[0].T.Kind() != gno.BoolKind {
+		return errors.New("expected 1 bool result from %s.HasPerm", NamesPkgPath)
+	}
+	if !rtvs[0].GetBool() {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"%s may not add packages to namespace %q", creator, namespace))
+	}
+	return nil
+}
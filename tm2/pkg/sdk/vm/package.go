@@ -14,9 +14,20 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 ).WithTypes(
 	MsgCall{}, "m_call",
 	MsgAddPackage{}, "m_addpkg", // TODO rename both to MsgAddPkg?
+	MsgRun{}, "m_run",
 
 	// errors
 	InvalidPkgPathError{}, "InvalidPkgPathError",
 	InvalidStmtError{}, "InvalidStmtError",
 	InvalidExprError{}, "InvalidExprError",
+	PkgExistError{}, "PkgExistError",
+
+	// package versions
+	PackageVersion{}, "PackageVersion",
+
+	// params
+	Params{}, "Params",
+
+	// events
+	CallResultEvent{}, "CallResultEvent",
 ))
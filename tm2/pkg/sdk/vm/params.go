@@ -0,0 +1,93 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+)
+
+// Default parameter values
+const (
+	DefaultGasPerCycle int64 = 1
+	DefaultMaxCycles   int64 = 10 * 1000 * 1000 // 10M cycles
+)
+
+// Params defines the parameters for the vm module.
+type Params struct {
+	// GasPerCycle is the amount of gas consumed per GnoVM cpu cycle
+	// (see the OpCPU* constants in gnolang) for transactions.
+	GasPerCycle int64 `json:"gas_per_cycle" yaml:"gas_per_cycle"`
+	// MaxCycles bounds the cpu cycles of any single VM execution,
+	// including queries and genesis transactions which are not gas
+	// metered. 0 means no limit.
+	MaxCycles int64 `json:"max_cycles" yaml:"max_cycles"`
+	// UpgradeAuthority, if set, may publish new versions of any package
+	// besides its original creator; see SplitPkgVersion.
+	UpgradeAuthority crypto.Address `json:"upgrade_authority" yaml:"upgrade_authority"`
+}
+
+// NewParams creates a new Params object
+func NewParams(gasPerCycle, maxCycles int64) Params {
+	return Params{
+		GasPerCycle: gasPerCycle,
+		MaxCycles:   maxCycles,
+	}
+}
+
+// Equals returns a boolean determining if two Params types are identical.
+func (p Params) Equals(p2 Params) bool {
+	return amino.DeepEqual(p, p2)
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() Params {
+	return Params{
+		GasPerCycle: DefaultGasPerCycle,
+		MaxCycles:   DefaultMaxCycles,
+	}
+}
+
+// String implements the stringer interface.
+func (p Params) String() string {
+	var sb strings.Builder
+	sb.WriteString("Params: \n")
+	sb.WriteString(fmt.Sprintf("GasPerCycle: %d\n", p.GasPerCycle))
+	sb.WriteString(fmt.Sprintf("MaxCycles: %d\n", p.MaxCycles))
+	sb.WriteString(fmt.Sprintf("UpgradeAuthority: %s\n", p.UpgradeAuthority))
+	return sb.String()
+}
+
+func paramsKey() []byte {
+	return []byte("params")
+}
+
+// GetParams returns the vm params stored in the state, or the default
+// params if none were set.
+func (vm *VMKeeper) GetParams(ctx sdk.Context) Params {
+	bz := ctx.Store(vm.iavlKey).Get(paramsKey())
+	if bz == nil {
+		return DefaultParams()
+	}
+	var params Params
+	amino.MustUnmarshal(bz, &params)
+	return params
+}
+
+// SetParams stores the vm params, e.g. from the genesis state.
+func (vm *VMKeeper) SetParams(ctx sdk.Context, params Params) {
+	ctx.Store(vm.iavlKey).Set(paramsKey(), amino.MustMarshal(params))
+}
+
+// getAuthParams returns the auth params set in the context by the
+// application, or the default params; the natives verifying signatures
+// consume the same gas as the ante handler.
+func getAuthParams(ctx sdk.Context) auth.Params {
+	if params, ok := ctx.Value(auth.AuthParamsContextKey{}).(auth.Params); ok {
+		return params
+	}
+	return auth.DefaultParams()
+}
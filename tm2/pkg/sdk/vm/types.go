@@ -1,6 +1,9 @@
 package vm
 
-import "github.com/gnolang/gno/tm2/pkg/amino"
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
 
 // Public facing function signatures.
 // See convertArgToGno() for supported types.
@@ -22,3 +25,18 @@ func (fsigs FunctionSignatures) JSON() string {
 	bz := amino.MustMarshalJSON(fsigs)
 	return string(bz)
 }
+
+// CallResultEvent is emitted by MsgCall when the function returns values,
+// with their JSON encoding, see gno.TypedValuesJSON. The JSON is an event
+// rather than the result Data, which clients like gnokey print as is and
+// which remains the String() of the values, one per line; the event is
+// left out if the values can't be encoded.
+type CallResultEvent struct {
+	PkgPath string `json:"pkg_path"`
+	Func    string `json:"func"`
+	JSON    string `json:"json"`
+}
+
+var _ abci.Event = CallResultEvent{}
+
+func (CallResultEvent) AssertABCIEvent() {}
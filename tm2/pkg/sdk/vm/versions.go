@@ -0,0 +1,120 @@
+package vm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Packages are immutable, and are upgraded by publishing a new version at
+// a versioned path: "gno.land/p/demo/foo/v2" is version 2 of
+// "gno.land/p/demo/foo". Once a version of a package is published, only
+// its creator (or the UpgradeAuthority param) may publish later versions,
+// and a package may only be first published at a /vN path by the owner of
+// its namespace.
+// Each publication is recorded in the package history, see QueryHistory.
+
+// PackageVersion records the publication of a version of a package.
+type PackageVersion struct {
+	Path    string         `json:"path" yaml:"path"`
+	Version int            `json:"version" yaml:"version"`
+	Creator crypto.Address `json:"creator" yaml:"creator"`
+	Height  int64          `json:"height" yaml:"height"`
+}
+
+// PackageHistory lists the published versions of a package, oldest first.
+type PackageHistory []PackageVersion
+
+func (ph PackageHistory) JSON() string {
+	bz := amino.MustMarshalJSON(ph)
+	return string(bz)
+}
+
+// Latest returns the latest published version, or nil if none.
+func (ph PackageHistory) Latest() *PackageVersion {
+	if len(ph) == 0 {
+		return nil
+	}
+	return &ph[len(ph)-1]
+}
+
+var reVersionSuffix = regexp.MustCompile(`^(.+)/v([1-9][0-9]*)$`)
+
+// SplitPkgVersion splits a package path into its base path and version.
+// A path ending with /vN, N >= 1, is version N of the base path; any
+// other path is version 1 of itself.
+func SplitPkgVersion(pkgPath string) (base string, version int) {
+	match := reVersionSuffix.FindStringSubmatch(pkgPath)
+	if match == nil {
+		return pkgPath, 1
+	}
+	version, err := strconv.Atoi(match[2])
+	if err != nil {
+		return pkgPath, 1 // overflow
+	}
+	return match[1], version
+}
+
+func pkgHistoryKey(base string) []byte {
+	return []byte("pkghist:" + base)
+}
+
+// GetPackageHistory returns the published versions of the package at
+// pkgPath, which may be any of its versions.
+func (vm *VMKeeper) GetPackageHistory(ctx sdk.Context, pkgPath string) PackageHistory {
+	base, _ := SplitPkgVersion(pkgPath)
+	bz := ctx.Store(vm.iavlKey).Get(pkgHistoryKey(base))
+	if bz == nil {
+		return nil
+	}
+	var hist PackageHistory
+	amino.MustUnmarshal(bz, &hist)
+	return hist
+}
+
+// checkPackageVersion returns an error if creator may not publish pkgPath.
+func (vm *VMKeeper) checkPackageVersion(ctx sdk.Context, creator crypto.Address, pkgPath string) error {
+	hist := vm.GetPackageHistory(ctx, pkgPath)
+	latest := hist.Latest()
+	if latest == nil {
+		// A new package published at a /vN path, N > 1, would own the
+		// history of its base path: only the owner of the namespace may
+		// do so, besides genesis transactions.
+		base, version := SplitPkgVersion(pkgPath)
+		if version > 1 && ctx.BlockHeight() != 0 {
+			return vm.checkNamespaceOwner(ctx, creator, PkgNamespace(base))
+		}
+		return nil
+	}
+	owner := hist[0].Creator
+	authority := vm.GetParams(ctx).UpgradeAuthority
+	if creator != owner && (authority.IsZero() || creator != authority) {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"only %s may publish new versions of %s",
+			owner, hist[0].Path))
+	}
+	if _, version := SplitPkgVersion(pkgPath); version <= latest.Version {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"version %d of %s must be greater than the latest version %d (%s)",
+			version, pkgPath, latest.Version, latest.Path))
+	}
+	return nil
+}
+
+// addPackageVersion records the publication of pkgPath by creator.
+func (vm *VMKeeper) addPackageVersion(ctx sdk.Context, creator crypto.Address, pkgPath string) {
+	hist := vm.GetPackageHistory(ctx, pkgPath)
+	base, version := SplitPkgVersion(pkgPath)
+	hist = append(hist, PackageVersion{
+		Path:    pkgPath,
+		Version: version,
+		Creator: creator,
+		Height:  ctx.BlockHeight(),
+	})
+	ctx.Store(vm.iavlKey).Set(pkgHistoryKey(base), amino.MustMarshal(hist))
+}
@@ -63,7 +63,7 @@ func (coin Coin) String() string {
 // validate returns an error if the Coin has a negative amount or if
 // the denom is invalid.
 func validate(denom string, amount int64) error {
-	if err := validateDenom(denom); err != nil {
+	if err := ValidateDenom(denom); err != nil {
 		return err
 	}
 
@@ -229,7 +229,7 @@ func (coins Coins) IsValid() bool {
 	case 0:
 		return true
 	case 1:
-		if err := validateDenom(coins[0].Denom); err != nil {
+		if err := ValidateDenom(coins[0].Denom); err != nil {
 			return false
 		}
 		return coins[0].IsPositive()
@@ -616,8 +616,9 @@ func (coins Coins) Sort() Coins {
 // Parsing
 
 var (
-	// Denominations can be 3 ~ 16 characters long.
-	reDnmString = `[a-z][a-z0-9]{2,15}`
+	// Denominations can be 3 ~ 16 characters long, optionally prefixed
+	// with the package path of their issuer, e.g. gno.land/r/foo:token.
+	reDnmString = `(?:[a-z][a-z0-9_./-]*:)?[a-z][a-z0-9]{2,15}`
 	reAmt       = `[[:digit:]]+`
 	reDecAmt    = `[[:digit:]]*\.[[:digit:]]+`
 	reSpc       = `[[:space:]]*`
@@ -626,7 +627,8 @@ var (
 	reDecCoin   = regexp.MustCompile(fmt.Sprintf(`^(%s)%s(%s)$`, reDecAmt, reSpc, reDnmString))
 )
 
-func validateDenom(denom string) error {
+// ValidateDenom returns an error if denom is not a valid denomination.
+func ValidateDenom(denom string) error {
 	if !reDnm.MatchString(denom) {
 		return fmt.Errorf("invalid denom: %s", denom)
 	}
@@ -634,11 +636,22 @@ func validateDenom(denom string) error {
 }
 
 func mustValidateDenom(denom string) {
-	if err := validateDenom(denom); err != nil {
+	if err := ValidateDenom(denom); err != nil {
 		panic(err)
 	}
 }
 
+// DenomIssuer returns the package path of the issuer of denom, i.e. its
+// prefix before ":" like gno.land/r/foo for gno.land/r/foo:token, or "" if
+// denom has no issuer.
+func DenomIssuer(denom string) string {
+	i := strings.LastIndexByte(denom, ':')
+	if i < 0 {
+		return ""
+	}
+	return denom[:i]
+}
+
 func MustParseCoin(coinStr string) Coin {
 	coin, err := ParseCoin(coinStr)
 	if err != nil {
@@ -664,7 +677,7 @@ func ParseCoin(coinStr string) (coin Coin, err error) {
 		return Coin{}, errors.Wrap(err, "failed to parse coin amount: %s", amountStr)
 	}
 
-	if err := validateDenom(denomStr); err != nil {
+	if err := ValidateDenom(denomStr); err != nil {
 		return Coin{}, fmt.Errorf("invalid denom cannot contain upper case characters or spaces: %w", err)
 	}
 
@@ -58,6 +58,10 @@ func TestCoinIsValid(t *testing.T) {
 		{Coin{"a very long coin denom", int64(1)}, false},
 		{Coin{"atOm", int64(1)}, false},
 		{Coin{"     ", int64(1)}, false},
+		{Coin{"gno.land/r/foo:atom", int64(1)}, true},
+		{Coin{"gno.land/r/foo:", int64(1)}, false},
+		{Coin{":atom", int64(1)}, false},
+		{Coin{"gno.land/r/foo:bar:atom", int64(1)}, false},
 	}
 
 	for i, tc := range cases {
@@ -65,6 +69,11 @@ func TestCoinIsValid(t *testing.T) {
 	}
 }
 
+func TestDenomIssuer(t *testing.T) {
+	require.Equal(t, "", DenomIssuer(testDenom1))
+	require.Equal(t, "gno.land/r/foo", DenomIssuer("gno.land/r/foo:atom"))
+}
+
 func TestAddCoin(t *testing.T) {
 	cases := []struct {
 		inputOne    Coin
@@ -400,6 +409,7 @@ func TestParse(t *testing.T) {
 		{"11me coin, 12you coin", false, nil}, // no spaces in coin names
 		{"1.2btc", false, nil},                // amount must be integer
 		{"5foo-bar", false, nil},              // once more, only letters in coin name
+		{"3gno.land/r/foo:bar,1foo", true, Coins{{"foo", one}, {"gno.land/r/foo:bar", int64(3)}}},
 	}
 
 	for tcIndex, tc := range cases {